@@ -23,6 +23,8 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 
+	cmdUtil "github.com/openebs/upgrade/cmd/util"
+	"github.com/openebs/upgrade/pkg/metrics"
 	cstor "github.com/openebs/upgrade/pkg/migrate/cstor"
 
 	"github.com/pkg/errors"
@@ -45,9 +47,9 @@ func NewMigratePoolJob() *cobra.Command {
 		Long:    cstorSPCMigrateCmdHelpText,
 		Example: `migrate cstor-spc --spc-name <spc-name>`,
 		Run: func(cmd *cobra.Command, args []string) {
-			util.CheckErr(options.RunPreFlightChecks(), util.Fatal)
-			util.CheckErr(options.RunCStorSPCMigrateChecks(), util.Fatal)
-			util.CheckErr(options.RunCStorSPCMigrate(), util.Fatal)
+			util.CheckErr(options.RunPreFlightChecks(), cmdUtil.Fatal)
+			util.CheckErr(options.RunCStorSPCMigrateChecks(), cmdUtil.Fatal)
+			util.CheckErr(options.RunCStorSPCMigrate(), cmdUtil.Fatal)
 		},
 	}
 
@@ -74,15 +76,25 @@ func (m *MigrateOptions) RunCStorSPCMigrateChecks() error {
 }
 
 // RunCStorSPCMigrate migrates the given spc.
-func (m *MigrateOptions) RunCStorSPCMigrate() error {
+func (m *MigrateOptions) RunCStorSPCMigrate() (err error) {
+	metrics.AddPending(metrics.Migrate, "cstorPool", 1)
+	defer func() {
+		metrics.ResourceDone(metrics.Migrate, "cstorPool", err)
+	}()
 
 	klog.Infof("Migrating spc %s to cspc", m.spcName)
-	migrator := cstor.CSPCMigrator{}
+	migrator := cstor.CSPCMigrator{
+		KubeClientset:    m.clientset.KubeClientset,
+		OpenebsClientset: m.clientset.OpenebsClientset,
+		Maya:             m.clientset.Maya,
+		Recorder:         m.clientset.Recorder,
+	}
 	if m.cspcName != "" {
 		klog.Infof("using custom cspc name as %s", m.cspcName)
 		migrator.SetCSPCName(m.cspcName)
 	}
-	err := migrator.Migrate(m.spcName, m.openebsNamespace)
+	err = migrator.Migrate(m.spcName, m.openebsNamespace)
+	m.addToReport("cstorPool", m.spcName, err)
 	if err != nil {
 		klog.Error(err)
 		return errors.Errorf("Failed to migrate cStor SPC : %s", m.spcName)
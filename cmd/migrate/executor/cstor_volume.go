@@ -23,6 +23,8 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 
+	cmdUtil "github.com/openebs/upgrade/cmd/util"
+	"github.com/openebs/upgrade/pkg/metrics"
 	cstor "github.com/openebs/upgrade/pkg/migrate/cstor"
 
 	"github.com/pkg/errors"
@@ -45,9 +47,9 @@ func NewMigrateCStorVolumeJob() *cobra.Command {
 		Long:    cstorVolumeMigrateCmdHelpText,
 		Example: `migrate cstor-volume <pv-name>`,
 		Run: func(cmd *cobra.Command, args []string) {
-			util.CheckErr(options.RunPreFlightChecks(), util.Fatal)
-			util.CheckErr(options.RunCStorVolumeMigrateChecks(), util.Fatal)
-			util.CheckErr(options.RunCStorVolumeMigrate(), util.Fatal)
+			util.CheckErr(options.RunPreFlightChecks(), cmdUtil.Fatal)
+			util.CheckErr(options.RunCStorVolumeMigrateChecks(), cmdUtil.Fatal)
+			util.CheckErr(options.RunCStorVolumeMigrate(), cmdUtil.Fatal)
 		},
 	}
 
@@ -69,11 +71,22 @@ func (m *MigrateOptions) RunCStorVolumeMigrateChecks() error {
 }
 
 // RunCStorVolumeMigrate migrates the given pv.
-func (m *MigrateOptions) RunCStorVolumeMigrate() error {
+func (m *MigrateOptions) RunCStorVolumeMigrate() (err error) {
+	metrics.AddPending(metrics.Migrate, "cstorVolume", 1)
+	defer func() {
+		metrics.ResourceDone(metrics.Migrate, "cstorVolume", err)
+	}()
 
 	klog.Infof("Migrating volume %s to csi spec", m.pvName)
-	migrator := cstor.VolumeMigrator{}
-	err := migrator.Migrate(m.pvName, m.openebsNamespace)
+	migrator := cstor.VolumeMigrator{
+		KubeClientset:    m.clientset.KubeClientset,
+		OpenebsClientset: m.clientset.OpenebsClientset,
+		SnapClientset:    m.clientset.SnapClientset,
+		Maya:             m.clientset.Maya,
+		Recorder:         m.clientset.Recorder,
+	}
+	err = migrator.Migrate(m.pvName, m.openebsNamespace)
+	m.addToReport("cstorVolume", m.pvName, err)
 	if err != nil {
 		klog.Error(err)
 		return errors.Errorf("Failed to migrate cStor Volume : %s", m.pvName)
@@ -0,0 +1,219 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	mutil "github.com/openebs/maya/pkg/util"
+	errors "github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openebs/upgrade/cmd/util"
+	"github.com/openebs/upgrade/pkg/generate"
+	"github.com/openebs/upgrade/pkg/kubeclient"
+	migrate "github.com/openebs/upgrade/pkg/migrate/cstor"
+)
+
+var (
+	generateCmdHelpText = `
+This command prints the manifests of the jobs migrating the given
+resources, or all the resources of the kind which are not migrated,
+with the service account and the cluster role they run with. The
+image is the migrate image of the version of the operator managing
+the migrated resources. The output is sorted so that the same
+cluster always gives the same manifests.
+
+Supported kinds: cstor-spc, cstor-volume
+
+Usage: migrate generate <kind> [<name>...]
+`
+)
+
+// generateKind holds the details of a kind of resource
+// the manifests can be generated for
+type generateKind struct {
+	// taskKind is the kind of the MigrationTask
+	taskKind string
+	// operator is the component name of the operator
+	// the migrate image should match
+	operator string
+	// nameFlag is the flag of the migrate
+	// command taking the resource name
+	nameFlag string
+	list     func(*kubeclient.MayaClients) ([]string, error)
+}
+
+var (
+	generateKinds = map[string]generateKind{
+		"cstor-spc": {
+			taskKind: "cstorPool",
+			operator: "cspc-operator",
+			nameFlag: "--spc-name",
+			list:     migrate.PendingSPCs,
+		},
+		"cstor-volume": {
+			taskKind: "cstorVolume",
+			operator: "cvc-operator",
+			nameFlag: "--pv-name",
+			list:     migrate.PendingVolumes,
+		},
+	}
+)
+
+// GenerateOptions stores information required to generate the manifests
+type GenerateOptions struct {
+	tasks          bool
+	serviceAccount string
+	backoffLimit   int
+}
+
+var (
+	generateOptions = &GenerateOptions{
+		serviceAccount: "openebs-migrate",
+		backoffLimit:   migrate.DefaultMaxRetries,
+	}
+)
+
+// NewGenerateCmd prints the manifests of the
+// jobs migrating the given kind of resources
+func NewGenerateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "generate",
+		Short:   "Print the job and rbac manifests migrating the resources",
+		Long:    generateCmdHelpText,
+		Example: `migrate generate cstor-volume > migrate.yaml`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 {
+				util.Fatal("failed to generate: resource kind is required")
+			}
+			kind, ok := generateKinds[args[0]]
+			if !ok {
+				util.Fatal("failed to generate: invalid resource kind " + args[0])
+			}
+			mutil.CheckErr(options.RunGenerateChecks(), util.Fatal)
+			mutil.CheckErr(options.RunGenerate(args[0], kind, args[1:]), util.Fatal)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&generateOptions.tasks,
+		"tasks", "",
+		generateOptions.tasks,
+		"[optional] generate a MigrationTask for every resource and a job processing it with the resource command.")
+
+	cmd.Flags().StringVarP(&generateOptions.serviceAccount,
+		"service-account", "",
+		generateOptions.serviceAccount,
+		"[optional] name of the service account, cluster role and cluster role binding run by the jobs.")
+
+	cmd.Flags().IntVarP(&generateOptions.backoffLimit,
+		"backoff-limit", "",
+		generateOptions.backoffLimit,
+		"[optional] backoffLimit of the jobs, the resource command marks the task as failed once it is reached.")
+
+	return cmd
+}
+
+// RunGenerateChecks will ensure the sanity of the generate options
+func (m *MigrateOptions) RunGenerateChecks() error {
+	if len(strings.TrimSpace(m.openebsNamespace)) == 0 {
+		return errors.Errorf("Cannot generate manifests: openebs namespace is missing")
+	}
+	if len(strings.TrimSpace(generateOptions.serviceAccount)) == 0 {
+		return errors.Errorf("Cannot generate manifests: service account is missing")
+	}
+	if generateOptions.backoffLimit < 1 {
+		return errors.Errorf("Cannot generate manifests: backoff-limit should be at least 1")
+	}
+	return nil
+}
+
+// RunGenerate prints the manifests migrating the given resources
+func (m *MigrateOptions) RunGenerate(cmdKind string, kind generateKind, names []string) error {
+	objs, err := m.generateManifests(cmdKind, kind, names)
+	if err != nil {
+		return errors.Wrap(err, "Cannot generate manifests")
+	}
+	return generate.Write(os.Stdout, objs)
+}
+
+// generateManifests returns the rbac objects followed by a job, and
+// a MigrationTask when requested, for every resource to be migrated
+func (m *MigrateOptions) generateManifests(cmdKind string, kind generateKind,
+	names []string) ([]interface{}, error) {
+	operator, err := generate.GetOperator(m.clientset.KubeClientset, m.openebsNamespace, kind.operator)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		names, err = kind.list(m.clientset.Maya)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(names) == 0 {
+		return nil, errors.Errorf("no %s to migrate", cmdKind)
+	}
+	sort.Strings(names)
+	o := generate.Options{
+		Operation:      "migrate",
+		Namespace:      m.openebsNamespace,
+		ServiceAccount: generateOptions.serviceAccount,
+		Image:          operator.ImagePrefix + "migrate:" + operator.Version,
+		BackoffLimit:   int32(generateOptions.backoffLimit),
+	}
+	objs := generate.RBAC(o)
+	for _, name := range names {
+		if !generateOptions.tasks {
+			objs = append(objs, generate.NewJob(o, generate.Job{
+				Name: generate.Name("migrate", cmdKind, name),
+				Args: []string{cmdKind, kind.nameFlag + "=" + name, "--v=4"},
+			}))
+			continue
+		}
+		taskName := migrate.MigrationTaskName(kind.taskKind, name)
+		mtaskObj := &v1Alpha1API.MigrationTask{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1Alpha1API.SchemeGroupVersion.String(),
+				Kind:       "MigrationTask",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      taskName,
+				Namespace: m.openebsNamespace,
+			},
+		}
+		switch kind.taskKind {
+		case "cstorPool":
+			mtaskObj.Spec.MigrateCStorPool = &v1Alpha1API.MigrateCStorPool{SPCName: name}
+		case "cstorVolume":
+			mtaskObj.Spec.MigrateCStorVolume = &v1Alpha1API.MigrateCStorVolume{PVName: name}
+		}
+		objs = append(objs, mtaskObj, generate.NewJob(o, generate.Job{
+			Name: generate.Name(taskName),
+			Args: []string{
+				"resource",
+				taskName,
+				"--v=4",
+			},
+		}))
+	}
+	return objs, nil
+}
@@ -20,6 +20,14 @@ import (
 	"strings"
 
 	errors "github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/openebs/upgrade/cmd/util"
+	"github.com/openebs/upgrade/pkg/events"
+	"github.com/openebs/upgrade/pkg/kubeclient"
+	"github.com/openebs/upgrade/pkg/metrics"
+	"github.com/openebs/upgrade/pkg/report"
+	"github.com/openebs/upgrade/pkg/version"
 )
 
 // MigrateOptions stores information required for migration of
@@ -30,6 +38,22 @@ type MigrateOptions struct {
 	cspcName         string
 	pvName           string
 	resourceKind     string
+	clientOptions    kubeclient.Options
+	clientset        *kubeclient.Clientset
+	// policyFile and policyConfigMap are the sources of the
+	// policy with the oldest version to migrate to
+	policyFile      string
+	policyConfigMap string
+	policy          version.Policy
+	// metricsAddr and metricsPushgateway are the address the
+	// metrics are served on and the Pushgateway they are pushed to
+	metricsAddr        string
+	metricsPushgateway string
+	// output is the format of the report of the run,
+	// written to the reportFile if set or to stdout
+	output     string
+	reportFile string
+	report     *report.Report
 }
 
 var (
@@ -38,10 +62,62 @@ var (
 	}
 )
 
+// InitializeClients builds the clients used by the migrate job
+// from the kubeconfig options
+func (m *MigrateOptions) InitializeClients() error {
+	if m.clientset != nil {
+		return nil
+	}
+	clientset, err := kubeclient.New(&m.clientOptions)
+	if err != nil {
+		return errors.Wrap(err, "Cannot execute migrate job")
+	}
+	clientset.Recorder = events.NewRecorder(clientset.KubeClientset,
+		kubeclient.Scheme(), "openebs-migrate")
+	m.clientset = clientset
+	return nil
+}
+
+// InitializePolicy loads the policy validating the migration,
+// the default policy is used when none is given
+func (m *MigrateOptions) InitializePolicy() error {
+	policy, err := util.LoadPolicy(m.clientset.KubeClientset, m.openebsNamespace,
+		m.policyFile, m.policyConfigMap)
+	if err != nil {
+		return errors.Wrap(err, "Cannot execute migrate job")
+	}
+	m.policy = policy
+	return nil
+}
+
+// InitializeMetrics serves the metrics of the job and
+// pushes them to the Pushgateway when the job exits
+func (m *MigrateOptions) InitializeMetrics() error {
+	if m.metricsAddr != "" {
+		err := metrics.Serve(m.metricsAddr)
+		if err != nil {
+			return errors.Wrap(err, "Cannot execute migrate job")
+		}
+	}
+	if m.metricsPushgateway != "" {
+		url := m.metricsPushgateway
+		util.OnExit(func() {
+			err := metrics.Push(url, "openebs-migrate")
+			if err != nil {
+				klog.Error(err)
+			}
+		})
+	}
+	return nil
+}
+
 // RunPreFlightChecks will ensure the sanity of the common migrate options
 func (m *MigrateOptions) RunPreFlightChecks() error {
 	if len(strings.TrimSpace(m.openebsNamespace)) == 0 {
 		return errors.Errorf("Cannot execute migrate job: openebs namespace is missing")
 	}
+	if err := m.policy.ValidateMigrationTarget(); err != nil {
+		return errors.Wrap(err, "Cannot execute migrate job")
+	}
 	return nil
 }
@@ -0,0 +1,94 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/openebs/upgrade/cmd/util"
+	migrate "github.com/openebs/upgrade/pkg/migrate/cstor"
+	"github.com/openebs/upgrade/pkg/report"
+	"github.com/openebs/upgrade/pkg/version"
+)
+
+// InitializeReport sets up the report of the run when an output
+// format or a report file is given, it is written when the job exits
+func (m *MigrateOptions) InitializeReport() error {
+	if m.output == "" && m.reportFile == "" {
+		return nil
+	}
+	if m.output == "" {
+		m.output = report.FormatJSON
+	}
+	err := report.ValidateFormat(m.output)
+	if err != nil {
+		return errors.Wrap(err, "Cannot execute migrate job")
+	}
+	m.report = report.New("migrate")
+	util.OnExit(func() {
+		err := m.writeReport()
+		if err != nil {
+			klog.Errorf("failed to write the report: %v", err)
+		}
+	})
+	return nil
+}
+
+// writeReport writes the report to the report file or to stdout
+func (m *MigrateOptions) writeReport() error {
+	var w io.Writer = os.Stdout
+	if m.reportFile != "" {
+		f, err := os.Create(m.reportFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return m.report.Write(w, m.output)
+}
+
+// addToReport adds the result of the migration of the given resource
+// to the report, the steps are read from its MigrationTask
+func (m *MigrateOptions) addToReport(kind, name string, err error) {
+	if m.report == nil {
+		return
+	}
+	res := report.Resource{
+		Kind:      kind,
+		Name:      name,
+		ToVersion: version.Current(),
+		Phase:     report.PhaseSucceeded,
+	}
+	if err != nil {
+		res.Phase = report.PhaseFailed
+		res.Error = err.Error()
+	}
+	mtaskObj, gerr := m.clientset.OpenebsClientset.OpenebsV1alpha1().
+		MigrationTasks(m.openebsNamespace).
+		Get(context.TODO(), migrate.MigrationTaskName(kind, name), metav1.GetOptions{})
+	if gerr == nil {
+		res.Steps = report.MigrationSteps(mtaskObj)
+	}
+	m.report.Add(res)
+}
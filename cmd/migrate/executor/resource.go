@@ -21,10 +21,7 @@ import (
 	"os"
 	"strings"
 
-	"k8s.io/client-go/rest"
-
 	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
-	openebsclientset "github.com/openebs/api/v3/pkg/client/clientset/versioned"
 	"github.com/openebs/maya/pkg/util"
 	cmdUtil "github.com/openebs/upgrade/cmd/util"
 	migrate "github.com/openebs/upgrade/pkg/migrate/cstor"
@@ -56,26 +53,28 @@ func NewMigrateResourceJob() *cobra.Command {
 		Long:    resourceMigrateCmdHelpText,
 		Example: `migrate resource`,
 		Run: func(cmd *cobra.Command, args []string) {
-			client, err := initClient()
-			util.CheckErr(err, util.Fatal)
+			client := options.clientset.OpenebsClientset
 			name := args[0]
-			openebsNamespace := cmdUtil.GetOpenEBSNamespace()
+			openebsNamespace := options.openebsNamespace
 			migrationTaskObj, err := client.OpenebsV1alpha1().
 				MigrationTasks(openebsNamespace).
 				Get(context.TODO(), name, metav1.GetOptions{})
-			util.CheckErr(err, util.Fatal)
-			util.CheckErr(options.InitializeFromMigrationTaskResource(migrationTaskObj), util.Fatal)
-			util.CheckErr(options.RunPreFlightChecks(), util.Fatal)
+			util.CheckErr(err, cmdUtil.Fatal)
+			util.CheckErr(options.InitializeFromMigrationTaskResource(migrationTaskObj), cmdUtil.Fatal)
+			util.CheckErr(options.RunPreFlightChecks(), cmdUtil.Fatal)
+			// the migration is not started outside of its maintenance window
+			util.CheckErr(migrate.WaitForMaintenanceWindow(context.TODO(), migrationTaskObj, client),
+				cmdUtil.Fatal)
 			err = options.RunResourceMigrate()
 			if err != nil {
 				migrationTaskObj, uerr := client.OpenebsV1alpha1().MigrationTasks(openebsNamespace).
 					Get(context.TODO(), name, metav1.GetOptions{})
 				if uerr != nil {
-					util.Fatal(uerr.Error())
+					cmdUtil.Fatal(uerr.Error())
 				}
-				backoffLimit, uerr := getBackoffLimit(openebsNamespace)
+				backoffLimit, uerr := getBackoffLimit(openebsNamespace, options.clientset.KubeClientset)
 				if uerr != nil {
-					util.Fatal(uerr.Error())
+					cmdUtil.Fatal(uerr.Error())
 				}
 				migrationTaskObj.Status.Retries = migrationTaskObj.Status.Retries + 1
 				if migrationTaskObj.Status.Retries == backoffLimit {
@@ -85,21 +84,21 @@ func NewMigrateResourceJob() *cobra.Command {
 				_, uerr = client.OpenebsV1alpha1().MigrationTasks(openebsNamespace).
 					Update(context.TODO(), migrationTaskObj, metav1.UpdateOptions{})
 				if uerr != nil {
-					util.Fatal(uerr.Error())
+					cmdUtil.Fatal(uerr.Error())
 				}
-				util.Fatal(err.Error())
+				cmdUtil.Fatal(err.Error())
 			} else {
 				migrationTaskObj, uerr := client.OpenebsV1alpha1().MigrationTasks(openebsNamespace).
 					Get(context.TODO(), name, metav1.GetOptions{})
 				if uerr != nil {
-					util.Fatal(uerr.Error())
+					cmdUtil.Fatal(uerr.Error())
 				}
 				migrationTaskObj.Status.Phase = v1Alpha1API.MigrateSuccess
 				migrationTaskObj.Status.CompletedTime = metav1.Now()
 				_, uerr = client.OpenebsV1alpha1().MigrationTasks(openebsNamespace).
 					Update(context.TODO(), migrationTaskObj, metav1.UpdateOptions{})
 				if uerr != nil {
-					util.Fatal(uerr.Error())
+					cmdUtil.Fatal(uerr.Error())
 				}
 			}
 		},
@@ -142,27 +141,7 @@ func (m *MigrateOptions) RunResourceMigrate() error {
 	return err
 }
 
-func initClient() (openebsclientset.Interface, error) {
-	cfg, err := rest.InClusterConfig()
-	if err != nil {
-		return nil, errors.Wrap(err, "error building kubeconfig")
-	}
-	client, err := openebsclientset.NewForConfig(cfg)
-	if err != nil {
-		return nil, errors.Wrap(err, "error building openebs clientset")
-	}
-	return client, nil
-}
-
-func getBackoffLimit(openebsNamespace string) (int, error) {
-	cfg, err := rest.InClusterConfig()
-	if err != nil {
-		return 0, errors.Wrap(err, "error building kubeconfig")
-	}
-	client, err := kubernetes.NewForConfig(cfg)
-	if err != nil {
-		return 0, errors.Wrap(err, "error building openebs clientset")
-	}
+func getBackoffLimit(openebsNamespace string, client kubernetes.Interface) (int, error) {
 	podName := os.Getenv("POD_NAME")
 	podObj, err := client.CoreV1().Pods(openebsNamespace).
 		Get(context.TODO(), podName, metav1.GetOptions{})
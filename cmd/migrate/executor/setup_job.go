@@ -20,6 +20,7 @@ import (
 	"flag"
 	"strings"
 
+	mutil "github.com/openebs/maya/pkg/util"
 	"github.com/openebs/upgrade/cmd/util"
 	"github.com/spf13/cobra"
 )
@@ -39,6 +40,7 @@ func NewJob() *cobra.Command {
 		NewMigratePoolJob(),
 		NewMigrateCStorVolumeJob(),
 		NewMigrateResourceJob(),
+		NewGenerateCmd(),
 	)
 
 	cmd.PersistentFlags().StringVarP(&options.openebsNamespace,
@@ -46,6 +48,56 @@ func NewJob() *cobra.Command {
 		options.openebsNamespace,
 		"namespace where openebs components are installed.")
 
+	cmd.PersistentFlags().StringVarP(&options.clientOptions.KubeConfig,
+		"kubeconfig", "",
+		options.clientOptions.KubeConfig,
+		"[optional] path to the kubeconfig file. If not specified, in-cluster config will be used")
+
+	cmd.PersistentFlags().StringVarP(&options.clientOptions.Context,
+		"context", "",
+		options.clientOptions.Context,
+		"[optional] name of the kubeconfig context to use.")
+
+	cmd.PersistentFlags().StringVarP(&options.clientOptions.Impersonate,
+		"as", "",
+		options.clientOptions.Impersonate,
+		"[optional] username to impersonate for the operation.")
+
+	cmd.PersistentFlags().StringSliceVarP(&options.clientOptions.ImpersonateGroups,
+		"as-group", "",
+		options.clientOptions.ImpersonateGroups,
+		"[optional] group to impersonate for the operation, this flag can be repeated to specify multiple groups.")
+
+	cmd.PersistentFlags().StringVarP(&options.policyFile,
+		"upgrade-policy-file", "",
+		options.policyFile,
+		"[optional] yaml file with the upgrade policy, replacing the default policy.")
+
+	cmd.PersistentFlags().StringVarP(&options.policyConfigMap,
+		"upgrade-policy-configmap", "",
+		options.policyConfigMap,
+		"[optional] configmap in the openebs namespace with the upgrade policy under the "+util.PolicyConfigMapKey+" key.")
+
+	cmd.PersistentFlags().StringVarP(&options.metricsAddr,
+		"metrics-addr", "",
+		options.metricsAddr,
+		"[optional] address to serve the prometheus metrics on, like :9500. The metrics are not served if not specified.")
+
+	cmd.PersistentFlags().StringVarP(&options.metricsPushgateway,
+		"metrics-pushgateway", "",
+		options.metricsPushgateway,
+		"[optional] url of a Pushgateway to push the prometheus metrics to when the job exits.")
+
+	cmd.PersistentFlags().StringVarP(&options.output,
+		"output", "o",
+		options.output,
+		"[optional] print a report of the run when the job exits in the given format, json or yaml.")
+
+	cmd.PersistentFlags().StringVarP(&options.reportFile,
+		"report-file", "",
+		options.reportFile,
+		"[optional] write the report of the run to the given file instead of stdout, in json if no output format is given.")
+
 	cmd.PersistentFlags().AddGoFlagSet(flag.CommandLine)
 
 	// Hack: Without the following line, the logs will be prefixed with Error
@@ -60,4 +112,8 @@ func PreRun(cmd *cobra.Command, args []string) {
 	if len(strings.TrimSpace(namespace)) != 0 {
 		options.openebsNamespace = namespace
 	}
+	mutil.CheckErr(options.InitializeClients(), mutil.Fatal)
+	mutil.CheckErr(options.InitializePolicy(), mutil.Fatal)
+	mutil.CheckErr(options.InitializeMetrics(), mutil.Fatal)
+	mutil.CheckErr(options.InitializeReport(), mutil.Fatal)
 }
@@ -28,5 +28,6 @@ func main() {
 	defer mlogger.FlushLogs()
 
 	err := executor.NewJob().Execute()
+	util.RunExitHandlers()
 	util.CheckError(err)
 }
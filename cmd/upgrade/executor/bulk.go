@@ -0,0 +1,244 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"github.com/openebs/upgrade/pkg/report"
+	upgrade "github.com/openebs/upgrade/pkg/upgrade"
+	"github.com/openebs/upgrade/pkg/upgrade/upgrader"
+)
+
+const (
+	resultSucceeded = "Succeeded"
+	resultSkipped   = "Skipped"
+	resultFailed    = "Failed"
+	resultCancelled = "Cancelled"
+)
+
+// bulkResult is the outcome of upgrading a selected volume
+type bulkResult struct {
+	name    string
+	version string
+	result  string
+	reason  string
+}
+
+// addVolumeSelectorFlags adds the flags used to select the volumes
+// to be upgraded instead of passing the names as arguments, and
+// the number of volumes to be upgraded at once
+func addVolumeSelectorFlags(cmd *cobra.Command, withCSPC bool) {
+	cmd.Flags().BoolVarP(&options.volumeSelector.All,
+		"all", "",
+		options.volumeSelector.All,
+		"[optional] upgrade all the volumes.")
+	if withCSPC {
+		cmd.Flags().StringVarP(&options.volumeSelector.CSPC,
+			"cspc", "",
+			options.volumeSelector.CSPC,
+			"[optional] upgrade the volumes having replicas on the given cspc.")
+	}
+	cmd.Flags().StringVarP(&options.volumeSelector.StorageClass,
+		"storageclass", "",
+		options.volumeSelector.StorageClass,
+		"[optional] upgrade the volumes provisioned using the given storageclass.")
+	cmd.Flags().StringVarP(&options.volumeSelector.PVCNamespace,
+		"pvc-namespace", "",
+		options.volumeSelector.PVCNamespace,
+		"[optional] upgrade the volumes bound to the pvcs in the given namespace.")
+	cmd.Flags().StringVarP(&options.volumeSelector.LabelSelector,
+		"label-selector", "l",
+		options.volumeSelector.LabelSelector,
+		"[optional] upgrade the volumes matching the given label selector.")
+	cmd.Flags().IntVarP(&options.parallelism,
+		"parallelism", "",
+		options.parallelism,
+		"[optional] number of volumes to upgrade at once, volumes with targets on the same node or replicas on the same rolling pool are never upgraded together.")
+}
+
+// RunBulkUpgrade upgrades all the given volumes, or the volumes matching
+// the selector if no names are given, and prints a summary once all of
+// them are processed. Up to parallelism volumes are upgraded at once.
+func (u *UpgradeOptions) RunBulkUpgrade(cmd *cobra.Command, names []string,
+	run func(cmd *cobra.Command, name string) error) error {
+	volumes, err := u.selectVolumes(names)
+	if err != nil {
+		return err
+	}
+	if len(volumes) == 0 {
+		klog.Infof("No %s found matching the selector", u.resourceKind)
+		return nil
+	}
+	results := make([]bulkResult, len(volumes))
+	scheduled := []upgrade.ScheduledVolume{}
+	index := []int{}
+	for i, v := range volumes {
+		results[i] = bulkResult{
+			name:    v.Name,
+			version: v.Version,
+		}
+		if v.SkipReason != "" {
+			klog.Infof("Skipping %s: %s", v.Name, v.SkipReason)
+			results[i].result = resultSkipped
+			results[i].reason = v.SkipReason
+			u.addToReport(report.Resource{
+				Name:        v.Name,
+				FromVersion: v.Version,
+				Phase:       report.PhaseSkipped,
+				Reason:      v.SkipReason,
+			})
+			continue
+		}
+		scheduled = append(scheduled, upgrade.ScheduledVolume{Name: v.Name})
+		index = append(index, i)
+	}
+	u.addPending(u.resourceKind, len(scheduled))
+	var locksOf func(name string) ([]string, error)
+	if u.parallelism > 1 {
+		// a volume whose locks cannot be read is not upgraded
+		versions := map[string]string{}
+		for _, v := range volumes {
+			versions[v.Name] = v.Version
+		}
+		locksOf = func(name string) ([]string, error) {
+			locks, err := u.volumeLocks(name)
+			if err != nil {
+				u.addToReport(report.Resource{
+					Name:        name,
+					FromVersion: versions[name],
+					Phase:       report.PhaseFailed,
+					Error:       err.Error(),
+				})
+				u.resourceDone(err)
+			}
+			return locks, err
+		}
+	}
+	errs := upgrade.Schedule(scheduled, u.parallelism, locksOf, func(name string) error {
+		return run(cmd, name)
+	})
+	for j, i := range index {
+		if upgrader.IsCancelled(errs[j]) {
+			results[i].result = resultCancelled
+			results[i].reason = errs[j].Error()
+		} else if errs[j] != nil {
+			results[i].result = resultFailed
+			results[i].reason = errs[j].Error()
+		} else {
+			results[i].result = resultSucceeded
+		}
+	}
+	err = printBulkSummary(u.humanOutput(), results)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range results {
+		if r.result == resultFailed {
+			failed++
+		}
+	}
+	if failed != 0 {
+		return errors.Errorf("Failed to upgrade %d of %d volumes", failed, len(volumes))
+	}
+	return nil
+}
+
+// volumeLocks returns the locks of the given volume when it is
+// about to be upgraded, the pools rolling are read every time
+func (u *UpgradeOptions) volumeLocks(name string) ([]string, error) {
+	var rolling map[string]bool
+	if u.resourceKind == "cstorVolume" {
+		var err error
+		rolling, err = upgrade.RollingCSPIs(u.openebsNamespace, u.clientset)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return upgrade.VolumeLocks(u.resourceKind, name, u.openebsNamespace, rolling, u.clientset)
+}
+
+// selectVolumes returns the given volumes, or the volumes
+// matching the selector if no names are given
+func (u *UpgradeOptions) selectVolumes(names []string) ([]upgrade.SelectedVolume, error) {
+	if len(names) == 0 {
+		to := u.toVersion
+		if len(strings.TrimSpace(to)) == 0 {
+			var err error
+			to, err = upgrade.OperatorVersion(u.resourceKind, u.openebsNamespace, u.clientset)
+			if err != nil {
+				return nil, errors.Wrap(err, "Cannot execute upgrade job: failed to infer the to-version")
+			}
+		}
+		volumes, err := upgrade.SelectVolumes(u.resourceKind, u.openebsNamespace,
+			to, u.volumeSelector, u.clientset)
+		if err != nil {
+			return nil, errors.Wrap(err, "Cannot execute upgrade job")
+		}
+		return volumes, nil
+	}
+	volumes := []upgrade.SelectedVolume{}
+	for _, name := range names {
+		v := upgrade.SelectedVolume{
+			Name:    name,
+			Version: u.fromVersion,
+		}
+		if len(strings.TrimSpace(v.Version)) == 0 {
+			// the version is only reported in the summary, failing
+			// to read it fails the upgrade of the volume later
+			v.Version, _ = upgrade.CurrentVersion(u.resourceKind, name,
+				u.openebsNamespace, u.clientset)
+		}
+		volumes = append(volumes, v)
+	}
+	return volumes, nil
+}
+
+func printBulkSummary(out io.Writer, results []bulkResult) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION\tRESULT\tREASON")
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.result]++
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.name, r.version, r.result, r.reason)
+	}
+	err := w.Flush()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "\n%d succeeded, %d skipped, %d failed",
+		counts[resultSucceeded], counts[resultSkipped], counts[resultFailed])
+	if err != nil {
+		return err
+	}
+	if counts[resultCancelled] != 0 {
+		_, err = fmt.Fprintf(out, ", %d cancelled", counts[resultCancelled])
+		if err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintln(out)
+	return err
+}
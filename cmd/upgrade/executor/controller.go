@@ -0,0 +1,228 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"context"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	cmdUtil "github.com/openebs/upgrade/cmd/util"
+	"github.com/openebs/upgrade/pkg/controller"
+	"github.com/openebs/upgrade/pkg/kubeclient"
+	"github.com/openebs/upgrade/pkg/metrics"
+	migrate "github.com/openebs/upgrade/pkg/migrate/cstor"
+)
+
+const (
+	// controllerLeaderElectionID is the name of the lease
+	// held by the running instance of the controller
+	controllerLeaderElectionID = "openebs-upgrade-controller"
+)
+
+var (
+	controllerCmdHelpText = `
+This command runs a controller which upgrades the resource of every
+UpgradeTask CR and migrates the resource of every MigrationTask CR
+created in the openebs namespace. A failed task is retried with a
+backoff until max-retries, the result is recorded in its status.
+
+Usage: upgrade controller
+`
+)
+
+// ControllerOptions stores information required for the controller
+type ControllerOptions struct {
+	concurrency int
+	leaderElect bool
+}
+
+var (
+	controllerOptions = &ControllerOptions{
+		concurrency: 1,
+	}
+)
+
+// NewControllerCmd runs the controller reconciling the UpgradeTasks
+// and MigrationTasks until the command is terminated
+func NewControllerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "controller",
+		Short:   "Upgrade and migrate the resources of the UpgradeTask and MigrationTask CRs as they are created.",
+		Long:    controllerCmdHelpText,
+		Example: `upgrade controller --concurrency 2`,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(options.RunControllerChecks(cmd), cmdUtil.Fatal)
+			util.CheckErr(options.RunController(cmd), cmdUtil.Fatal)
+		},
+	}
+
+	cmd.Flags().IntVarP(&controllerOptions.concurrency,
+		"concurrency", "",
+		controllerOptions.concurrency,
+		"[optional] number of UpgradeTasks, and of MigrationTasks, processed at once.")
+
+	cmd.Flags().BoolVarP(&controllerOptions.leaderElect,
+		"leader-elect", "",
+		controllerOptions.leaderElect,
+		"[optional] elect a leader among the replicas of the controller, only the leader processes the tasks.")
+
+	cmd.Flags().IntVarP(&options.maxRetries,
+		"max-retries", "",
+		options.maxRetries,
+		"[optional] number of failed attempts after which an UpgradeTask, or a MigrationTask, is marked as failed.")
+
+	addImagePullFlags(cmd)
+	addReplicaHealthFlags(cmd)
+	return cmd
+}
+
+// RunControllerChecks will ensure the sanity of the controller options
+func (u *UpgradeOptions) RunControllerChecks(cmd *cobra.Command) error {
+	if u.dryRun {
+		return errors.Errorf("Cannot execute upgrade controller: dry-run is not supported")
+	}
+	if controllerOptions.concurrency < 1 {
+		return errors.Errorf("Cannot execute upgrade controller: concurrency should be at least 1")
+	}
+	if u.maxRetries < 1 {
+		return errors.Errorf("Cannot execute upgrade controller: max-retries should be at least 1")
+	}
+	return nil
+}
+
+// RunController starts the reconcilers of the tasks
+// and blocks until the controller is stopped
+func (u *UpgradeOptions) RunController(cmd *cobra.Command) error {
+	cfg, err := u.clientOptions.Config()
+	if err != nil {
+		return errors.Wrap(err, "Cannot execute upgrade controller")
+	}
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:    kubeclient.Scheme(),
+		Namespace: u.openebsNamespace,
+		// the metrics of the upgrades are served
+		// separately with the metrics-addr flag
+		MetricsBindAddress:      "0",
+		LeaderElection:          controllerOptions.leaderElect,
+		LeaderElectionID:        controllerLeaderElectionID,
+		LeaderElectionNamespace: u.openebsNamespace,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Cannot execute upgrade controller")
+	}
+	opts := controller.Options{
+		Concurrency: controllerOptions.concurrency,
+		MaxRetries:  u.maxRetries,
+	}
+	err = (&controller.UpgradeTaskReconciler{
+		Clientset: u.clientset.OpenebsClientset,
+		Upgrade: func(ctx context.Context, utaskObj *v1Alpha1API.UpgradeTask) error {
+			return u.upgradeTask(ctx, cmd, utaskObj)
+		},
+		Options: opts,
+	}).SetupWithManager(mgr)
+	if err != nil {
+		return errors.Wrap(err, "Cannot execute upgrade controller")
+	}
+	migrate.IsMigrationTaskJob = true
+	err = (&controller.MigrationTaskReconciler{
+		Clientset: u.clientset.OpenebsClientset,
+		Migrate:   u.migrationTask,
+		Options:   opts,
+	}).SetupWithManager(mgr)
+	if err != nil {
+		return errors.Wrap(err, "Cannot execute upgrade controller")
+	}
+	klog.Infof("Starting upgrade controller in namespace %s", u.openebsNamespace)
+	return mgr.Start(u.ctx)
+}
+
+// upgradeTask upgrades the resource of the UpgradeTask with a copy
+// of the options, so that the tasks processed at once do not share
+// the details of the resource
+func (u *UpgradeOptions) upgradeTask(ctx context.Context, cmd *cobra.Command,
+	utaskObj *v1Alpha1API.UpgradeTask) error {
+	task := *u
+	task.ctx = ctx
+	task.upgradeTaskJob = true
+	// the reconciler starts the task once it is not
+	// paused and its maintenance window is open
+	err := task.InitializeFromUpgradeTaskResource(*utaskObj)
+	if err != nil {
+		return err
+	}
+	err = task.RunPreFlightChecks(cmd)
+	if err != nil {
+		return err
+	}
+	err = task.RunResourceUpgradeChecks(cmd)
+	if err != nil {
+		return err
+	}
+	err = task.InitializeDefaults(cmd)
+	if err != nil {
+		return err
+	}
+	task.addPending(task.resourceKind, 1)
+	return task.RunResourceUpgrade(cmd)
+}
+
+// migrationTask migrates the resource of the MigrationTask
+func (u *UpgradeOptions) migrationTask(ctx context.Context,
+	mtaskObj *v1Alpha1API.MigrationTask) (err error) {
+	err = u.policy.ValidateMigrationTarget()
+	if err != nil {
+		return err
+	}
+	switch {
+	case mtaskObj.Spec.MigrateCStorPool != nil:
+		metrics.AddPending(metrics.Migrate, "cstorPool", 1)
+		defer func() {
+			metrics.ResourceDone(metrics.Migrate, "cstorPool", err)
+		}()
+		migrator := migrate.CSPCMigrator{
+			KubeClientset:    u.clientset.KubeClientset,
+			OpenebsClientset: u.clientset.OpenebsClientset,
+			Maya:             u.clientset.Maya,
+			Recorder:         u.clientset.Recorder,
+		}
+		if mtaskObj.Spec.MigrateCStorPool.Rename != "" {
+			migrator.SetCSPCName(mtaskObj.Spec.MigrateCStorPool.Rename)
+		}
+		return migrator.Migrate(mtaskObj.Spec.MigrateCStorPool.SPCName, u.openebsNamespace)
+	case mtaskObj.Spec.MigrateCStorVolume != nil:
+		metrics.AddPending(metrics.Migrate, "cstorVolume", 1)
+		defer func() {
+			metrics.ResourceDone(metrics.Migrate, "cstorVolume", err)
+		}()
+		migrator := migrate.VolumeMigrator{
+			KubeClientset:    u.clientset.KubeClientset,
+			OpenebsClientset: u.clientset.OpenebsClientset,
+			SnapClientset:    u.clientset.SnapClientset,
+			Maya:             u.clientset.Maya,
+			Recorder:         u.clientset.Recorder,
+		}
+		return migrator.Migrate(mtaskObj.Spec.MigrateCStorVolume.PVName, u.openebsNamespace)
+	}
+	return errors.Errorf("no resource to migrate in %s", mtaskObj.Name)
+}
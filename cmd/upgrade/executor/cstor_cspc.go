@@ -23,8 +23,8 @@ import (
 
 	"github.com/pkg/errors"
 
-	upgrade "github.com/openebs/upgrade/pkg/upgrade"
-	"github.com/openebs/upgrade/pkg/version"
+	cmdUtil "github.com/openebs/upgrade/cmd/util"
+	"github.com/openebs/upgrade/pkg/upgrade/upgrader"
 )
 
 var (
@@ -45,38 +45,36 @@ func NewUpgradeCStorCSPCJob() *cobra.Command {
 		Example: `upgrade cstor-cspc <spc-name>...`,
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) == 0 {
-				util.Fatal("failed to upgrade: no cspc name provided")
+				cmdUtil.Fatal("failed to upgrade: no cspc name provided")
 			}
+			options.addPending("cstorPoolCluster", len(args))
 			for _, name := range args {
 				options.resourceKind = "cstorPoolCluster"
-				util.CheckErr(options.RunPreFlightChecks(cmd), util.Fatal)
-				util.CheckErr(options.InitializeDefaults(cmd), util.Fatal)
-				util.CheckErr(options.RunCStorCSPCUpgrade(cmd, name), util.Fatal)
+				util.CheckErr(options.RunPreFlightChecks(cmd), cmdUtil.Fatal)
+				util.CheckErr(options.InitializeDefaults(cmd), cmdUtil.Fatal)
+				util.CheckErr(ignoreCancelled(options.RunCStorCSPCUpgrade(cmd, name)), cmdUtil.Fatal)
 			}
 		},
 	}
 
+	addImagePullFlags(cmd)
+	addReplicaHealthFlags(cmd)
 	return cmd
 }
 
 // RunCStorCSPCUpgrade upgrades the given Jiva Volume.
 func (u *UpgradeOptions) RunCStorCSPCUpgrade(cmd *cobra.Command, name string) error {
-
-	if version.IsCurrentVersionValid(u.fromVersion) && version.IsDesiredVersionValid(u.toVersion) {
-		klog.Infof("Upgrading %s to %s", name, u.toVersion)
-		err := upgrade.Exec(u.fromVersion, u.toVersion,
-			u.resourceKind,
-			name,
-			u.openebsNamespace,
-			u.imageURLPrefix,
-			u.toVersionImageTag)
-		if err != nil {
-			klog.Error(err)
-			return errors.Errorf("Failed to upgrade cStor CSPC %v", name)
-		}
-		klog.Infof("Successfully upgraded %s to %s", name, u.toVersion)
-	} else {
-		return errors.Errorf("Invalid from version %s or to version %s", u.fromVersion, u.toVersion)
+	if u.dryRun {
+		return u.RunDryRun(name)
+	}
+	to, err := u.execUpgrade(name)
+	if upgrader.IsCancelled(err) {
+		return errors.Wrapf(err, "Upgrade of cStor CSPC %v was cancelled", name)
+	}
+	if err != nil {
+		klog.Error(err)
+		return errors.Errorf("Failed to upgrade cStor CSPC %v", name)
 	}
+	klog.Infof("Successfully upgraded %s to %s", name, to)
 	return nil
 }
@@ -0,0 +1,115 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/openebs/upgrade/pkg/report"
+	upgrade "github.com/openebs/upgrade/pkg/upgrade"
+	"github.com/openebs/upgrade/pkg/upgrade/upgrader"
+)
+
+// stdoutLock keeps the changes of volumes previewed
+// in parallel from being interleaved
+var stdoutLock sync.Mutex
+
+// RunDryRun prints the patches computed for the given resource
+// without applying any of them
+func (u *UpgradeOptions) RunDryRun(name string) error {
+	from, to, err := u.versionsOf(name)
+	if err != nil {
+		u.addToReport(report.Resource{
+			Name:  name,
+			Phase: report.PhaseFailed,
+			Error: err.Error(),
+		})
+		return err
+	}
+	klog.Infof("Computing patches to upgrade %s %s from %s to %s",
+		u.resourceKind, name, from, to)
+	changes, err := upgrade.DryRun(from, to,
+		u.resourceKind,
+		name,
+		u.openebsNamespace,
+		u.imageURLPrefix,
+		u.imageTagOf(to),
+		u.clientset,
+		u.resourcePatchOptions()...)
+	res := report.Resource{
+		Name:        name,
+		FromVersion: from,
+		ToVersion:   to,
+		Phase:       report.PhaseDryRun,
+		Patches:     reportPatches(changes),
+	}
+	if err != nil {
+		res.Phase = report.PhaseFailed
+		res.Error = err.Error()
+	}
+	u.addToReport(res)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to compute patches for %s %s", u.resourceKind, name)
+	}
+	var buf bytes.Buffer
+	err = printChanges(&buf, u.resourceKind, name, changes)
+	if err != nil {
+		return err
+	}
+	stdoutLock.Lock()
+	defer stdoutLock.Unlock()
+	_, err = buf.WriteTo(u.humanOutput())
+	return err
+}
+
+func printChanges(w io.Writer, kind, name string, changes []upgrader.Change) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintf(w, "# %s %s: no changes required\n", kind, name)
+		return err
+	}
+	for _, c := range changes {
+		var buf bytes.Buffer
+		err := json.Indent(&buf, c.Patch, "", "  ")
+		if err != nil {
+			return errors.Wrapf(err, "invalid patch for %s %s/%s", c.Kind, c.Namespace, c.Name)
+		}
+		_, err = fmt.Fprintf(w, "# %s %s/%s\n", c.Kind, c.Namespace, c.Name)
+		if err != nil {
+			return err
+		}
+		if len(c.SkippedContainers) != 0 {
+			_, err = fmt.Fprintf(w, "# skipped non openebs containers: %s\n",
+				strings.Join(c.SkippedContainers, ", "))
+			if err != nil {
+				return err
+			}
+		}
+		_, err = fmt.Fprintf(w, "%s\n", buf.String())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
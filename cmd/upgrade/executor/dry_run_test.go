@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/openebs/upgrade/pkg/upgrade/upgrader"
+)
+
+func Test_printChanges(t *testing.T) {
+	tests := map[string]struct {
+		changes []upgrader.Change
+		want    string
+		wantErr bool
+	}{
+		"no changes": {
+			want: "# cstorVolume pv-1: no changes required\n",
+		},
+		"changes": {
+			changes: []upgrader.Change{
+				{
+					Kind:      "CStorVolumeReplica",
+					Namespace: "openebs",
+					Name:      "pv-1-cspi-a",
+					Patch:     []byte(`{"versionDetails":{"desired":"3.5.0"}}`),
+				},
+				{
+					Kind:              "Deployment",
+					Namespace:         "openebs",
+					Name:              "pv-1-target",
+					Patch:             []byte(`{"metadata":{"labels":{"openebs.io/version":"3.5.0"}}}`),
+					SkippedContainers: []string{"istio-proxy", "fluent-bit"},
+				},
+			},
+			want: `# CStorVolumeReplica openebs/pv-1-cspi-a
+{
+  "versionDetails": {
+    "desired": "3.5.0"
+  }
+}
+# Deployment openebs/pv-1-target
+# skipped non openebs containers: istio-proxy, fluent-bit
+{
+  "metadata": {
+    "labels": {
+      "openebs.io/version": "3.5.0"
+    }
+  }
+}
+`,
+		},
+		"invalid patch": {
+			changes: []upgrader.Change{
+				{Kind: "Service", Namespace: "openebs", Name: "pv-1", Patch: []byte("{")},
+			},
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := printChanges(&buf, "cstorVolume", "pv-1", test.changes)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("printChanges() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if got := buf.String(); got != test.want {
+				t.Errorf("printChanges() =\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
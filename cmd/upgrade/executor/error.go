@@ -20,6 +20,10 @@ import (
 	"context"
 	"fmt"
 	"os"
+
+	"k8s.io/klog/v2"
+
+	"github.com/openebs/upgrade/pkg/upgrade/upgrader"
 )
 
 // CheckError prints err to stderr and exits with code 1 if err is not nil. Otherwise, it is a
@@ -32,3 +36,13 @@ func CheckError(err error) {
 		os.Exit(1)
 	}
 }
+
+// ignoreCancelled returns nil if the upgrade was cancelled with the
+// upgrade control annotation, a cancelled upgrade is not retried
+func ignoreCancelled(err error) error {
+	if upgrader.IsCancelled(err) {
+		klog.Info(err)
+		return nil
+	}
+	return err
+}
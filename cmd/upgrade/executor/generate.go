@@ -0,0 +1,331 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	"github.com/openebs/maya/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	cmdUtil "github.com/openebs/upgrade/cmd/util"
+	"github.com/openebs/upgrade/pkg/generate"
+	upgrade "github.com/openebs/upgrade/pkg/upgrade"
+	"github.com/openebs/upgrade/pkg/upgrade/upgrader"
+)
+
+const (
+	// upgradeJobLabel is set on the generated UpgradeTasks
+	// with the name of the job processing them
+	upgradeJobLabel = "openebs.io/upgrade-job"
+)
+
+var (
+	generateCmdHelpText = `
+This command prints the manifests of the jobs upgrading the given
+resources, or all the resources of the kind which are not in the
+to-version, with the service account and the cluster role they run
+with. The to-version and the image prefix are read from the operator
+managing the resources and the from-version from every resource when
+they are not provided. The output is sorted so that the same cluster
+always gives the same manifests.
+
+Supported kinds: cstor-cspc, cstor-volume, jiva-volume
+
+Usage: upgrade generate <kind> [<name>...]
+`
+
+	generateKinds = map[string]string{
+		"cstor-cspc":   "cstorPoolCluster",
+		"cstor-volume": "cstorVolume",
+		"jiva-volume":  "jivaVolume",
+	}
+)
+
+// GenerateOptions stores information required to generate the manifests
+type GenerateOptions struct {
+	tasks          bool
+	serviceAccount string
+	backoffLimit   int
+}
+
+var (
+	generateOptions = &GenerateOptions{
+		serviceAccount: "openebs-upgrade",
+		backoffLimit:   upgrader.DefaultMaxRetries,
+	}
+)
+
+// generatedResource is a resource to be
+// upgraded by the generated manifests
+type generatedResource struct {
+	name    string
+	version string
+}
+
+// NewGenerateCmd prints the manifests of the
+// jobs upgrading the given kind of resources
+func NewGenerateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "generate",
+		Short:   "Print the job and rbac manifests upgrading the resources",
+		Long:    generateCmdHelpText,
+		Example: `upgrade generate cstor-volume > upgrade.yaml`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 {
+				cmdUtil.Fatal("failed to generate: resource kind is required")
+			}
+			kind, ok := generateKinds[args[0]]
+			if !ok {
+				cmdUtil.Fatal("failed to generate: invalid resource kind " + args[0])
+			}
+			options.resourceKind = kind
+			util.CheckErr(options.RunPreFlightChecks(cmd), cmdUtil.Fatal)
+			util.CheckErr(options.RunGenerateChecks(cmd), cmdUtil.Fatal)
+			util.CheckErr(options.RunGenerate(cmd, args[0], args[1:]), cmdUtil.Fatal)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&generateOptions.tasks,
+		"tasks", "",
+		generateOptions.tasks,
+		"[optional] generate an UpgradeTask for every resource and a single job processing them with the resource command.")
+
+	cmd.Flags().StringVarP(&generateOptions.serviceAccount,
+		"service-account", "",
+		generateOptions.serviceAccount,
+		"[optional] name of the service account, cluster role and cluster role binding run by the jobs.")
+
+	cmd.Flags().IntVarP(&generateOptions.backoffLimit,
+		"backoff-limit", "",
+		generateOptions.backoffLimit,
+		"[optional] backoffLimit of the jobs, the resource command marks the task as failed once it is reached.")
+
+	return cmd
+}
+
+// RunGenerateChecks will ensure the sanity of the generate options
+func (u *UpgradeOptions) RunGenerateChecks(cmd *cobra.Command) error {
+	if len(strings.TrimSpace(generateOptions.serviceAccount)) == 0 {
+		return errors.Errorf("Cannot generate manifests: service account is missing")
+	}
+	if generateOptions.backoffLimit < 1 {
+		return errors.Errorf("Cannot generate manifests: backoff-limit should be at least 1")
+	}
+	return nil
+}
+
+// RunGenerate prints the manifests upgrading the given resources
+func (u *UpgradeOptions) RunGenerate(cmd *cobra.Command, cmdKind string, names []string) error {
+	objs, err := u.generateManifests(cmdKind, names)
+	if err != nil {
+		return errors.Wrap(err, "Cannot generate manifests")
+	}
+	stdoutLock.Lock()
+	defer stdoutLock.Unlock()
+	return generate.Write(os.Stdout, objs)
+}
+
+// generateManifests returns the rbac objects followed by the jobs, and
+// the UpgradeTasks when requested, upgrading the given resources
+func (u *UpgradeOptions) generateManifests(cmdKind string, names []string) ([]interface{}, error) {
+	component, err := upgrade.OperatorComponent(u.resourceKind)
+	if err != nil {
+		return nil, err
+	}
+	operator, err := generate.GetOperator(u.clientset.KubeClientset, u.openebsNamespace, component)
+	if err != nil {
+		return nil, err
+	}
+	to := u.toVersion
+	if len(strings.TrimSpace(to)) == 0 {
+		to = operator.Version
+	}
+	prefix := u.imageURLPrefix
+	if len(strings.TrimSpace(prefix)) == 0 {
+		prefix = operator.ImagePrefix
+	}
+	resources, err := u.generatedResources(names, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(resources) == 0 {
+		return nil, errors.Errorf("no %s to upgrade to %s", cmdKind, to)
+	}
+	o := generate.Options{
+		Operation:      "upgrade",
+		Namespace:      u.openebsNamespace,
+		ServiceAccount: generateOptions.serviceAccount,
+		Image:          prefix + "upgrade:" + u.imageTagOf(to),
+		BackoffLimit:   int32(generateOptions.backoffLimit),
+	}
+	objs := generate.RBAC(o)
+	if generateOptions.tasks {
+		return append(objs, u.generateTasks(o, cmdKind, to, prefix, resources)...), nil
+	}
+	// the resources are grouped by version as a job
+	// upgrades all its resources from one version
+	byVersion := map[string][]string{}
+	versions := []string{}
+	for _, r := range resources {
+		if _, ok := byVersion[r.version]; !ok {
+			versions = append(versions, r.version)
+		}
+		byVersion[r.version] = append(byVersion[r.version], r.name)
+	}
+	sort.Strings(versions)
+	for _, from := range versions {
+		args := []string{
+			cmdKind,
+			"--from-version=" + from,
+			"--to-version=" + to,
+			"--to-version-image-prefix=" + prefix,
+		}
+		if len(strings.TrimSpace(u.toVersionImageTag)) != 0 {
+			args = append(args, "--to-version-image-tag="+u.toVersionImageTag)
+		}
+		args = append(args, byVersion[from]...)
+		objs = append(objs, generate.NewJob(o, generate.Job{
+			Name: generate.Name("upgrade", cmdKind, from),
+			Args: append(args, "--v=4"),
+		}))
+	}
+	return objs, nil
+}
+
+// generateTasks returns the UpgradeTasks of the resources
+// and the job processing them with the resource command
+func (u *UpgradeOptions) generateTasks(o generate.Options, cmdKind, to, prefix string,
+	resources []generatedResource) []interface{} {
+	jobName := generate.Name("upgrade", cmdKind, to)
+	objs := []interface{}{}
+	for _, r := range resources {
+		utaskObj := &v1Alpha1API.UpgradeTask{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1Alpha1API.SchemeGroupVersion.String(),
+				Kind:       "UpgradeTask",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      upgrader.UpgradeTaskName(u.resourceKind, r.name),
+				Namespace: u.openebsNamespace,
+				Labels: map[string]string{
+					upgradeJobLabel: jobName,
+				},
+			},
+			Spec: v1Alpha1API.UpgradeTaskSpec{
+				FromVersion: r.version,
+				ToVersion:   to,
+				ImagePrefix: prefix,
+				ImageTag:    u.toVersionImageTag,
+			},
+		}
+		switch u.resourceKind {
+		case "cstorPoolCluster":
+			utaskObj.Spec.ResourceSpec.CStorPoolCluster = &v1Alpha1API.CStorPoolCluster{CSPCName: r.name}
+		case "cstorVolume":
+			utaskObj.Spec.ResourceSpec.CStorVolume = &v1Alpha1API.CStorVolume{PVName: r.name}
+		case "jivaVolume":
+			utaskObj.Spec.ResourceSpec.JivaVolume = &v1Alpha1API.JivaVolume{PVName: r.name}
+		}
+		objs = append(objs, utaskObj)
+	}
+	return append(objs, generate.NewJob(o, generate.Job{
+		Name: jobName,
+		Args: []string{
+			"resource",
+			"--v=4",
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name:  "UPGRADE_TASK_LABEL",
+				Value: upgradeJobLabel + "=" + jobName,
+			},
+		},
+	}))
+}
+
+// generatedResources returns the given resources, or all the resources
+// of the kind when no names are given, with the versions they are
+// upgraded from. The resources already in the to-version are skipped.
+func (u *UpgradeOptions) generatedResources(names []string, to string) ([]generatedResource, error) {
+	var err error
+	if len(names) == 0 {
+		names, err = u.listResources(to)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(names)
+	resources := []generatedResource{}
+	for _, name := range names {
+		from := u.fromVersion
+		if len(strings.TrimSpace(from)) == 0 {
+			from, err = upgrade.CurrentVersion(u.resourceKind, name, u.openebsNamespace, u.clientset)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if from == to {
+			klog.Infof("Skipping %s %s, already in %s version", u.resourceKind, name, to)
+			continue
+		}
+		err = u.policy.ValidateUpgradePath(from, to)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Cannot upgrade %s %s", u.resourceKind, name)
+		}
+		resources = append(resources, generatedResource{name: name, version: from})
+	}
+	return resources, nil
+}
+
+// listResources returns the names of all the resources of the kind,
+// the volumes annotated to be skipped from bulk upgrades are excluded
+func (u *UpgradeOptions) listResources(to string) ([]string, error) {
+	names := []string{}
+	if u.resourceKind == "cstorPoolCluster" {
+		cspcList, err := u.clientset.OpenebsClientset.CstorV1().
+			CStorPoolClusters(u.openebsNamespace).
+			List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list cspcs")
+		}
+		for _, cspc := range cspcList.Items {
+			names = append(names, cspc.Name)
+		}
+		return names, nil
+	}
+	volumes, err := upgrade.SelectVolumes(u.resourceKind, u.openebsNamespace, to,
+		upgrade.VolumeSelector{All: true}, u.clientset)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range volumes {
+		if v.SkipReason != "" {
+			continue
+		}
+		names = append(names, v.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/openebs/maya/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	cmdUtil "github.com/openebs/upgrade/cmd/util"
+	upgrade "github.com/openebs/upgrade/pkg/upgrade"
+)
+
+var (
+	impactCmdHelpText = `
+This command shows who feels the upgrade of the given cStor or Jiva
+csi volumes: the pvc, the pods using it and their workloads, and the
+nodes the iSCSI initiator runs on. The expected IO pause is estimated
+from the restart of the target, and of the replica of single replica
+jiva volumes. Single replica volumes and volumes whose replicas are not
+all healthy are flagged. Nothing is changed on the cluster.
+
+Usage: upgrade impact <pv-name>...
+`
+)
+
+// NewImpactCmd shows the application impact of the upgrade of volumes
+func NewImpactCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "impact",
+		Short:   "Show the applications affected by the upgrade of volumes",
+		Long:    impactCmdHelpText,
+		Example: `upgrade impact <pv-name>...`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				cmdUtil.Fatal("failed to analyze impact: no volume name provided")
+			}
+			util.CheckErr(options.RunImpact(os.Stdout, args), cmdUtil.Fatal)
+		},
+	}
+	return cmd
+}
+
+// RunImpact prints the impact of the upgrade of the given volumes
+func (u *UpgradeOptions) RunImpact(out io.Writer, names []string) error {
+	impacts := []*upgrade.VolumeImpact{}
+	for _, name := range names {
+		impact, err := upgrade.AnalyzeImpact(name, u.openebsNamespace, u.clientset)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to analyze the impact of the upgrade of %s", name)
+		}
+		impacts = append(impacts, impact)
+	}
+	return printImpact(out, impacts)
+}
+
+// addImpactFlags adds the flag logging the impact
+// of the upgrade of every volume before upgrading it
+func addImpactFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&options.analyzeImpact,
+		"analyze-impact", "",
+		options.analyzeImpact,
+		"[optional] log the pods, workloads and initiator nodes affected by the upgrade of every volume and the expected IO pause before upgrading it.")
+}
+
+// logImpact logs the impact of the upgrade of the volume before
+// touching it, a failed analysis does not stop the upgrade
+func (u *UpgradeOptions) logImpact(name string) {
+	if !u.analyzeImpact || (u.resourceKind != "cstorVolume" && u.resourceKind != "jivaVolume") {
+		return
+	}
+	impact, err := upgrade.AnalyzeImpact(name, u.openebsNamespace, u.clientset)
+	if err != nil {
+		klog.Warningf("Failed to analyze the impact of the upgrade of %s: %v", name, err)
+		return
+	}
+	klog.Infof("Upgrade of %s pauses the IO for about %s: pvc %s, workloads %s, initiator nodes %s, %d of %d replicas healthy",
+		name, impact.ExpectedIOPause, orNone(impact.PVC), joinOrNone(impact.Workloads),
+		joinOrNone(impact.InitiatorNodes), impact.HealthyReplicas, impact.Replicas)
+	for _, w := range impact.Warnings {
+		klog.Warningf("Upgrade of %s: %s", name, w)
+	}
+}
+
+// printImpact prints a table with the impact of every volume
+func printImpact(out io.Writer, impacts []*upgrade.VolumeImpact) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tKIND\tPVC\tWORKLOADS\tINITIATOR-NODES\tHEALTHY-REPLICAS\tIO-PAUSE\tWARNINGS")
+	for _, i := range impacts {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d/%d\t%s\t%s\n",
+			i.Name, i.Kind, orNone(i.PVC),
+			joinOrNone(i.Workloads), joinOrNone(i.InitiatorNodes),
+			i.HealthyReplicas, i.Replicas, i.ExpectedIOPause,
+			strings.Join(i.Warnings, "; "))
+	}
+	return w.Flush()
+}
+
+func joinOrNone(s []string) string {
+	return orNone(strings.Join(s, ","))
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
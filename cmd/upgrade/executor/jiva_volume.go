@@ -23,8 +23,8 @@ import (
 
 	"github.com/pkg/errors"
 
-	upgrade "github.com/openebs/upgrade/pkg/upgrade"
-	"github.com/openebs/upgrade/pkg/version"
+	cmdUtil "github.com/openebs/upgrade/cmd/util"
+	"github.com/openebs/upgrade/pkg/upgrade/upgrader"
 )
 
 var (
@@ -43,38 +43,49 @@ func NewUpgradeJivaVolumeJob() *cobra.Command {
 		Long:    jivaVolumeUpgradeCmdHelpText,
 		Example: `upgrade jiva-volume <spc-name>...`,
 		Run: func(cmd *cobra.Command, args []string) {
-			if len(args) == 0 {
-				util.Fatal("failed to upgrade: no volume name provided")
+			if !options.volumeSelector.IsEmpty() && len(args) != 0 {
+				cmdUtil.Fatal("failed to upgrade: volume names cannot be provided along with selectors")
 			}
+			if options.volumeSelector.IsEmpty() && len(args) == 0 {
+				cmdUtil.Fatal("failed to upgrade: no volume name provided")
+			}
+			if !options.volumeSelector.IsEmpty() || options.parallelism > 1 {
+				options.resourceKind = "jivaVolume"
+				util.CheckErr(options.RunPreFlightChecks(cmd), cmdUtil.Fatal)
+				util.CheckErr(options.InitializeDefaults(cmd), cmdUtil.Fatal)
+				util.CheckErr(options.RunBulkUpgrade(cmd, args, options.RunJivaVolumeUpgrade), cmdUtil.Fatal)
+				return
+			}
+			options.addPending("jivaVolume", len(args))
 			for _, name := range args {
 				options.resourceKind = "jivaVolume"
-				util.CheckErr(options.RunPreFlightChecks(cmd), util.Fatal)
-				util.CheckErr(options.InitializeDefaults(cmd), util.Fatal)
-				util.CheckErr(options.RunJivaVolumeUpgrade(cmd, name), util.Fatal)
+				util.CheckErr(options.RunPreFlightChecks(cmd), cmdUtil.Fatal)
+				util.CheckErr(options.InitializeDefaults(cmd), cmdUtil.Fatal)
+				util.CheckErr(ignoreCancelled(options.RunJivaVolumeUpgrade(cmd, name)), cmdUtil.Fatal)
 			}
 		},
 	}
+	addVolumeSelectorFlags(cmd, false)
+	addImagePullFlags(cmd)
+	addReplicaHealthFlags(cmd)
+	addImpactFlags(cmd)
 	return cmd
 }
 
 // RunJivaVolumeUpgrade upgrades the given Jiva Volume.
 func (u *UpgradeOptions) RunJivaVolumeUpgrade(cmd *cobra.Command, name string) error {
-
-	if version.IsCurrentVersionValid(u.fromVersion) && version.IsDesiredVersionValid(u.toVersion) {
-		klog.Infof("Upgrading JivaVolume %s to %s", name, u.toVersion)
-		err := upgrade.Exec(u.fromVersion, u.toVersion,
-			u.resourceKind,
-			name,
-			u.openebsNamespace,
-			u.imageURLPrefix,
-			u.toVersionImageTag)
-		if err != nil {
-			klog.Error(err)
-			return errors.Errorf("Failed to upgrade JivaVolume %v", name)
-		}
-		klog.Infof("Successfully upgraded %s to %s", name, u.toVersion)
-	} else {
-		return errors.Errorf("Invalid from version %s or to version %s", u.fromVersion, u.toVersion)
+	u.logImpact(name)
+	if u.dryRun {
+		return u.RunDryRun(name)
+	}
+	to, err := u.execUpgrade(name)
+	if upgrader.IsCancelled(err) {
+		return errors.Wrapf(err, "Upgrade of JivaVolume %v was cancelled", name)
+	}
+	if err != nil {
+		klog.Error(err)
+		return errors.Errorf("Failed to upgrade JivaVolume %v", name)
 	}
+	klog.Infof("Successfully upgraded %s to %s", name, to)
 	return nil
 }
@@ -17,11 +17,33 @@ limitations under the License.
 package executor
 
 import (
+	"context"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	errors "github.com/pkg/errors"
 
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	cmdUtil "github.com/openebs/upgrade/cmd/util"
+	"github.com/openebs/upgrade/pkg/events"
+	"github.com/openebs/upgrade/pkg/kubeclient"
+	"github.com/openebs/upgrade/pkg/metrics"
+	"github.com/openebs/upgrade/pkg/report"
+	upgrade "github.com/openebs/upgrade/pkg/upgrade"
+	"github.com/openebs/upgrade/pkg/upgrade/upgrader"
+	"github.com/openebs/upgrade/pkg/version"
+)
+
+const (
+	// registryRulesConfigMapKey is the key of the
+	// registry rules in the configmap
+	registryRulesConfigMapKey = "registry-rules.yaml"
 )
 
 // UpgradeOptions stores information required for upgrade
@@ -31,33 +53,196 @@ type UpgradeOptions struct {
 	openebsNamespace  string
 	imageURLPrefix    string
 	toVersionImageTag string
-	resourceKind      string
-	name              string
+	imageMappingFile  string
+	imageMapping      upgrader.ImageMapping
+	// registryRulesFile and registryRulesConfigMap are
+	// the sources of the registry rules
+	registryRulesFile      string
+	registryRulesConfigMap string
+	registryRules          upgrader.RegistryRules
+	// policyFile and policyConfigMap are the sources of
+	// the policy validating the upgrade paths
+	policyFile       string
+	policyConfigMap  string
+	policy           version.Policy
+	resourceKind     string
+	name             string
+	clientOptions    kubeclient.Options
+	clientset        *kubeclient.Clientset
+	dryRun           bool
+	analyzeImpact    bool
+	timeout          time.Duration
+	stepTimeout      time.Duration
+	imagePullCheck   bool
+	imagePullTimeout time.Duration
+	// skipReplicaHealthCheck and replicaHealthTimeout control the wait
+	// for the volume replicas around each pool or jiva replica restart
+	skipReplicaHealthCheck bool
+	replicaHealthTimeout   time.Duration
+	ctx                    context.Context
+	cancel                 context.CancelFunc
+	volumeSelector         upgrade.VolumeSelector
+	parallelism            int
+	// maxRetries is the number of failed attempts after which
+	// the controller marks an UpgradeTask as failed
+	maxRetries int
+	// upgradeTaskJob is set when the upgrade is run by the
+	// controller for an UpgradeTask, like the resource job
+	upgradeTaskJob bool
+	// metricsAddr and metricsPushgateway are the address the
+	// metrics are served on and the Pushgateway they are pushed to
+	metricsAddr        string
+	metricsPushgateway string
+	// output is the format of the report of the run,
+	// written to the reportFile if set or to stdout
+	output     string
+	reportFile string
+	report     *report.Report
 }
 
 var (
 	options = &UpgradeOptions{
-		openebsNamespace: "openebs",
-		imageURLPrefix:   "",
+		openebsNamespace:     "openebs",
+		imageURLPrefix:       "",
+		parallelism:          1,
+		imagePullTimeout:     5 * time.Minute,
+		replicaHealthTimeout: upgrader.DefaultReplicaHealthTimeout,
+		maxRetries:           upgrader.DefaultMaxRetries,
 	}
 )
 
+// InitializeClients builds the clients used by the upgrade job
+// from the kubeconfig options
+func (u *UpgradeOptions) InitializeClients() error {
+	if u.clientset != nil {
+		return nil
+	}
+	clientset, err := kubeclient.New(&u.clientOptions)
+	if err != nil {
+		return errors.Wrap(err, "Cannot execute upgrade job")
+	}
+	clientset.Recorder = events.NewRecorder(clientset.KubeClientset,
+		kubeclient.Scheme(), "openebs-upgrade")
+	u.clientset = clientset
+	return nil
+}
+
+// InitializePolicy loads the policy validating the upgrade paths,
+// the default policy is used when none is given
+func (u *UpgradeOptions) InitializePolicy() error {
+	policy, err := cmdUtil.LoadPolicy(u.clientset.KubeClientset, u.openebsNamespace,
+		u.policyFile, u.policyConfigMap)
+	if err != nil {
+		return errors.Wrap(err, "Cannot execute upgrade job")
+	}
+	u.policy = policy
+	return nil
+}
+
+// InitializeMetrics serves the metrics of the job and
+// pushes them to the Pushgateway when the job exits
+func (u *UpgradeOptions) InitializeMetrics() error {
+	if u.metricsAddr != "" {
+		err := metrics.Serve(u.metricsAddr)
+		if err != nil {
+			return errors.Wrap(err, "Cannot execute upgrade job")
+		}
+	}
+	if u.metricsPushgateway != "" {
+		url := u.metricsPushgateway
+		cmdUtil.OnExit(func() {
+			err := metrics.Push(url, "openebs-upgrade")
+			if err != nil {
+				klog.Error(err)
+			}
+		})
+	}
+	return nil
+}
+
+// addPending counts the resources to be upgraded as pending
+// in the metrics, nothing is upgraded by a dry-run
+func (u *UpgradeOptions) addPending(kind string, n int) {
+	if u.dryRun {
+		return
+	}
+	metrics.AddPending(metrics.Upgrade, kind, n)
+}
+
+// resourceDone counts a pending resource which was
+// not upgraded as failed in the metrics
+func (u *UpgradeOptions) resourceDone(err error) {
+	if u.dryRun {
+		return
+	}
+	metrics.ResourceDone(metrics.Upgrade, u.resourceKind, err)
+}
+
+// InitializeContext sets up the context bounding the whole job, it is
+// cancelled when the timeout expires or the job is terminated
+func (u *UpgradeOptions) InitializeContext() {
+	if u.ctx != nil {
+		return
+	}
+	u.ctx, u.cancel = signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	if u.timeout > 0 {
+		stop := u.cancel
+		var cancel context.CancelFunc
+		u.ctx, cancel = context.WithTimeout(u.ctx, u.timeout)
+		// release the timer and stop relaying the signals
+		u.cancel = func() {
+			cancel()
+			stop()
+		}
+	}
+	cmdUtil.OnExit(u.cancel)
+}
+
+// resourcePatchOptions returns the options common
+// to the upgrade of every resource
+func (u *UpgradeOptions) resourcePatchOptions() []upgrader.ResourcePatchOptions {
+	opts := []upgrader.ResourcePatchOptions{
+		upgrader.WithContext(u.ctx),
+		upgrader.WithStepTimeout(u.stepTimeout),
+		upgrader.WithImageMapping(u.imageMapping),
+		upgrader.WithRegistryRules(u.registryRules),
+		upgrader.WithImagePullCheck(u.imagePullCheck, u.imagePullTimeout),
+		upgrader.WithReplicaHealthCheck(u.skipReplicaHealthCheck, u.replicaHealthTimeout),
+	}
+	if u.upgradeTaskJob {
+		opts = append(opts,
+			upgrader.WithUpgradeTaskJob(true),
+			upgrader.WithMaxRetries(u.maxRetries),
+		)
+	}
+	return opts
+}
+
 // RunPreFlightChecks will ensure the sanity of the common upgrade options
 func (u *UpgradeOptions) RunPreFlightChecks(cmd *cobra.Command) error {
 	if len(strings.TrimSpace(u.openebsNamespace)) == 0 {
 		return errors.Errorf("Cannot execute upgrade job: namespace is missing")
 	}
 
-	if len(strings.TrimSpace(u.fromVersion)) == 0 {
-		return errors.Errorf("Cannot execute upgrade job: from-version is missing")
+	if len(strings.TrimSpace(u.resourceKind)) == 0 {
+		return errors.Errorf("Cannot execute upgrade job: resource details are missing")
 	}
 
-	if len(strings.TrimSpace(u.toVersion)) == 0 {
-		return errors.Errorf("Cannot execute upgrade job: to-version is missing")
+	// versions which are not given are inferred and
+	// validated separately for every resource
+	switch {
+	case len(strings.TrimSpace(u.fromVersion)) != 0 && len(strings.TrimSpace(u.toVersion)) != 0:
+		if err := u.policy.ValidateUpgradePath(u.fromVersion, u.toVersion); err != nil {
+			return errors.Wrap(err, "Cannot execute upgrade job")
+		}
+	case len(strings.TrimSpace(u.toVersion)) != 0:
+		if err := version.ValidateTarget(u.toVersion); err != nil {
+			return errors.Wrap(err, "Cannot execute upgrade job")
+		}
 	}
 
-	if len(strings.TrimSpace(u.resourceKind)) == 0 {
-		return errors.Errorf("Cannot execute upgrade job: resource details are missing")
+	if u.parallelism < 1 {
+		return errors.Errorf("Cannot execute upgrade job: parallelism should be at least 1")
 	}
 
 	return nil
@@ -69,5 +254,89 @@ func (u *UpgradeOptions) InitializeDefaults(cmd *cobra.Command) error {
 	if len(strings.TrimSpace(u.toVersionImageTag)) == 0 {
 		u.toVersionImageTag = u.toVersion
 	}
+	if u.imageMapping == nil && len(strings.TrimSpace(u.imageMappingFile)) != 0 {
+		data, err := os.ReadFile(u.imageMappingFile)
+		if err != nil {
+			return errors.Wrap(err, "Cannot execute upgrade job: failed to read image mapping")
+		}
+		u.imageMapping, err = upgrader.ParseImageMapping(data)
+		if err != nil {
+			return errors.Wrap(err, "Cannot execute upgrade job")
+		}
+	}
+	if u.registryRules == nil {
+		err := u.loadRegistryRules()
+		if err != nil {
+			return errors.Wrap(err, "Cannot execute upgrade job")
+		}
+	}
 	return nil
 }
+
+// loadRegistryRules reads the registry rules from
+// the file or the configmap, at most one can be given
+func (u *UpgradeOptions) loadRegistryRules() error {
+	var data []byte
+	var err error
+	switch {
+	case u.registryRulesFile != "" && u.registryRulesConfigMap != "":
+		return errors.Errorf("only one of registry-rules-file or registry-rules-configmap can be set")
+	case u.registryRulesFile != "":
+		data, err = os.ReadFile(u.registryRulesFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to read registry rules")
+		}
+	case u.registryRulesConfigMap != "":
+		cm, err := u.clientset.KubeClientset.CoreV1().ConfigMaps(u.openebsNamespace).
+			Get(context.TODO(), u.registryRulesConfigMap, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get registry rules configmap %s", u.registryRulesConfigMap)
+		}
+		rules, ok := cm.Data[registryRulesConfigMapKey]
+		if !ok {
+			return errors.Errorf("configmap %s has no %s key", cm.Name, registryRulesConfigMapKey)
+		}
+		data = []byte(rules)
+	default:
+		return nil
+	}
+	u.registryRules, err = upgrader.ParseRegistryRules(data)
+	return err
+}
+
+// versionsOf returns the versions the given resource is upgraded from
+// and to. When not provided the from-version is read from the resource
+// and the to-version from the operator managing it, so that resources
+// at different versions can be upgraded together. The upgrade path is
+// validated for every resource with an inferred version.
+func (u *UpgradeOptions) versionsOf(name string) (string, string, error) {
+	from, to := u.fromVersion, u.toVersion
+	var err error
+	if len(strings.TrimSpace(to)) == 0 {
+		to, err = upgrade.OperatorVersion(u.resourceKind, u.openebsNamespace, u.clientset)
+		if err != nil {
+			return "", "", errors.Wrap(err, "failed to infer the to-version")
+		}
+	}
+	if len(strings.TrimSpace(from)) == 0 {
+		from, err = upgrade.CurrentVersion(u.resourceKind, name, u.openebsNamespace, u.clientset)
+		if err != nil {
+			return "", "", errors.Wrap(err, "failed to infer the from-version")
+		}
+	}
+	if from != u.fromVersion || to != u.toVersion {
+		err = u.policy.ValidateUpgradePath(from, to)
+		if err != nil {
+			return "", "", errors.Wrapf(err, "Cannot upgrade %s %s", u.resourceKind, name)
+		}
+	}
+	return from, to, nil
+}
+
+// imageTagOf returns the image tag to use for the given to-version
+func (u *UpgradeOptions) imageTagOf(to string) string {
+	if len(strings.TrimSpace(u.toVersionImageTag)) == 0 {
+		return to
+	}
+	return u.toVersionImageTag
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	cmdUtil "github.com/openebs/upgrade/cmd/util"
+	"github.com/openebs/upgrade/pkg/report"
+	upgrade "github.com/openebs/upgrade/pkg/upgrade"
+	"github.com/openebs/upgrade/pkg/upgrade/upgrader"
+)
+
+// InitializeReport sets up the report of the run when an output
+// format or a report file is given, it is written when the job exits
+func (u *UpgradeOptions) InitializeReport() error {
+	if u.output == "" && u.reportFile == "" {
+		return nil
+	}
+	if u.output == "" {
+		u.output = report.FormatJSON
+	}
+	err := report.ValidateFormat(u.output)
+	if err != nil {
+		return errors.Wrap(err, "Cannot execute upgrade job")
+	}
+	u.report = report.New("upgrade")
+	cmdUtil.OnExit(func() {
+		err := u.writeReport()
+		if err != nil {
+			klog.Errorf("failed to write the report: %v", err)
+		}
+	})
+	return nil
+}
+
+// writeReport writes the report to the report file or to stdout
+func (u *UpgradeOptions) writeReport() error {
+	var w io.Writer = os.Stdout
+	if u.reportFile != "" {
+		f, err := os.Create(u.reportFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	stdoutLock.Lock()
+	defer stdoutLock.Unlock()
+	return u.report.Write(w, u.output)
+}
+
+// humanOutput returns where the summaries and the previewed patches
+// are printed, stderr if the report is written to stdout so that the
+// report can be parsed
+func (u *UpgradeOptions) humanOutput() io.Writer {
+	if u.report != nil && u.reportFile == "" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// addToReport adds the result of a resource to the report, the steps
+// are read from the UpgradeTask of the resource if it exists
+func (u *UpgradeOptions) addToReport(res report.Resource) {
+	if u.report == nil {
+		return
+	}
+	res.Kind = u.resourceKind
+	if res.Phase == report.PhaseSucceeded || res.Phase == report.PhaseFailed {
+		utaskObj, err := u.clientset.OpenebsClientset.OpenebsV1alpha1().
+			UpgradeTasks(u.openebsNamespace).
+			Get(context.TODO(), upgrader.UpgradeTaskName(u.resourceKind, res.Name), metav1.GetOptions{})
+		if err == nil {
+			res.Steps = report.UpgradeSteps(utaskObj)
+		}
+	}
+	u.report.Add(res)
+}
+
+// reportPatches converts the changes to the patches of the report
+func reportPatches(changes []upgrader.Change) []report.Patch {
+	patches := []report.Patch{}
+	for _, c := range changes {
+		patches = append(patches, report.Patch{
+			Kind:      c.Kind,
+			Namespace: c.Namespace,
+			Name:      c.Name,
+			Patch:     c.Patch,
+		})
+	}
+	return patches
+}
+
+// execUpgrade upgrades the given resource from its current version and
+// records the result in the report, it returns the version upgraded to
+func (u *UpgradeOptions) execUpgrade(name string) (string, error) {
+	from, to, err := u.versionsOf(name)
+	if err != nil {
+		u.addToReport(report.Resource{
+			Name:  name,
+			Phase: report.PhaseFailed,
+			Error: err.Error(),
+		})
+		return "", err
+	}
+	klog.Infof("Upgrading %s %s from %s to %s", u.resourceKind, name, from, to)
+	var lock sync.Mutex
+	changes := []upgrader.Change{}
+	err = upgrade.Exec(from, to,
+		u.resourceKind,
+		name,
+		u.openebsNamespace,
+		u.imageURLPrefix,
+		u.imageTagOf(to),
+		u.clientset,
+		append(u.resourcePatchOptions(),
+			upgrader.WithPatchRecorder(func(c upgrader.Change) {
+				lock.Lock()
+				defer lock.Unlock()
+				changes = append(changes, c)
+			}),
+		)...)
+	res := report.Resource{
+		Name:        name,
+		FromVersion: from,
+		ToVersion:   to,
+		Phase:       report.PhaseSucceeded,
+		Patches:     reportPatches(changes),
+	}
+	if err != nil {
+		res.Phase = report.PhaseFailed
+		res.Error = err.Error()
+	}
+	if upgrader.IsCancelled(err) {
+		res.Phase = report.PhaseCancelled
+	}
+	u.addToReport(res)
+	return to, err
+}
@@ -21,20 +21,23 @@ import (
 	"os"
 	"strings"
 
-	"k8s.io/client-go/rest"
-	"k8s.io/klog/v2"
-
 	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
-	openebsclientset "github.com/openebs/api/v3/pkg/client/clientset/versioned"
 	"github.com/openebs/maya/pkg/util"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
 
 	cmdUtil "github.com/openebs/upgrade/cmd/util"
 	upgrade "github.com/openebs/upgrade/pkg/upgrade"
-	"github.com/openebs/upgrade/pkg/version"
+	"github.com/openebs/upgrade/pkg/upgrade/upgrader"
+)
+
+const (
+	// imageMappingAnnotation holds the image mapping of an UpgradeTask
+	// in the same format as the image mapping file
+	imageMappingAnnotation = "openebs.io/image-mapping"
 )
 
 var (
@@ -53,39 +56,56 @@ type ResourceOptions struct {
 
 // NewUpgradeResourceJob upgrade a resource from upgradeTask
 func NewUpgradeResourceJob() *cobra.Command {
-	client, err := initClient()
-	util.CheckErr(err, util.Fatal)
 	cmd := &cobra.Command{
 		Use:     "resource",
 		Short:   "Upgrade a resource using the details specified in the UpgradeTask CR.",
 		Long:    resourceUpgradeCmdHelpText,
 		Example: `upgrade resource`,
 		Run: func(cmd *cobra.Command, args []string) {
+			client := options.clientset.OpenebsClientset
 			upgradeTaskLabel := cmdUtil.GetUpgradeTaskLabel()
-			openebsNamespace := cmdUtil.GetOpenEBSNamespace()
+			openebsNamespace := options.openebsNamespace
 			upgradeTaskList, err := client.OpenebsV1alpha1().UpgradeTasks(openebsNamespace).
 				List(context.TODO(), metav1.ListOptions{
 					LabelSelector: upgradeTaskLabel,
 				})
-			util.CheckErr(err, util.Fatal)
+			util.CheckErr(err, cmdUtil.Fatal)
 			if len(upgradeTaskList.Items) == 0 {
-				util.Fatal("No resource found for given label")
+				cmdUtil.Fatal("No resource found for given label")
 			}
 			for _, cr := range upgradeTaskList.Items {
-				util.CheckErr(options.InitializeFromUpgradeTaskResource(cr), util.Fatal)
-				util.CheckErr(options.RunPreFlightChecks(cmd), util.Fatal)
-				util.CheckErr(options.RunResourceUpgradeChecks(cmd), util.Fatal)
-				util.CheckErr(options.InitializeDefaults(cmd), util.Fatal)
-				err := options.RunResourceUpgrade(cmd)
+				// a task is not started outside of its maintenance window
+				err := upgrade.WaitToStart(options.ctx, &cr, options.clientset)
+				if upgrader.IsCancelled(err) {
+					klog.Infof("Upgrade of %s was cancelled", cr.Name)
+					continue
+				}
+				util.CheckErr(err, cmdUtil.Fatal)
+				util.CheckErr(options.InitializeFromUpgradeTaskResource(cr), cmdUtil.Fatal)
+				util.CheckErr(options.RunPreFlightChecks(cmd), cmdUtil.Fatal)
+				util.CheckErr(options.RunResourceUpgradeChecks(cmd), cmdUtil.Fatal)
+				util.CheckErr(options.InitializeDefaults(cmd), cmdUtil.Fatal)
+				options.addPending(options.resourceKind, 1)
+				if options.dryRun {
+					util.CheckErr(options.RunResourceUpgrade(cmd), cmdUtil.Fatal)
+					continue
+				}
+				err = options.RunResourceUpgrade(cmd)
+				if upgrader.IsCancelled(err) {
+					// the upgrader failed the cancelled
+					// upgradetask, it is not retried
+					klog.Info(err)
+					continue
+				}
 				if err != nil {
 					utaskObj, uerr := client.OpenebsV1alpha1().UpgradeTasks(openebsNamespace).
 						Get(context.TODO(), cr.Name, metav1.GetOptions{})
 					if uerr != nil {
-						util.Fatal(uerr.Error())
+						cmdUtil.Fatal(uerr.Error())
 					}
-					backoffLimit, uerr := getBackoffLimit(openebsNamespace)
+					backoffLimit, uerr := getBackoffLimit(openebsNamespace, options.clientset.KubeClientset)
 					if uerr != nil {
-						util.Fatal(uerr.Error())
+						cmdUtil.Fatal(uerr.Error())
 					}
 					utaskObj.Status.Retries = utaskObj.Status.Retries + 1
 					if utaskObj.Status.Retries == backoffLimit {
@@ -95,26 +115,29 @@ func NewUpgradeResourceJob() *cobra.Command {
 					_, uerr = client.OpenebsV1alpha1().UpgradeTasks(openebsNamespace).
 						Update(context.TODO(), utaskObj, metav1.UpdateOptions{})
 					if uerr != nil {
-						util.Fatal(uerr.Error())
+						cmdUtil.Fatal(uerr.Error())
 					}
-					util.Fatal(err.Error())
+					cmdUtil.Fatal(err.Error())
 				} else {
 					utaskObj, uerr := client.OpenebsV1alpha1().UpgradeTasks(openebsNamespace).
 						Get(context.TODO(), cr.Name, metav1.GetOptions{})
 					if uerr != nil {
-						util.Fatal(uerr.Error())
+						cmdUtil.Fatal(uerr.Error())
 					}
 					utaskObj.Status.Phase = v1Alpha1API.UpgradeSuccess
 					utaskObj.Status.CompletedTime = metav1.Now()
 					_, uerr = client.OpenebsV1alpha1().UpgradeTasks(openebsNamespace).
 						Update(context.TODO(), utaskObj, metav1.UpdateOptions{})
 					if uerr != nil {
-						util.Fatal(uerr.Error())
+						cmdUtil.Fatal(uerr.Error())
 					}
 				}
 			}
 		},
 	}
+	addImagePullFlags(cmd)
+	addReplicaHealthFlags(cmd)
+	addImpactFlags(cmd)
 	return cmd
 }
 
@@ -141,6 +164,17 @@ func (u *UpgradeOptions) InitializeFromUpgradeTaskResource(
 		u.toVersionImageTag = upgradeTaskCRObj.Spec.ImageTag
 	}
 
+	// the image mapping is not part of the UpgradeTask spec, it is read
+	// from the annotation and falls back to the image mapping file
+	u.imageMapping = nil
+	if mapping := upgradeTaskCRObj.Annotations[imageMappingAnnotation]; mapping != "" {
+		m, err := upgrader.ParseImageMapping([]byte(mapping))
+		if err != nil {
+			return errors.Wrapf(err, "Cannot execute upgrade job: invalid %s annotation", imageMappingAnnotation)
+		}
+		u.imageMapping = m
+	}
+
 	switch {
 	case upgradeTaskCRObj.Spec.ResourceSpec.CStorPoolInstance != nil:
 		u.resourceKind = "cstorPoolInstance"
@@ -174,44 +208,18 @@ func (u *UpgradeOptions) RunResourceUpgradeChecks(cmd *cobra.Command) error {
 
 // RunResourceUpgrade upgrades the given upgradeTask
 func (u *UpgradeOptions) RunResourceUpgrade(cmd *cobra.Command) error {
-	if version.IsCurrentVersionValid(u.fromVersion) && version.IsDesiredVersionValid(u.toVersion) {
-		klog.Infof("Upgrading %s from %s to %s", u.resourceKind, u.fromVersion, u.toVersion)
-		err := upgrade.Exec(u.fromVersion, u.toVersion,
-			u.resourceKind,
-			u.name,
-			u.openebsNamespace,
-			u.imageURLPrefix,
-			u.toVersionImageTag)
-		if err != nil {
-			return errors.Wrapf(err, "Failed to upgrade %v %v", u.resourceKind, u.name)
-		}
-	} else {
-		return errors.Errorf("Invalid from version %s or to version %s", u.fromVersion, u.toVersion)
-	}
-	return nil
-}
-
-func initClient() (openebsclientset.Interface, error) {
-	cfg, err := rest.InClusterConfig()
-	if err != nil {
-		return nil, errors.Wrap(err, "error building kubeconfig")
+	u.logImpact(u.name)
+	if u.dryRun {
+		return u.RunDryRun(u.name)
 	}
-	client, err := openebsclientset.NewForConfig(cfg)
+	_, err := u.execUpgrade(u.name)
 	if err != nil {
-		return nil, errors.Wrap(err, "error building openebs clientset")
+		return errors.Wrapf(err, "Failed to upgrade %v %v", u.resourceKind, u.name)
 	}
-	return client, nil
+	return nil
 }
 
-func getBackoffLimit(openebsNamespace string) (int, error) {
-	cfg, err := rest.InClusterConfig()
-	if err != nil {
-		return 0, errors.Wrap(err, "error building kubeconfig")
-	}
-	client, err := kubernetes.NewForConfig(cfg)
-	if err != nil {
-		return 0, errors.Wrap(err, "error building openebs clientset")
-	}
+func getBackoffLimit(openebsNamespace string, client kubernetes.Interface) (int, error) {
 	podName := os.Getenv("POD_NAME")
 	podObj, err := client.CoreV1().Pods(openebsNamespace).
 		Get(context.TODO(), podName, metav1.GetOptions{})
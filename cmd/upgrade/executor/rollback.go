@@ -0,0 +1,94 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"strings"
+
+	"github.com/openebs/maya/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	cmdUtil "github.com/openebs/upgrade/cmd/util"
+	upgrade "github.com/openebs/upgrade/pkg/upgrade"
+)
+
+var (
+	rollbackCmdHelpText = `
+This command restores a resource to the objects recorded before
+it was upgraded. The versions are taken from the recorded backup.
+
+Supported kinds: cstor-cspc, cstor-cspi, cstor-volume, jiva-volume
+
+Usage: upgrade rollback <kind> <name>...
+`
+
+	rollbackKinds = map[string]string{
+		"cstor-cspc":   "cstorPoolCluster",
+		"cstor-cspi":   "cstorPoolInstance",
+		"cstor-volume": "cstorVolume",
+		"jiva-volume":  "jivaVolume",
+	}
+)
+
+// NewRollbackJob rolls back the given resources to
+// the versions before the upgrade
+func NewRollbackJob() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rollback",
+		Short:   "Rollback an upgraded resource",
+		Long:    rollbackCmdHelpText,
+		Example: `upgrade rollback cstor-volume <volume-name>...`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 2 {
+				cmdUtil.Fatal("failed to rollback: resource kind and name are required")
+			}
+			kind, ok := rollbackKinds[args[0]]
+			if !ok {
+				cmdUtil.Fatal("failed to rollback: invalid resource kind " + args[0])
+			}
+			for _, name := range args[1:] {
+				options.resourceKind = kind
+				util.CheckErr(options.RunRollbackChecks(cmd), cmdUtil.Fatal)
+				util.CheckErr(options.RunRollback(cmd, name), cmdUtil.Fatal)
+			}
+		},
+	}
+	return cmd
+}
+
+// RunRollbackChecks will ensure the sanity of the rollback options
+func (u *UpgradeOptions) RunRollbackChecks(cmd *cobra.Command) error {
+	if len(strings.TrimSpace(u.openebsNamespace)) == 0 {
+		return errors.Errorf("Cannot execute rollback job: namespace is missing")
+	}
+	return nil
+}
+
+// RunRollback restores the given resource to the recorded objects
+func (u *UpgradeOptions) RunRollback(cmd *cobra.Command, name string) error {
+	klog.Infof("Rolling back %s %s", u.resourceKind, name)
+	err := upgrade.Rollback(u.resourceKind, name, u.openebsNamespace, u.clientset,
+		u.resourcePatchOptions()...)
+	if err != nil {
+		klog.Error(err)
+		return errors.Errorf("Failed to rollback %s %v", u.resourceKind, name)
+	}
+	klog.Infof("Successfully rolled back %s %s", u.resourceKind, name)
+	return nil
+}
@@ -21,7 +21,10 @@ import (
 	"os"
 	"strings"
 
+	"github.com/openebs/maya/pkg/util"
 	"github.com/spf13/cobra"
+
+	cmdUtil "github.com/openebs/upgrade/cmd/util"
 )
 
 // NewJob will setup a new upgrade job
@@ -40,17 +43,21 @@ func NewJob() *cobra.Command {
 		NewUpgradeCStorVolumeJob(),
 		NewUpgradeResourceJob(),
 		NewUpgradeJivaVolumeJob(),
+		NewRollbackJob(),
+		NewControllerCmd(),
+		NewGenerateCmd(),
+		NewImpactCmd(),
 	)
 
 	cmd.PersistentFlags().StringVarP(&options.fromVersion,
 		"from-version", "",
 		options.fromVersion,
-		"current version of the resource.")
+		"[optional] current version of the resource, read from each resource if not provided.")
 
 	cmd.PersistentFlags().StringVarP(&options.toVersion,
 		"to-version", "",
 		options.toVersion,
-		"new version to which resource should be upgraded.")
+		"[optional] new version to which resource should be upgraded, defaults to the version of the operator managing the resource.")
 
 	cmd.PersistentFlags().StringVarP(&options.openebsNamespace,
 		"openebs-namespace", "",
@@ -60,13 +67,93 @@ func NewJob() *cobra.Command {
 	cmd.PersistentFlags().StringVarP(&options.imageURLPrefix,
 		"to-version-image-prefix", "",
 		options.imageURLPrefix,
-		"[optional] custom image prefix.")
+		"[optional] custom image prefix, used for the images not matching any registry rule.")
 
 	cmd.PersistentFlags().StringVarP(&options.toVersionImageTag,
 		"to-version-image-tag", "",
 		options.toVersionImageTag,
 		"[optional] custom image tag. If not specified, to-version will be used")
 
+	cmd.PersistentFlags().StringVarP(&options.registryRulesFile,
+		"registry-rules-file", "",
+		options.registryRulesFile,
+		"[optional] yaml file with the ordered rules rewriting the image repositories, the first matching rule is applied.")
+
+	cmd.PersistentFlags().StringVarP(&options.registryRulesConfigMap,
+		"registry-rules-configmap", "",
+		options.registryRulesConfigMap,
+		"[optional] configmap in the openebs namespace with the registry rules under the "+registryRulesConfigMapKey+" key.")
+
+	cmd.PersistentFlags().StringVarP(&options.policyFile,
+		"upgrade-policy-file", "",
+		options.policyFile,
+		"[optional] yaml file with the policy validating the upgrade paths, replacing the default policy.")
+
+	cmd.PersistentFlags().StringVarP(&options.policyConfigMap,
+		"upgrade-policy-configmap", "",
+		options.policyConfigMap,
+		"[optional] configmap in the openebs namespace with the upgrade policy under the "+cmdUtil.PolicyConfigMapKey+" key.")
+
+	cmd.PersistentFlags().StringVarP(&options.imageMappingFile,
+		"image-mapping-file", "",
+		options.imageMappingFile,
+		"[optional] yaml file overriding the tag, digest or image of containers by container name or image repository.")
+
+	cmd.PersistentFlags().BoolVarP(&options.dryRun,
+		"dry-run", "",
+		options.dryRun,
+		"[optional] print the patches for every resource without applying them.")
+
+	cmd.PersistentFlags().DurationVarP(&options.timeout,
+		"timeout", "",
+		options.timeout,
+		"[optional] maximum time the job can take, 0 means no limit.")
+
+	cmd.PersistentFlags().DurationVarP(&options.stepTimeout,
+		"step-timeout", "",
+		options.stepTimeout,
+		"[optional] maximum time each step of a resource upgrade can take, 0 means no limit.")
+
+	cmd.PersistentFlags().StringVarP(&options.metricsAddr,
+		"metrics-addr", "",
+		options.metricsAddr,
+		"[optional] address to serve the prometheus metrics on, like :9500. The metrics are not served if not specified.")
+
+	cmd.PersistentFlags().StringVarP(&options.metricsPushgateway,
+		"metrics-pushgateway", "",
+		options.metricsPushgateway,
+		"[optional] url of a Pushgateway to push the prometheus metrics to when the job exits.")
+
+	cmd.PersistentFlags().StringVarP(&options.output,
+		"output", "o",
+		options.output,
+		"[optional] print a report of the run when the job exits in the given format, json or yaml.")
+
+	cmd.PersistentFlags().StringVarP(&options.reportFile,
+		"report-file", "",
+		options.reportFile,
+		"[optional] write the report of the run to the given file instead of stdout, in json if no output format is given.")
+
+	cmd.PersistentFlags().StringVarP(&options.clientOptions.KubeConfig,
+		"kubeconfig", "",
+		options.clientOptions.KubeConfig,
+		"[optional] path to the kubeconfig file. If not specified, in-cluster config will be used")
+
+	cmd.PersistentFlags().StringVarP(&options.clientOptions.Context,
+		"context", "",
+		options.clientOptions.Context,
+		"[optional] name of the kubeconfig context to use.")
+
+	cmd.PersistentFlags().StringVarP(&options.clientOptions.Impersonate,
+		"as", "",
+		options.clientOptions.Impersonate,
+		"[optional] username to impersonate for the operation.")
+
+	cmd.PersistentFlags().StringSliceVarP(&options.clientOptions.ImpersonateGroups,
+		"as-group", "",
+		options.clientOptions.ImpersonateGroups,
+		"[optional] group to impersonate for the operation, this flag can be repeated to specify multiple groups.")
+
 	cmd.PersistentFlags().AddGoFlagSet(flag.CommandLine)
 
 	// Hack: Without the following line, the logs will be prefixed with Error
@@ -75,10 +162,42 @@ func NewJob() *cobra.Command {
 	return cmd
 }
 
+// addImagePullFlags adds the flags pulling the new images
+// with probe pods before the workloads are patched
+func addImagePullFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&options.imagePullCheck,
+		"check-image-pull", "",
+		options.imagePullCheck,
+		"[optional] pull the new images on the nodes of the pods with a probe pod before patching them.")
+	cmd.Flags().DurationVarP(&options.imagePullTimeout,
+		"image-pull-timeout", "",
+		options.imagePullTimeout,
+		"[optional] maximum time the new images can take to be pulled by the probe pods.")
+}
+
+// addReplicaHealthFlags adds the flags controlling the wait for the
+// volume replicas to be healthy around the restart of the pools and
+// of the jiva controllers
+func addReplicaHealthFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&options.skipReplicaHealthCheck,
+		"skip-replica-health-check", "",
+		options.skipReplicaHealthCheck,
+		"[optional] restart the cstor pool instances and the jiva controllers without waiting for the volume replicas to be healthy.")
+	cmd.Flags().DurationVarP(&options.replicaHealthTimeout,
+		"replica-health-timeout", "",
+		options.replicaHealthTimeout,
+		"[optional] maximum time to wait for the replicas of the volumes on a cstor pool instance, or of a jiva volume, to be healthy, 0 means no limit.")
+}
+
 // PreRun will check for environement variables to be read and intialized.
 func PreRun(cmd *cobra.Command, args []string) {
 	namespace := os.Getenv("OPENEBS_NAMESPACE")
 	if len(strings.TrimSpace(namespace)) != 0 {
 		options.openebsNamespace = namespace
 	}
+	util.CheckErr(options.InitializeClients(), util.Fatal)
+	util.CheckErr(options.InitializePolicy(), util.Fatal)
+	util.CheckErr(options.InitializeMetrics(), util.Fatal)
+	util.CheckErr(options.InitializeReport(), util.Fatal)
+	options.InitializeContext()
 }
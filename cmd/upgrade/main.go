@@ -19,6 +19,7 @@ package main
 import (
 	mlogger "github.com/openebs/maya/pkg/logs"
 	"github.com/openebs/upgrade/cmd/upgrade/executor"
+	"github.com/openebs/upgrade/cmd/util"
 )
 
 func main() {
@@ -27,5 +28,6 @@ func main() {
 	defer mlogger.FlushLogs()
 
 	err := executor.NewJob().Execute()
+	util.RunExitHandlers()
 	executor.CheckError(err)
 }
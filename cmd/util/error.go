@@ -20,12 +20,44 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
+
+	mutil "github.com/openebs/maya/pkg/util"
+)
+
+var (
+	exitHandlers []func()
+	exitOnce     sync.Once
 )
 
+// OnExit registers a function to be run before the job exits,
+// like pushing the metrics of the job
+func OnExit(f func()) {
+	exitHandlers = append(exitHandlers, f)
+}
+
+// RunExitHandlers runs the functions registered with OnExit,
+// they are run only once even if called again
+func RunExitHandlers() {
+	exitOnce.Do(func() {
+		for _, f := range exitHandlers {
+			f()
+		}
+	})
+}
+
+// Fatal runs the exit handlers and exits with
+// the given message like util.Fatal of maya
+func Fatal(msg string) {
+	RunExitHandlers()
+	mutil.Fatal(msg)
+}
+
 // CheckError prints err to stderr and exits with code 1 if err is not nil. Otherwise, it is a
 // no-op.
 func CheckError(err error) {
 	if err != nil {
+		RunExitHandlers()
 		if err != context.Canceled {
 			fmt.Fprintf(os.Stderr, fmt.Sprintf("An error occurred: %v\n", err))
 		}
@@ -0,0 +1,65 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"os"
+
+	errors "github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openebs/upgrade/pkg/version"
+)
+
+const (
+	// PolicyConfigMapKey is the key of the
+	// upgrade policy in the configmap
+	PolicyConfigMapKey = "upgrade-policy.yaml"
+)
+
+// LoadPolicy reads the upgrade policy from the file or the
+// configmap in the given namespace, at most one can be given.
+// The default policy is returned when neither is given.
+func LoadPolicy(client kubernetes.Interface, namespace, file, configMap string) (version.Policy, error) {
+	var data []byte
+	var err error
+	switch {
+	case file != "" && configMap != "":
+		return version.Policy{}, errors.Errorf("only one of upgrade-policy-file or upgrade-policy-configmap can be set")
+	case file != "":
+		data, err = os.ReadFile(file)
+		if err != nil {
+			return version.Policy{}, errors.Wrap(err, "failed to read upgrade policy")
+		}
+	case configMap != "":
+		cm, err := client.CoreV1().ConfigMaps(namespace).
+			Get(context.TODO(), configMap, metav1.GetOptions{})
+		if err != nil {
+			return version.Policy{}, errors.Wrapf(err, "failed to get upgrade policy configmap %s", configMap)
+		}
+		policy, ok := cm.Data[PolicyConfigMapKey]
+		if !ok {
+			return version.Policy{}, errors.Errorf("configmap %s has no %s key", cm.Name, PolicyConfigMapKey)
+		}
+		data = []byte(policy)
+	default:
+		return version.DefaultPolicy, nil
+	}
+	return version.ParsePolicy(data)
+}
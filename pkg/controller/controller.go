@@ -0,0 +1,110 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openebs/upgrade/pkg/schedule"
+	"github.com/openebs/upgrade/pkg/upgrade/upgrader"
+)
+
+const (
+	// retryAfterAnnotation is set on a failed task with the time
+	// of the next attempt, the updates of the task before that
+	// time do not trigger another attempt
+	retryAfterAnnotation = "openebs.io/retry-after"
+
+	// DefaultMaxRetries is the number of attempts after which
+	// a task is marked as failed, same as the default
+	// backoffLimit of a Job
+	DefaultMaxRetries = upgrader.DefaultMaxRetries
+
+	baseRetryInterval = 10 * time.Second
+	maxRetryInterval  = 5 * time.Minute
+
+	// pauseInterval is the interval between two
+	// reconciles of a paused UpgradeTask
+	pauseInterval = 10 * time.Second
+)
+
+// Options configures the reconcilers of the tasks
+type Options struct {
+	// Concurrency is the number of tasks of each
+	// kind which can be processed at once
+	Concurrency int
+	// MaxRetries is the number of attempts after
+	// which a task is marked as failed
+	MaxRetries int
+}
+
+// retryInterval returns the time to wait before the next attempt, it
+// doubles with every failed attempt up to the maximum interval
+func retryInterval(retries int) time.Duration {
+	d := baseRetryInterval
+	for i := 1; i < retries && d < maxRetryInterval; i++ {
+		d *= 2
+	}
+	if d > maxRetryInterval {
+		return maxRetryInterval
+	}
+	return d
+}
+
+// waitForRetry returns the time left before the next attempt
+// of a task recorded in its annotations
+func waitForRetry(obj metav1.Object, now time.Time) time.Duration {
+	value, ok := obj.GetAnnotations()[retryAfterAnnotation]
+	if !ok {
+		return 0
+	}
+	retryAfter, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0
+	}
+	return retryAfter.Sub(now)
+}
+
+// waitForWindow returns the time left before the maintenance window
+// of a task opens, the reconcile is requeued until then instead of
+// blocking a worker
+func waitForWindow(obj metav1.Object, now time.Time) (time.Duration, error) {
+	next, err := schedule.NextOpen(obj.GetAnnotations(), now)
+	if err != nil || next.IsZero() {
+		return 0, err
+	}
+	return next.Sub(now), nil
+}
+
+// setRetryAfter records the time of the next attempt of a task
+func setRetryAfter(obj metav1.Object, after time.Time) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[retryAfterAnnotation] = after.UTC().Format(time.RFC3339)
+	obj.SetAnnotations(annotations)
+}
+
+// clearRetryAfter removes the time of the next attempt of a task
+func clearRetryAfter(obj metav1.Object) {
+	annotations := obj.GetAnnotations()
+	delete(annotations, retryAfterAnnotation)
+	obj.SetAnnotations(annotations)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	openebsclientset "github.com/openebs/api/v3/pkg/client/clientset/versioned"
+	"github.com/pkg/errors"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	migrate "github.com/openebs/upgrade/pkg/migrate/cstor"
+)
+
+// MigrateFunc migrates the resource of the given MigrationTask
+type MigrateFunc func(ctx context.Context, mtaskObj *v1Alpha1API.MigrationTask) error
+
+// MigrationTaskReconciler runs the migration of the resource of every
+// MigrationTask which is not completed and records the result on it,
+// it is retried like the upgrades
+type MigrationTaskReconciler struct {
+	Clientset openebsclientset.Interface
+	Migrate   MigrateFunc
+	Options
+}
+
+// SetupWithManager registers the reconciler with the manager
+func (r *MigrationTaskReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1Alpha1API.MigrationTask{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.Concurrency}).
+		Complete(r)
+}
+
+// Reconcile migrates the resource of the MigrationTask
+func (r *MigrationTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	mtaskObj, err := r.Clientset.OpenebsV1alpha1().MigrationTasks(req.Namespace).
+		Get(ctx, req.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if mtaskObj.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+	switch mtaskObj.Status.Phase {
+	case v1Alpha1API.MigrateSuccess, v1Alpha1API.MigrateError:
+		return ctrl.Result{}, nil
+	}
+	if wait := waitForRetry(mtaskObj, time.Now()); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+	err = validateMigrationTask(mtaskObj)
+	if err != nil {
+		klog.Errorf("Cannot migrate the resource of %s: %v", mtaskObj.Name, err)
+		return ctrl.Result{}, r.complete(ctx, mtaskObj, v1Alpha1API.MigrateError)
+	}
+	wait, err := waitForWindow(mtaskObj, time.Now())
+	if err != nil {
+		klog.Errorf("Invalid maintenance window of %s: %v", mtaskObj.Name, err)
+		return r.retry(ctx, mtaskObj)
+	}
+	if wait > 0 {
+		klog.Infof("Migrationtask %s is waiting %s for its maintenance window", mtaskObj.Name, wait)
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	klog.Infof("Processing migrationtask %s", mtaskObj.Name)
+	err = r.Migrate(ctx, mtaskObj)
+	if err == nil {
+		klog.Infof("Migrationtask %s completed", mtaskObj.Name)
+		return ctrl.Result{}, r.complete(ctx, mtaskObj, v1Alpha1API.MigrateSuccess)
+	}
+	if ctx.Err() != nil {
+		return ctrl.Result{}, nil
+	}
+	klog.Errorf("Migrationtask %s failed: %v", mtaskObj.Name, err)
+	return r.retry(ctx, mtaskObj)
+}
+
+// validateMigrationTask checks that the MigrationTask has a resource
+// and the name the migration records its steps on
+func validateMigrationTask(mtaskObj *v1Alpha1API.MigrationTask) error {
+	var kind, name string
+	switch {
+	case mtaskObj.Spec.MigrateCStorPool != nil:
+		kind, name = "cstorPool", mtaskObj.Spec.MigrateCStorPool.SPCName
+	case mtaskObj.Spec.MigrateCStorVolume != nil:
+		kind, name = "cstorVolume", mtaskObj.Spec.MigrateCStorVolume.PVName
+	}
+	if kind == "" || name == "" {
+		return errors.Errorf("no resource to migrate")
+	}
+	if want := migrate.MigrationTaskName(kind, name); mtaskObj.Name != want {
+		return errors.Errorf("the migrationtask of %s %s should be named %s", kind, name, want)
+	}
+	return nil
+}
+
+// complete records the final phase of the MigrationTask
+func (r *MigrationTaskReconciler) complete(ctx context.Context,
+	mtaskObj *v1Alpha1API.MigrationTask, phase v1Alpha1API.MigratePhase) error {
+	return r.update(ctx, mtaskObj, func(m *v1Alpha1API.MigrationTask) {
+		m.Status.Phase = phase
+		m.Status.CompletedTime = metav1.Now()
+		clearRetryAfter(m)
+	})
+}
+
+// retry records the failed attempt on the MigrationTask and schedules
+// the next one, the task fails once the maximum retries are reached
+func (r *MigrationTaskReconciler) retry(ctx context.Context,
+	mtaskObj *v1Alpha1API.MigrationTask) (ctrl.Result, error) {
+	var result ctrl.Result
+	err := r.update(ctx, mtaskObj, func(m *v1Alpha1API.MigrationTask) {
+		m.Status.Retries++
+		if m.Status.Retries >= r.MaxRetries {
+			m.Status.Phase = v1Alpha1API.MigrateError
+			m.Status.CompletedTime = metav1.Now()
+			clearRetryAfter(m)
+			result = ctrl.Result{}
+			return
+		}
+		interval := retryInterval(m.Status.Retries)
+		setRetryAfter(m, time.Now().Add(interval))
+		result = ctrl.Result{RequeueAfter: interval}
+	})
+	return result, err
+}
+
+// update applies the change to the latest MigrationTask
+func (r *MigrationTaskReconciler) update(ctx context.Context,
+	mtaskObj *v1Alpha1API.MigrationTask, change func(*v1Alpha1API.MigrationTask)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := r.Clientset.OpenebsV1alpha1().MigrationTasks(mtaskObj.Namespace).
+			Get(ctx, mtaskObj.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		change(latest)
+		_, err = r.Clientset.OpenebsV1alpha1().MigrationTasks(latest.Namespace).
+			Update(ctx, latest, metav1.UpdateOptions{})
+		return err
+	})
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	openebsclientset "github.com/openebs/api/v3/pkg/client/clientset/versioned"
+	"github.com/pkg/errors"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	"github.com/openebs/upgrade/pkg/upgrade/upgrader"
+)
+
+// UpgradeFunc upgrades the resource of the given UpgradeTask
+type UpgradeFunc func(ctx context.Context, utaskObj *v1Alpha1API.UpgradeTask) error
+
+// UpgradeTaskReconciler runs the upgrade of the resource of every
+// UpgradeTask which is not completed and records the result on it.
+// A failed upgrade is retried with a backoff until the maximum
+// retries, the UpgradeTask is then marked as failed.
+type UpgradeTaskReconciler struct {
+	Clientset openebsclientset.Interface
+	Upgrade   UpgradeFunc
+	Options
+}
+
+// SetupWithManager registers the reconciler with the manager
+func (r *UpgradeTaskReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1Alpha1API.UpgradeTask{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.Concurrency}).
+		Complete(r)
+}
+
+// Reconcile upgrades the resource of the UpgradeTask, the upgrade
+// runs in the reconcile so that the concurrency bounds the upgrades
+func (r *UpgradeTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	utaskObj, err := r.Clientset.OpenebsV1alpha1().UpgradeTasks(req.Namespace).
+		Get(ctx, req.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !r.pending(utaskObj) {
+		return ctrl.Result{}, nil
+	}
+	if wait := waitForRetry(utaskObj, time.Now()); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+	err = validateUpgradeTask(utaskObj)
+	if err != nil {
+		klog.Errorf("Cannot upgrade the resource of %s: %v", utaskObj.Name, err)
+		return ctrl.Result{}, r.complete(ctx, utaskObj, v1Alpha1API.UpgradeError)
+	}
+	switch utaskObj.Annotations[upgrader.UpgradeControlAnnotation] {
+	case upgrader.ControlCancel:
+		klog.Infof("Upgradetask %s was cancelled", utaskObj.Name)
+		return ctrl.Result{}, r.complete(ctx, utaskObj, v1Alpha1API.UpgradeError)
+	case upgrader.ControlPause:
+		klog.V(4).Infof("Upgradetask %s is paused", utaskObj.Name)
+		return ctrl.Result{RequeueAfter: pauseInterval}, nil
+	}
+	wait, err := waitForWindow(utaskObj, time.Now())
+	if err != nil {
+		klog.Errorf("Invalid maintenance window of %s: %v", utaskObj.Name, err)
+		return r.retry(ctx, utaskObj)
+	}
+	if wait > 0 {
+		klog.Infof("Upgradetask %s is waiting %s for its maintenance window", utaskObj.Name, wait)
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	klog.Infof("Processing upgradetask %s", utaskObj.Name)
+	err = r.Upgrade(ctx, utaskObj)
+	if err == nil {
+		klog.Infof("Upgradetask %s completed", utaskObj.Name)
+		return ctrl.Result{}, r.complete(ctx, utaskObj, v1Alpha1API.UpgradeSuccess)
+	}
+	if ctx.Err() != nil {
+		// the controller is stopping, the upgrade
+		// resumes when the controller is restarted
+		return ctrl.Result{}, nil
+	}
+	if upgrader.IsCancelled(err) {
+		klog.Infof("Upgradetask %s was cancelled", utaskObj.Name)
+		return ctrl.Result{}, r.complete(ctx, utaskObj, v1Alpha1API.UpgradeError)
+	}
+	klog.Errorf("Upgradetask %s failed: %v", utaskObj.Name, err)
+	return r.retry(ctx, utaskObj)
+}
+
+// pending returns true if the UpgradeTask has to be processed, the
+// UpgradeTasks of the cspis of a CSPC are processed with the CSPC
+func (r *UpgradeTaskReconciler) pending(utaskObj *v1Alpha1API.UpgradeTask) bool {
+	if utaskObj.DeletionTimestamp != nil {
+		return false
+	}
+	if _, ok := utaskObj.Labels[upgrader.ParentUpgradeTaskLabel]; ok {
+		return false
+	}
+	switch utaskObj.Status.Phase {
+	case v1Alpha1API.UpgradeSuccess, v1Alpha1API.UpgradeError:
+		return false
+	}
+	return true
+}
+
+// validateUpgradeTask checks that the UpgradeTask has a resource and
+// the name the upgrade records its steps on
+func validateUpgradeTask(utaskObj *v1Alpha1API.UpgradeTask) error {
+	kind, name := upgradeTaskResource(utaskObj)
+	if kind == "" || name == "" {
+		return errors.Errorf("no resource to upgrade")
+	}
+	if want := upgrader.UpgradeTaskName(kind, name); utaskObj.Name != want {
+		return errors.Errorf("the upgradetask of %s %s should be named %s", kind, name, want)
+	}
+	return nil
+}
+
+// upgradeTaskResource returns the kind and the name
+// of the resource upgraded by the UpgradeTask
+func upgradeTaskResource(utaskObj *v1Alpha1API.UpgradeTask) (string, string) {
+	spec := utaskObj.Spec.ResourceSpec
+	switch {
+	case spec.CStorPoolCluster != nil:
+		return "cstorPoolCluster", spec.CStorPoolCluster.CSPCName
+	case spec.CStorPoolInstance != nil:
+		return "cstorPoolInstance", spec.CStorPoolInstance.CSPIName
+	case spec.CStorVolume != nil:
+		return "cstorVolume", spec.CStorVolume.PVName
+	case spec.JivaVolume != nil:
+		return "jivaVolume", spec.JivaVolume.PVName
+	}
+	return "", ""
+}
+
+// complete records the final phase of the UpgradeTask
+func (r *UpgradeTaskReconciler) complete(ctx context.Context,
+	utaskObj *v1Alpha1API.UpgradeTask, phase v1Alpha1API.UpgradePhase) error {
+	return r.update(ctx, utaskObj, func(u *v1Alpha1API.UpgradeTask) {
+		u.Status.Phase = phase
+		u.Status.CompletedTime = metav1.Now()
+		clearRetryAfter(u)
+	})
+}
+
+// retry records the failed attempt on the UpgradeTask and schedules
+// the next one, the task fails once the maximum retries are reached
+func (r *UpgradeTaskReconciler) retry(ctx context.Context,
+	utaskObj *v1Alpha1API.UpgradeTask) (ctrl.Result, error) {
+	var result ctrl.Result
+	err := r.update(ctx, utaskObj, func(u *v1Alpha1API.UpgradeTask) {
+		u.Status.Retries++
+		if u.Status.Retries >= r.MaxRetries {
+			u.Status.Phase = v1Alpha1API.UpgradeError
+			u.Status.CompletedTime = metav1.Now()
+			clearRetryAfter(u)
+			result = ctrl.Result{}
+			return
+		}
+		interval := retryInterval(u.Status.Retries)
+		setRetryAfter(u, time.Now().Add(interval))
+		result = ctrl.Result{RequeueAfter: interval}
+	})
+	return result, err
+}
+
+// update applies the change to the latest UpgradeTask, the
+// upgrade updates the statuses of the task while it runs
+func (r *UpgradeTaskReconciler) update(ctx context.Context,
+	utaskObj *v1Alpha1API.UpgradeTask, change func(*v1Alpha1API.UpgradeTask)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := r.Clientset.OpenebsV1alpha1().UpgradeTasks(utaskObj.Namespace).
+			Get(ctx, utaskObj.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		change(latest)
+		_, err = r.Clientset.OpenebsV1alpha1().UpgradeTasks(latest.Namespace).
+			Update(ctx, latest, metav1.UpdateOptions{})
+		return err
+	})
+}
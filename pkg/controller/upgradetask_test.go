@@ -0,0 +1,186 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	openebsFakeClientset "github.com/openebs/api/v3/pkg/client/clientset/versioned/fake"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/openebs/upgrade/pkg/schedule"
+	"github.com/openebs/upgrade/pkg/upgrade/upgrader"
+)
+
+func fakeUpgradeTask(name, pvName string) *v1Alpha1API.UpgradeTask {
+	return &v1Alpha1API.UpgradeTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "openebs",
+		},
+		Spec: v1Alpha1API.UpgradeTaskSpec{
+			ResourceSpec: v1Alpha1API.ResourceSpec{
+				CStorVolume: &v1Alpha1API.CStorVolume{PVName: pvName},
+			},
+		},
+	}
+}
+
+func TestUpgradeTaskReconciler(t *testing.T) {
+	tests := map[string]struct {
+		utask      *v1Alpha1API.UpgradeTask
+		upgradeErr error
+		maxRetries int
+		wantCalls  int
+		wantPhase  v1Alpha1API.UpgradePhase
+		wantRetry  bool
+		// wantRequeue is set if the task is requeued
+		// without recording a failed attempt
+		wantRequeue bool
+	}{
+		"upgrade succeeds": {
+			utask:      fakeUpgradeTask("upgrade-cstor-csi-volume-pv-1", "pv-1"),
+			maxRetries: 3,
+			wantCalls:  1,
+			wantPhase:  v1Alpha1API.UpgradeSuccess,
+		},
+		"upgrade fails and is retried": {
+			utask:      fakeUpgradeTask("upgrade-cstor-csi-volume-pv-1", "pv-1"),
+			upgradeErr: errors.New("failed"),
+			maxRetries: 3,
+			wantCalls:  1,
+			wantRetry:  true,
+		},
+		"upgrade fails at the last retry": {
+			utask:      fakeUpgradeTask("upgrade-cstor-csi-volume-pv-1", "pv-1"),
+			upgradeErr: errors.New("failed"),
+			maxRetries: 1,
+			wantCalls:  1,
+			wantPhase:  v1Alpha1API.UpgradeError,
+		},
+		"name does not match the resource": {
+			utask:      fakeUpgradeTask("upgrade-pv-1", "pv-1"),
+			maxRetries: 3,
+			wantPhase:  v1Alpha1API.UpgradeError,
+		},
+		"completed task is skipped": {
+			utask: func() *v1Alpha1API.UpgradeTask {
+				u := fakeUpgradeTask("upgrade-cstor-csi-volume-pv-1", "pv-1")
+				u.Status.Phase = v1Alpha1API.UpgradeSuccess
+				return u
+			}(),
+			maxRetries: 3,
+			wantPhase:  v1Alpha1API.UpgradeSuccess,
+		},
+		"paused task is requeued": {
+			utask: func() *v1Alpha1API.UpgradeTask {
+				u := fakeUpgradeTask("upgrade-cstor-csi-volume-pv-1", "pv-1")
+				u.Annotations = map[string]string{upgrader.UpgradeControlAnnotation: upgrader.ControlPause}
+				return u
+			}(),
+			maxRetries:  3,
+			wantRequeue: true,
+		},
+		"cancelled task fails": {
+			utask: func() *v1Alpha1API.UpgradeTask {
+				u := fakeUpgradeTask("upgrade-cstor-csi-volume-pv-1", "pv-1")
+				u.Annotations = map[string]string{upgrader.UpgradeControlAnnotation: upgrader.ControlCancel}
+				return u
+			}(),
+			maxRetries: 3,
+			wantPhase:  v1Alpha1API.UpgradeError,
+		},
+		"task outside of its window is requeued": {
+			utask: func() *v1Alpha1API.UpgradeTask {
+				u := fakeUpgradeTask("upgrade-cstor-csi-volume-pv-1", "pv-1")
+				// a one minute window at the start of the year
+				u.Annotations = map[string]string{schedule.WindowAnnotation: "0 0 1 1 * 1m"}
+				return u
+			}(),
+			maxRetries:  3,
+			wantRequeue: true,
+		},
+		"cspi task of a cspc is skipped": {
+			utask: func() *v1Alpha1API.UpgradeTask {
+				u := fakeUpgradeTask("upgrade-cstor-csi-volume-pv-1", "pv-1")
+				u.Labels = map[string]string{upgrader.ParentUpgradeTaskLabel: "upgrade-cstor-cspc-cspc-1"}
+				return u
+			}(),
+			maxRetries: 3,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			clientset := openebsFakeClientset.NewSimpleClientset(test.utask)
+			r := &UpgradeTaskReconciler{
+				Clientset: clientset,
+				Upgrade: func(ctx context.Context, utaskObj *v1Alpha1API.UpgradeTask) error {
+					calls++
+					return test.upgradeErr
+				},
+				Options: Options{Concurrency: 1, MaxRetries: test.maxRetries},
+			}
+			result, err := r.Reconcile(context.TODO(), ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: test.utask.Namespace,
+					Name:      test.utask.Name,
+				},
+			})
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if calls != test.wantCalls {
+				t.Errorf("Reconcile() upgrade calls = %d, want %d", calls, test.wantCalls)
+			}
+			if (result.RequeueAfter > 0) != (test.wantRetry || test.wantRequeue) {
+				t.Errorf("Reconcile() requeue after = %v, want retry %v", result.RequeueAfter, test.wantRetry)
+			}
+			got, err := clientset.OpenebsV1alpha1().UpgradeTasks(test.utask.Namespace).
+				Get(context.TODO(), test.utask.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get upgradetask: %v", err)
+			}
+			if got.Status.Phase != test.wantPhase {
+				t.Errorf("Reconcile() phase = %q, want %q", got.Status.Phase, test.wantPhase)
+			}
+			if test.wantRetry && waitForRetry(got, time.Now()) <= 0 {
+				t.Errorf("Reconcile() did not record the next attempt on the upgradetask")
+			}
+		})
+	}
+}
+
+func Test_retryInterval(t *testing.T) {
+	tests := map[int]time.Duration{
+		1:  baseRetryInterval,
+		2:  2 * baseRetryInterval,
+		3:  4 * baseRetryInterval,
+		20: maxRetryInterval,
+	}
+	for retries, want := range tests {
+		if got := retryInterval(retries); got != want {
+			t.Errorf("retryInterval(%d) = %v, want %v", retries, got, want)
+		}
+	}
+}
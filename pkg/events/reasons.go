@@ -0,0 +1,50 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+// Reasons of the events emitted when a step of
+// an upgrade starts, completes or fails
+const (
+	UpgradeStarted             = "UpgradeStarted"
+	PreUpgradeCompleted        = "PreUpgradeCompleted"
+	ReplicaUpgradeStarted      = "ReplicaUpgradeStarted"
+	ReplicaUpgraded            = "ReplicaUpgraded"
+	TargetUpgradeStarted       = "TargetUpgradeStarted"
+	TargetPatched              = "TargetPatched"
+	PoolInstanceUpgradeStarted = "PoolInstanceUpgradeStarted"
+	PoolInstanceUpgraded       = "PoolInstanceUpgraded"
+	VerifyStarted              = "VerifyStarted"
+	UpgradeVerified            = "UpgradeVerified"
+	RollbackStarted            = "RollbackStarted"
+	RolledBack                 = "RolledBack"
+	UpgradePaused              = "UpgradePaused"
+	UpgradeResumed             = "UpgradeResumed"
+	MaintenanceWindowWaiting   = "MaintenanceWindowWaiting"
+	MaintenanceWindowOpened    = "MaintenanceWindowOpened"
+	UpgradeFailed              = "UpgradeFailed"
+	RollbackFailed             = "RollbackFailed"
+)
+
+// Reasons of the events emitted when a step of
+// a migration starts, completes or fails
+const (
+	MigrationStarted      = "MigrationStarted"
+	PreMigrationCompleted = "PreMigrationCompleted"
+	MigrateStarted        = "MigrateStarted"
+	Migrated              = "Migrated"
+	MigrationFailed       = "MigrationFailed"
+)
@@ -0,0 +1,112 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/tools/reference"
+	"k8s.io/klog/v2"
+)
+
+// Recorder is an EventRecorder which creates the events before
+// returning. The upgrade and migrate jobs exit as soon as they are
+// done, so the events of the last step would be lost if they were
+// queued like the events of the broadcaster of client-go.
+type Recorder struct {
+	client    kubernetes.Interface
+	scheme    *runtime.Scheme
+	component string
+	host      string
+}
+
+var _ record.EventRecorder = &Recorder{}
+
+// NewRecorder returns a recorder creating the events with the given
+// client, the scheme is used to get the references of the objects
+func NewRecorder(client kubernetes.Interface, scheme *runtime.Scheme, component string) *Recorder {
+	host, _ := os.Hostname()
+	return &Recorder{
+		client:    client,
+		scheme:    scheme,
+		component: component,
+		host:      host,
+	}
+}
+
+// Event creates an event for the object, a failure
+// to create the event is only logged
+func (r *Recorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.generateEvent(object, nil, eventtype, reason, message)
+}
+
+// Eventf is just like Event, but with Sprintf for the message field.
+func (r *Recorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.generateEvent(object, nil, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf is just like eventf, but with annotations attached
+func (r *Recorder) AnnotatedEventf(object runtime.Object, annotations map[string]string,
+	eventtype, reason, messageFmt string, args ...interface{}) {
+	r.generateEvent(object, annotations, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *Recorder) generateEvent(object runtime.Object, annotations map[string]string,
+	eventtype, reason, message string) {
+	ref, err := reference.GetReference(r.scheme, object)
+	if err != nil {
+		klog.Errorf("failed to get the reference of %#v for event %s: %v", object, reason, err)
+		return
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		// the events of cluster scoped objects
+		// like the PVs go to the default namespace
+		namespace = metav1.NamespaceDefault
+	}
+	now := metav1.NewTime(time.Now())
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%v.%x", ref.Name, now.UnixNano()),
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+		InvolvedObject: *ref,
+		Reason:         reason,
+		Message:        message,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+		Type:           eventtype,
+		Source: corev1.EventSource{
+			Component: r.component,
+			Host:      r.host,
+		},
+	}
+	_, err = r.client.CoreV1().Events(namespace).Create(context.TODO(), event, metav1.CreateOptions{})
+	if err != nil {
+		klog.Errorf("failed to create event %s for %s %s: %v", reason, ref.Kind, ref.Name, err)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"testing"
+
+	cstor "github.com/openebs/api/v3/pkg/apis/cstor/v1"
+	openebsscheme "github.com/openebs/api/v3/pkg/client/clientset/versioned/scheme"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestRecorder_Event(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(openebsscheme.AddToScheme(scheme))
+	tests := []struct {
+		name          string
+		obj           runtime.Object
+		wantNamespace string
+		wantKind      string
+		wantVersion   string
+	}{
+		{
+			name: "cluster scoped pv",
+			obj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pvc-1", UID: types.UID("pv-uid")},
+			},
+			wantNamespace: metav1.NamespaceDefault,
+			wantKind:      "PersistentVolume",
+			wantVersion:   "v1",
+		},
+		{
+			name: "namespaced cspi",
+			obj: &cstor.CStorPoolInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: "cspc-stripe-k7cc", Namespace: "openebs", UID: types.UID("cspi-uid")},
+			},
+			wantNamespace: "openebs",
+			wantKind:      "CStorPoolInstance",
+			wantVersion:   "cstor.openebs.io/v1",
+		},
+		{
+			name: "object reference",
+			obj: &corev1.ObjectReference{
+				APIVersion: "openebs.io/v1alpha1",
+				Kind:       "StoragePoolClaim",
+				Name:       "spc-1",
+				UID:        types.UID("spc-uid"),
+			},
+			wantNamespace: metav1.NamespaceDefault,
+			wantKind:      "StoragePoolClaim",
+			wantVersion:   "openebs.io/v1alpha1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			r := NewRecorder(client, scheme, "openebs-upgrade")
+			r.Eventf(tt.obj, corev1.EventTypeNormal, UpgradeStarted, "Upgrading from %s to %s", "3.4.0", "3.5.0")
+			list, err := client.CoreV1().Events(tt.wantNamespace).List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("failed to list events: %v", err)
+			}
+			if len(list.Items) != 1 {
+				t.Fatalf("Eventf() created %d events in %s, want 1", len(list.Items), tt.wantNamespace)
+			}
+			e := list.Items[0]
+			if e.InvolvedObject.Kind != tt.wantKind || e.InvolvedObject.APIVersion != tt.wantVersion ||
+				e.InvolvedObject.UID == "" {
+				t.Errorf("Eventf() involved object = %+v, want %s %s", e.InvolvedObject, tt.wantVersion, tt.wantKind)
+			}
+			if e.Reason != UpgradeStarted || e.Message != "Upgrading from 3.4.0 to 3.5.0" ||
+				e.Source.Component != "openebs-upgrade" {
+				t.Errorf("Eventf() event = %+v", e)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// openebsNamespaceEnv is read by the jobs
+	// to find the openebs namespace
+	openebsNamespaceEnv = "OPENEBS_NAMESPACE"
+	// podNameEnv is read by the resource command to find
+	// the job running it and the backoffLimit of the job
+	podNameEnv = "POD_NAME"
+	// maxNameLength is the maximum length of
+	// the names of the generated objects
+	maxNameLength = 63
+)
+
+var (
+	invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// Options holds the details common to the generated manifests
+type Options struct {
+	// Operation is either upgrade or migrate, it is the name of
+	// the container and of the image run by the jobs
+	Operation string
+	// Namespace is the openebs namespace
+	Namespace string
+	// ServiceAccount is the name of the service account,
+	// the cluster role and the cluster role binding
+	ServiceAccount string
+	// Image is the image run by the jobs
+	Image string
+	// BackoffLimit is the number of retries of the jobs
+	BackoffLimit int32
+}
+
+// Job holds the details of a job to be generated
+type Job struct {
+	Name string
+	Args []string
+	Env  []corev1.EnvVar
+}
+
+// Name returns a valid object name built from the given parts,
+// truncated to the maximum length of a name
+func Name(parts ...string) string {
+	name := strings.ToLower(strings.Join(parts, "-"))
+	name = invalidNameChars.ReplaceAllString(name, "-")
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+	return strings.Trim(name, "-")
+}
+
+// RBAC returns the service account run by the jobs with
+// the cluster role and the binding granting its permissions
+func RBAC(o Options) []interface{} {
+	return []interface{}{
+		ServiceAccount(o),
+		ClusterRole(o),
+		ClusterRoleBinding(o),
+	}
+}
+
+// ServiceAccount returns the service account run by the jobs
+func ServiceAccount(o Options) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ServiceAccount",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      o.ServiceAccount,
+			Namespace: o.Namespace,
+		},
+	}
+}
+
+// ClusterRole returns the permissions required to upgrade and
+// migrate the openebs resources, record their backups and events,
+// and run the image pull probes
+func ClusterRole(o Options) *rbacv1.ClusterRole {
+	all := []string{"*"}
+	readWrite := []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+	return &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "ClusterRole",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: o.ServiceAccount,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"configmaps", "events", "nodes", "persistentvolumeclaims",
+					"persistentvolumes", "pods", "services"},
+				Verbs: readWrite,
+			},
+			{
+				APIGroups: []string{"apps"},
+				Resources: []string{"deployments", "replicasets", "statefulsets"},
+				Verbs:     readWrite,
+			},
+			{
+				APIGroups: []string{"batch"},
+				Resources: []string{"jobs"},
+				Verbs:     []string{"get", "list"},
+			},
+			{
+				APIGroups: []string{"coordination.k8s.io"},
+				Resources: []string{"leases"},
+				Verbs:     readWrite,
+			},
+			{
+				APIGroups: []string{"cstor.openebs.io", "openebs.io"},
+				Resources: all,
+				Verbs:     all,
+			},
+			{
+				APIGroups: []string{"snapshot.storage.k8s.io"},
+				Resources: []string{"volumesnapshotclasses", "volumesnapshotcontents", "volumesnapshots"},
+				Verbs:     readWrite,
+			},
+			{
+				APIGroups: []string{"storage.k8s.io"},
+				Resources: []string{"storageclasses"},
+				Verbs:     readWrite,
+			},
+			{
+				APIGroups: []string{"storage.k8s.io"},
+				Resources: []string{"volumeattachments"},
+				Verbs:     []string{"get", "list"},
+			},
+		},
+	}
+}
+
+// ClusterRoleBinding binds the cluster role to the service account
+func ClusterRoleBinding(o Options) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "ClusterRoleBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: o.ServiceAccount,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     o.ServiceAccount,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      o.ServiceAccount,
+				Namespace: o.Namespace,
+			},
+		},
+	}
+}
+
+// NewJob returns the job running the given args
+func NewJob(o Options, j Job) *batchv1.Job {
+	backoffLimit := o.BackoffLimit
+	env := append([]corev1.EnvVar{
+		{
+			Name: openebsNamespaceEnv,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.namespace",
+				},
+			},
+		},
+		{
+			Name: podNameEnv,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.name",
+				},
+			},
+		},
+	}, j.Env...)
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: batchv1.SchemeGroupVersion.String(),
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      j.Name,
+			Namespace: o.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					ServiceAccountName: o.ServiceAccount,
+					RestartPolicy:      corev1.RestartPolicyOnFailure,
+					Containers: []corev1.Container{
+						{
+							Name:            o.Operation,
+							Image:           o.Image,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Args:            j.Args,
+							Env:             env,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Write writes the objects as a yaml stream, the fields are sorted
+// and the statuses and empty timestamps are dropped so that the same
+// objects are always written the same way
+func Write(w io.Writer, objs []interface{}) error {
+	for _, obj := range objs {
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode manifest")
+		}
+		m := map[string]interface{}{}
+		err = json.Unmarshal(data, &m)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode manifest")
+		}
+		delete(m, "status")
+		prune(m)
+		data, err = yaml.Marshal(m)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode manifest")
+		}
+		_, err = io.WriteString(w, "---\n"+string(data))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prune removes the null creation timestamps and the
+// empty metadata and resources of the nested objects
+func prune(m map[string]interface{}) {
+	for key, value := range m {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			prune(v)
+			if len(v) == 0 && (key == "metadata" || key == "resources") {
+				delete(m, key)
+			}
+		case []interface{}:
+			for _, item := range v {
+				if im, ok := item.(map[string]interface{}); ok {
+					prune(im)
+				}
+			}
+		case nil:
+			if key == "creationTimestamp" {
+				delete(m, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWrite(t *testing.T) {
+	o := Options{
+		Operation:      "upgrade",
+		Namespace:      "openebs",
+		ServiceAccount: "openebs-upgrade",
+		Image:          "openebs/upgrade:3.5.0",
+		BackoffLimit:   6,
+	}
+	objs := []interface{}{
+		ServiceAccount(o),
+		NewJob(o, Job{
+			Name: Name("upgrade", "cstor-volume", "3.4.0"),
+			Args: []string{"cstor-volume", "--from-version=3.4.0", "--to-version=3.5.0", "pvc-1"},
+		}),
+	}
+	want := `---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: openebs-upgrade
+  namespace: openebs
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: upgrade-cstor-volume-3-4-0
+  namespace: openebs
+spec:
+  backoffLimit: 6
+  template:
+    spec:
+      containers:
+      - args:
+        - cstor-volume
+        - --from-version=3.4.0
+        - --to-version=3.5.0
+        - pvc-1
+        env:
+        - name: OPENEBS_NAMESPACE
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.namespace
+        - name: POD_NAME
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.name
+        image: openebs/upgrade:3.5.0
+        imagePullPolicy: IfNotPresent
+        name: upgrade
+      restartPolicy: OnFailure
+      serviceAccountName: openebs-upgrade
+`
+	// the output should be the same on every run
+	for i := 0; i < 3; i++ {
+		var buf bytes.Buffer
+		err := Write(&buf, objs)
+		if err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if buf.String() != want {
+			t.Fatalf("Write() = \n%s\nwant\n%s", buf.String(), want)
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	tests := map[string]struct {
+		parts []string
+		want  string
+	}{
+		"version":   {[]string{"upgrade", "jiva-volume", "3.4.0"}, "upgrade-jiva-volume-3-4-0"},
+		"uppercase": {[]string{"migrate", "cstor-spc", "Pool_1"}, "migrate-cstor-spc-pool-1"},
+		"too long": {
+			[]string{"migrate", "cstor-volume", "pvc-47f1af68-54fb-462c-b47b-443c267950b0-extra"},
+			"migrate-cstor-volume-pvc-47f1af68-54fb-462c-b47b-443c267950b0-e",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := Name(test.parts...); got != test.want {
+				t.Errorf("Name() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func operatorPod(name, version, image string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "openebs",
+			Labels: map[string]string{
+				"openebs.io/component-name": "cvc-operator",
+				"openebs.io/version":        version,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "sidecar", Image: "quay.io/other/sidecar:1.0"},
+				{Name: "cvc-operator", Image: image},
+			},
+		},
+	}
+}
+
+func TestGetOperator(t *testing.T) {
+	tests := map[string]struct {
+		pods    []*corev1.Pod
+		want    Operator
+		wantErr bool
+	}{
+		"custom registry": {
+			pods: []*corev1.Pod{
+				operatorPod("cvc-operator-b", "3.5.0", "registry.local:5000/openebs/cvc-operator:3.5.0"),
+				operatorPod("cvc-operator-a", "3.5.0", "registry.local:5000/openebs/cvc-operator:3.5.0"),
+			},
+			want: Operator{Version: "3.5.0", ImagePrefix: "registry.local:5000/openebs/"},
+		},
+		"rollout in progress": {
+			pods: []*corev1.Pod{
+				operatorPod("cvc-operator-a", "3.4.0", "openebs/cvc-operator:3.4.0"),
+				operatorPod("cvc-operator-b", "3.5.0", "openebs/cvc-operator:3.5.0"),
+			},
+			wantErr: true,
+		},
+		"no operator": {
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			for _, pod := range test.pods {
+				_, err := client.CoreV1().Pods("openebs").Create(context.TODO(), pod, metav1.CreateOptions{})
+				if err != nil {
+					t.Fatalf("failed to create pod: %v", err)
+				}
+			}
+			got, err := GetOperator(client, "openebs", "cvc-operator")
+			if (err != nil) != test.wantErr {
+				t.Fatalf("GetOperator() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if err == nil && *got != test.want {
+				t.Errorf("GetOperator() = %+v, want %+v", *got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/openebs/api/v3/pkg/apis/types"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Operator holds the details of a running operator
+// used to fill in the generated manifests
+type Operator struct {
+	Version string
+	// ImagePrefix is the registry and the organization of
+	// the operator image, like openebs/ for openebs/cvc-operator
+	ImagePrefix string
+}
+
+// GetOperator returns the version and the image prefix of the operator
+// with the given component name, all its pods should be at the same version
+func GetOperator(client kubernetes.Interface, namespace, componentName string) (*Operator, error) {
+	pods, err := client.CoreV1().Pods(namespace).
+		List(context.TODO(), metav1.ListOptions{
+			LabelSelector: "openebs.io/component-name=" + componentName,
+		})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list %s pods", componentName)
+	}
+	if len(pods.Items) == 0 {
+		return nil, errors.Errorf("operator pod missing for %s", componentName)
+	}
+	// list the pods in a fixed order so that the
+	// same image is picked on every run
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+	var op *Operator
+	for _, pod := range pods.Items {
+		version := pod.Labels[types.OpenEBSVersionLabelKey]
+		if version == "" {
+			return nil, errors.Errorf("%s pod %s has no %s label",
+				componentName, pod.Name, types.OpenEBSVersionLabelKey)
+		}
+		if op != nil && op.Version != version {
+			return nil, errors.Errorf("%s pods are in %s and %s versions, wait for the rollout to complete",
+				componentName, op.Version, version)
+		}
+		if op == nil {
+			op = &Operator{
+				Version:     version,
+				ImagePrefix: ImagePrefix(operatorImage(pod, componentName)),
+			}
+		}
+	}
+	return op, nil
+}
+
+// operatorImage returns the image of the operator container of the
+// pod, the container whose image is named after the component
+func operatorImage(pod corev1.Pod, componentName string) string {
+	for _, c := range pod.Spec.Containers {
+		if strings.Contains(c.Image, "/"+componentName+":") ||
+			strings.HasPrefix(c.Image, componentName+":") {
+			return c.Image
+		}
+	}
+	if len(pod.Spec.Containers) == 0 {
+		return ""
+	}
+	return pod.Spec.Containers[0].Image
+}
+
+// ImagePrefix returns the part of the image before its name,
+// like quay.io/openebs/ for quay.io/openebs/cvc-operator:3.5.0
+func ImagePrefix(image string) string {
+	i := strings.LastIndex(image, "/")
+	if i < 0 {
+		return ""
+	}
+	return image[:i+1]
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeclient
+
+import (
+	snapclientset "github.com/kubernetes-csi/external-snapshotter/client/v4/clientset/versioned"
+	openebsclientset "github.com/openebs/api/v3/pkg/client/clientset/versioned"
+	openebsscheme "github.com/openebs/api/v3/pkg/client/clientset/versioned/scheme"
+	jv "github.com/openebs/jiva-operator/pkg/apis/openebs/v1"
+	mayaclientset "github.com/openebs/maya/pkg/client/generated/clientset/versioned"
+	mayasnapclientset "github.com/openebs/maya/pkg/client/generated/openebs.io/snapshot/v1/clientset/internalclientset"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Options holds the details required to build the rest config
+// used by all the clients. When no kubeconfig is provided the
+// KUBECONFIG env and the in-cluster config are used in that order.
+type Options struct {
+	KubeConfig        string
+	Context           string
+	Impersonate       string
+	ImpersonateGroups []string
+}
+
+// Clientset holds all the clients required by the
+// upgrade and migrate jobs
+type Clientset struct {
+	// KubeClientset is a standard kubernetes clientset
+	KubeClientset kubernetes.Interface
+	// OpenebsClientset is a openebs custom resource package generated for custom API group.
+	OpenebsClientset openebsclientset.Interface
+	// SnapClientset is the clientset for csi volumesnapshot resources
+	SnapClientset snapclientset.Interface
+	// RuntimeClient is a controller-runtime client used for
+	// resources without a generated clientset like JivaVolume
+	RuntimeClient client.Client
+	// Recorder emits the events of the upgrade and migrate jobs
+	Recorder record.EventRecorder
+	// Maya holds the legacy maya clients used during migration
+	Maya *MayaClients
+}
+
+// MayaClients holds the legacy maya clients used during migration,
+// they are built from the same config as the other clients so that
+// the kubeconfig context and the impersonation options apply to them
+type MayaClients struct {
+	// Clientset is the clientset for the SPC, CSP, CV and CVR resources
+	Clientset *mayaclientset.Clientset
+	// SnapClientset is the clientset for the external volumesnapshots
+	SnapClientset *mayasnapclientset.Clientset
+	// KubeClientset and Config are used to exec in the pool pods
+	KubeClientset *kubernetes.Clientset
+	Config        *rest.Config
+}
+
+// Config returns the rest config built from the given options
+func (o *Options) Config() (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if o.KubeConfig != "" {
+		loadingRules.ExplicitPath = o.KubeConfig
+	}
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: o.Context,
+	}
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules,
+		overrides,
+	).ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building kubeconfig")
+	}
+	if o.Impersonate != "" || len(o.ImpersonateGroups) != 0 {
+		cfg.Impersonate = rest.ImpersonationConfig{
+			UserName: o.Impersonate,
+			Groups:   o.ImpersonateGroups,
+		}
+	}
+	return cfg, nil
+}
+
+// New builds all the clients from the given options
+func New(o *Options) (*Clientset, error) {
+	cfg, err := o.Config()
+	if err != nil {
+		return nil, err
+	}
+	return NewForConfig(cfg)
+}
+
+// NewForConfig builds all the clients from the given rest config
+func NewForConfig(cfg *rest.Config) (*Clientset, error) {
+	var err error
+	c := &Clientset{}
+	c.KubeClientset, err = kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "error building kubernetes clientset")
+	}
+	c.OpenebsClientset, err = openebsclientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "error building openebs clientset")
+	}
+	c.SnapClientset, err = snapclientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "error building snapshot clientset")
+	}
+	c.RuntimeClient, err = client.New(cfg, client.Options{
+		Scheme: Scheme(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error building runtime client")
+	}
+	c.Maya, err = NewMayaClients(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewMayaClients builds the legacy maya clients from the given rest config
+func NewMayaClients(cfg *rest.Config) (*MayaClients, error) {
+	var err error
+	m := &MayaClients{Config: cfg}
+	m.Clientset, err = mayaclientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "error building maya clientset")
+	}
+	m.SnapClientset, err = mayasnapclientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "error building maya snapshot clientset")
+	}
+	m.KubeClientset, err = kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "error building kubernetes clientset")
+	}
+	return m, nil
+}
+
+// Scheme returns the scheme with all the types
+// known to the runtime client
+func Scheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(openebsscheme.AddToScheme(scheme))
+	utilruntime.Must(jv.AddToScheme(scheme))
+	return scheme
+}
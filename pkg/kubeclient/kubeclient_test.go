@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeclient
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+const testKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com:6443
+- name: prod
+  cluster:
+    server: https://prod.example.com:6443
+users:
+- name: admin
+  user:
+    token: secret
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: admin
+- name: prod
+  context:
+    cluster: prod
+    user: admin
+current-context: dev
+`
+
+func writeKubeConfig(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "config")
+	err := os.WriteFile(path, []byte(testKubeConfig), 0600)
+	if err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	return path
+}
+
+func TestOptionsConfig(t *testing.T) {
+	kubeconfig := writeKubeConfig(t)
+	tests := map[string]struct {
+		options         Options
+		wantHost        string
+		wantImpersonate rest.ImpersonationConfig
+		wantErr         bool
+	}{
+		"current context of the kubeconfig": {
+			options:  Options{KubeConfig: kubeconfig},
+			wantHost: "https://dev.example.com:6443",
+		},
+		"context given with --context": {
+			options:  Options{KubeConfig: kubeconfig, Context: "prod"},
+			wantHost: "https://prod.example.com:6443",
+		},
+		"impersonated user and groups": {
+			options: Options{
+				KubeConfig:        kubeconfig,
+				Impersonate:       "upgrade-admin",
+				ImpersonateGroups: []string{"system:masters", "openebs"},
+			},
+			wantHost: "https://dev.example.com:6443",
+			wantImpersonate: rest.ImpersonationConfig{
+				UserName: "upgrade-admin",
+				Groups:   []string{"system:masters", "openebs"},
+			},
+		},
+		"impersonated groups only": {
+			options: Options{
+				KubeConfig:        kubeconfig,
+				ImpersonateGroups: []string{"openebs"},
+			},
+			wantHost: "https://dev.example.com:6443",
+			wantImpersonate: rest.ImpersonationConfig{
+				Groups: []string{"openebs"},
+			},
+		},
+		"unknown context": {
+			options: Options{KubeConfig: kubeconfig, Context: "staging"},
+			wantErr: true,
+		},
+		"missing kubeconfig": {
+			options: Options{KubeConfig: filepath.Join(t.TempDir(), "missing")},
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := test.options.Config()
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Config() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if cfg.Host != test.wantHost {
+				t.Errorf("Config() host = %s, want %s", cfg.Host, test.wantHost)
+			}
+			if cfg.BearerToken != "secret" {
+				t.Errorf("Config() token = %q, want the token of the kubeconfig user", cfg.BearerToken)
+			}
+			if !reflect.DeepEqual(cfg.Impersonate, test.wantImpersonate) {
+				t.Errorf("Config() impersonate = %+v, want %+v", cfg.Impersonate, test.wantImpersonate)
+			}
+		})
+	}
+}
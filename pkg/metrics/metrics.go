@@ -0,0 +1,175 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const (
+	// Upgrade and Migrate are the values of the
+	// operation label of the metrics
+	Upgrade = "upgrade"
+	Migrate = "migrate"
+
+	// StatePending, StateSucceeded and StateFailed are
+	// the values of the state label of the resource count
+	StatePending   = "pending"
+	StateSucceeded = "succeeded"
+	StateFailed    = "failed"
+)
+
+var (
+	// Registry holds the metrics of the job, it is
+	// served on the metrics address and pushed on exit
+	Registry = prometheus.NewRegistry()
+
+	stepDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "openebs",
+		Subsystem: "task",
+		Name:      "step_duration_seconds",
+		Help:      "Duration of the completed and failed steps of the upgrade or migration of a resource.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+	}, []string{"operation", "kind", "step", "result"})
+
+	stepStarted = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "openebs",
+		Subsystem: "task",
+		Name:      "step_started_timestamp_seconds",
+		Help:      "Start time of the steps in progress, a step is removed once it is completed or failed.",
+	}, []string{"operation", "kind", "name", "step"})
+
+	stepRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "openebs",
+		Subsystem: "task",
+		Name:      "step_retries_total",
+		Help:      "Number of steps started again after a failed or interrupted attempt.",
+	}, []string{"operation", "kind", "step"})
+
+	stepFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "openebs",
+		Subsystem: "task",
+		Name:      "step_failures_total",
+		Help:      "Number of failed steps.",
+	}, []string{"operation", "kind", "step"})
+
+	waitDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "openebs",
+		Subsystem: "task",
+		Name:      "wait_duration_seconds",
+		Help:      "Time spent waiting for the resources to reconcile, like the rollout of a deployment.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+	}, []string{"condition", "result"})
+
+	resources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "openebs",
+		Subsystem: "task",
+		Name:      "resources",
+		Help:      "Number of resources pending, succeeded and failed in the job.",
+	}, []string{"operation", "kind", "state"})
+
+	// pending tracks the pending resources so that a resource
+	// which was never counted as pending does not decrement it
+	pending = map[string]int{}
+	lock    sync.Mutex
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		stepDuration,
+		stepStarted,
+		stepRetries,
+		stepFailures,
+		waitDuration,
+		resources,
+	)
+}
+
+// StepStarted records the start of a step of the upgrade or migration
+// of a resource, retry is set if the step was attempted before
+func StepStarted(operation, kind, name, step string, retry bool) {
+	stepStarted.WithLabelValues(operation, kind, name, step).SetToCurrentTime()
+	if retry {
+		stepRetries.WithLabelValues(operation, kind, step).Inc()
+	}
+}
+
+// StepFinished records the end of a step which was started at the
+// given time, failed is set if the step ended with an error
+func StepFinished(operation, kind, name, step string, start time.Time, failed bool) {
+	stepStarted.DeleteLabelValues(operation, kind, name, step)
+	result := StateSucceeded
+	if failed {
+		result = StateFailed
+		stepFailures.WithLabelValues(operation, kind, step).Inc()
+	}
+	if !start.IsZero() {
+		stepDuration.WithLabelValues(operation, kind, step, result).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveWait records the time spent waiting for the given
+// condition, like "rollout" or "version reconciliation"
+func ObserveWait(condition string, d time.Duration, err error) {
+	result := StateSucceeded
+	if err != nil {
+		result = StateFailed
+	}
+	waitDuration.WithLabelValues(condition, result).Observe(d.Seconds())
+}
+
+// WaitCondition returns the condition of a wait description
+// of the form "<condition> of <object>"
+func WaitCondition(desc string) string {
+	if i := strings.Index(desc, " of "); i > 0 {
+		return desc[:i]
+	}
+	return desc
+}
+
+// AddPending adds the given number of resources to be
+// upgraded or migrated by the job
+func AddPending(operation, kind string, n int) {
+	lock.Lock()
+	defer lock.Unlock()
+	pending[operation+"/"+kind] += n
+	resources.WithLabelValues(operation, kind, StatePending).Add(float64(n))
+}
+
+// ResourceDone moves a pending resource to the succeeded
+// or failed resources depending on the error
+func ResourceDone(operation, kind string, err error) {
+	lock.Lock()
+	defer lock.Unlock()
+	if key := operation + "/" + kind; pending[key] > 0 {
+		pending[key]--
+		resources.WithLabelValues(operation, kind, StatePending).Dec()
+	}
+	state := StateSucceeded
+	if err != nil {
+		state = StateFailed
+	}
+	resources.WithLabelValues(operation, kind, state).Inc()
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSteps(t *testing.T) {
+	StepStarted(Upgrade, "cstorVolume", "pvc-1", "REPLICA_UPGRADE", false)
+	if got := testutil.ToFloat64(stepStarted.WithLabelValues(Upgrade, "cstorVolume", "pvc-1", "REPLICA_UPGRADE")); got == 0 {
+		t.Errorf("StepStarted() did not record the start time")
+	}
+	StepFinished(Upgrade, "cstorVolume", "pvc-1", "REPLICA_UPGRADE", time.Now().Add(-time.Minute), true)
+	if got := testutil.CollectAndCount(stepStarted); got != 0 {
+		t.Errorf("StepFinished() left %d steps in progress, want 0", got)
+	}
+	if got := testutil.ToFloat64(stepFailures.WithLabelValues(Upgrade, "cstorVolume", "REPLICA_UPGRADE")); got != 1 {
+		t.Errorf("StepFinished() failures = %v, want 1", got)
+	}
+	StepStarted(Upgrade, "cstorVolume", "pvc-1", "REPLICA_UPGRADE", true)
+	if got := testutil.ToFloat64(stepRetries.WithLabelValues(Upgrade, "cstorVolume", "REPLICA_UPGRADE")); got != 1 {
+		t.Errorf("StepStarted() retries = %v, want 1", got)
+	}
+}
+
+func TestResources(t *testing.T) {
+	AddPending(Migrate, "cstorVolume", 2)
+	ResourceDone(Migrate, "cstorVolume", nil)
+	ResourceDone(Migrate, "cstorVolume", errors.New("failed"))
+	// a resource which was not pending does not make the count negative
+	ResourceDone(Migrate, "cstorVolume", nil)
+	want := map[string]float64{
+		StatePending:   0,
+		StateSucceeded: 2,
+		StateFailed:    1,
+	}
+	for state, count := range want {
+		if got := testutil.ToFloat64(resources.WithLabelValues(Migrate, "cstorVolume", state)); got != count {
+			t.Errorf("%s resources = %v, want %v", state, got, count)
+		}
+	}
+}
+
+func TestWaitCondition(t *testing.T) {
+	tests := map[string]string{
+		"rollout of deployment pvc-1-target": "rollout",
+		"version reconciliation of pvc-1":    "version reconciliation",
+		"test":                               "test",
+	}
+	for desc, want := range tests {
+		if got := WaitCondition(desc); got != want {
+			t.Errorf("WaitCondition(%q) = %q, want %q", desc, got, want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"k8s.io/klog/v2"
+)
+
+// Serve serves the metrics on /metrics of the given address until
+// the job exits, an error is returned if the address cannot be bound
+func Serve(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to serve metrics on %s", addr)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+	go func() {
+		err := http.Serve(l, mux)
+		if err != nil {
+			klog.Errorf("failed to serve metrics: %v", err)
+		}
+	}()
+	klog.Infof("Serving metrics on %s/metrics", l.Addr())
+	return nil
+}
+
+// Push pushes the metrics to the Pushgateway at the given url, they are
+// grouped by the job and the pod name so that every job keeps its metrics
+func Push(url, job string) error {
+	instance := os.Getenv("POD_NAME")
+	if instance == "" {
+		instance, _ = os.Hostname()
+	}
+	err := push.New(url, job).
+		Gatherer(Registry).
+		Grouping("instance", instance).
+		Push()
+	if err != nil {
+		return errors.Wrapf(err, "failed to push metrics to %s", url)
+	}
+	return nil
+}
@@ -25,9 +25,6 @@ import (
 	"github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
 	openebstypes "github.com/openebs/api/v3/pkg/apis/types"
 	apis "github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
-	csp "github.com/openebs/maya/pkg/cstor/pool/v1alpha3"
-	pod "github.com/openebs/maya/pkg/kubernetes/pod/v1alpha1"
-	spc "github.com/openebs/maya/pkg/storagepoolclaim/v1alpha1"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -58,7 +55,7 @@ func (c *CSPCMigrator) correctBDs(spcName string) error {
 	}
 
 retryspcupdate:
-	spcObj, err := spc.NewKubeClient().Get(spcName, metav1.GetOptions{})
+	spcObj, err := newSPCClient(c.Maya).Get(spcName, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
@@ -69,7 +66,7 @@ retryspcupdate:
 
 	if spcObj.Annotations[string(apis.OpenEBSDisableReconcileKey)] != "true" {
 		spcObj.Annotations[string(apis.OpenEBSDisableReconcileKey)] = "true"
-		spcObj, err = spc.NewKubeClient().Update(spcObj)
+		spcObj, err = newSPCClient(c.Maya).Update(spcObj)
 		if err != nil {
 			if k8serrors.IsConflict(err) {
 				klog.Errorf("failed to update spc with OpenEBSDisableReconcile annotation due to conflict error")
@@ -80,7 +77,7 @@ retryspcupdate:
 		}
 	}
 
-	cspList, err := csp.KubeClient().List(
+	cspList, err := newCSPClient(c.Maya).List(
 		metav1.ListOptions{
 			LabelSelector: string(apis.StoragePoolClaimCPK) + "=" + spcName,
 		},
@@ -101,7 +98,7 @@ retryspcupdate:
 		}
 	}
 	delete(spcObj.Annotations, string(apis.OpenEBSDisableReconcileKey))
-	_, err = spc.NewKubeClient().Update(spcObj)
+	_, err = newSPCClient(c.Maya).Update(spcObj)
 	return err
 }
 
@@ -163,7 +160,7 @@ func (c *CSPCMigrator) correctCSPBDs(spcObj *apis.StoragePoolClaim, cspObj apis.
 	if err != nil {
 		return err
 	}
-	_, err = csp.KubeClient().
+	_, err = newCSPClient(c.Maya).
 		Patch(cspObj.Name, types.MergePatchType, data)
 
 	return err
@@ -335,7 +332,7 @@ func (c *CSPCMigrator) updateBDRefsAndlabels(spcObj *apis.StoragePoolClaim, oldB
 }
 
 func (c *CSPCMigrator) getDevlinks(podName string) ([]string, error) {
-	podClient := pod.NewKubeClient()
+	podClient := newPodClient(c.Maya)
 	output, err := podClient.WithNamespace(c.OpenebsNamespace).
 		Exec(podName, &corev1.PodExecOptions{
 			Container: "cstor-pool",
@@ -0,0 +1,84 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrate
+
+import (
+	csp "github.com/openebs/maya/pkg/cstor/pool/v1alpha3"
+	cv "github.com/openebs/maya/pkg/cstor/volume/v1alpha1"
+	cvr "github.com/openebs/maya/pkg/cstor/volumereplica/v1alpha1"
+	pod "github.com/openebs/maya/pkg/kubernetes/pod/v1alpha1"
+	snap "github.com/openebs/maya/pkg/kubernetes/snapshot/v1alpha1"
+	snapData "github.com/openebs/maya/pkg/kubernetes/snapshotdata/v1alpha1"
+	spc "github.com/openebs/maya/pkg/storagepoolclaim/v1alpha1"
+
+	"github.com/openebs/upgrade/pkg/kubeclient"
+)
+
+// The maya clients below are built from the given MayaClients, so that
+// they talk to the same cluster as the other clients. Without them the
+// maya clients fall back to the OPENEBS_IO_KUBE_CONFIG env and to the
+// in-cluster config.
+
+func newSPCClient(m *kubeclient.MayaClients) *spc.Kubeclient {
+	if m == nil {
+		return spc.NewKubeClient()
+	}
+	return spc.NewKubeClient(spc.WithKubeClient(m.Clientset))
+}
+
+func newCSPClient(m *kubeclient.MayaClients) *csp.Kubeclient {
+	if m == nil {
+		return csp.KubeClient()
+	}
+	return csp.KubeClient(csp.WithKubeClient(m.Clientset))
+}
+
+func newCVClient(m *kubeclient.MayaClients) *cv.Kubeclient {
+	if m == nil {
+		return cv.NewKubeclient()
+	}
+	return cv.NewKubeclient(cv.WithClientSet(m.Clientset))
+}
+
+func newCVRClient(m *kubeclient.MayaClients) *cvr.Kubeclient {
+	if m == nil {
+		return cvr.NewKubeclient()
+	}
+	return cvr.NewKubeclient(cvr.WithKubeClient(m.Clientset))
+}
+
+func newSnapClient(m *kubeclient.MayaClients) *snap.Kubeclient {
+	if m == nil {
+		return snap.NewKubeClient()
+	}
+	return snap.NewKubeClient(snap.WithClientSet(m.SnapClientset))
+}
+
+func newSnapDataClient(m *kubeclient.MayaClients) *snapData.Kubeclient {
+	if m == nil {
+		return snapData.NewKubeClient()
+	}
+	return snapData.NewKubeClient(snapData.WithClientSet(m.SnapClientset))
+}
+
+func newPodClient(m *kubeclient.MayaClients) *pod.KubeClient {
+	if m == nil {
+		return pod.NewKubeClient()
+	}
+	return pod.NewKubeClient(pod.WithClientSet(m.KubeClientset)).
+		WithKubeConfig(m.Config)
+}
@@ -23,7 +23,6 @@ import (
 	cstor "github.com/openebs/api/v3/pkg/apis/cstor/v1"
 	"github.com/openebs/api/v3/pkg/apis/types"
 	apis "github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
-	csp "github.com/openebs/maya/pkg/cstor/pool/v1alpha3"
 	"github.com/openebs/maya/pkg/util/retry"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
@@ -75,7 +74,7 @@ func getBDList(rg apis.BlockDeviceGroup) []cstor.CStorPoolInstanceBlockDevice {
 }
 
 func (c *CSPCMigrator) getCSPCSpecForSPC(spcName string) (*cstor.CStorPoolCluster, error) {
-	cspClient := csp.KubeClient()
+	cspClient := newCSPClient(c.Maya)
 	cspList, err := cspClient.List(metav1.ListOptions{
 		LabelSelector: string(apis.StoragePoolClaimCPK) + "=" + c.SPCObj.Name,
 	})
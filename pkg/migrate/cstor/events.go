@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	"github.com/openebs/upgrade/pkg/events"
+)
+
+// migrationStepReasons are the reasons of the events emitted when a
+// step is started and completed, a failed step emits MigrationFailed
+var migrationStepReasons = map[string][2]string{
+	"Pre-migration": {events.MigrationStarted, events.PreMigrationCompleted},
+	"Migrate":       {events.MigrateStarted, events.Migrated},
+}
+
+// eventSource is implemented by the migrators to emit
+// the events of the migration steps on the migrated objects
+type eventSource interface {
+	eventRecorder() record.EventRecorder
+	migratedObjects(mtaskObj *v1Alpha1API.MigrationTask) []runtime.Object
+}
+
+// migrationEvent returns the type, reason and message of
+// the event for the given status of a migration step
+func migrationEvent(status v1Alpha1API.MigrationDetailedStatuses) (string, string, string) {
+	reasons := migrationStepReasons[status.Step]
+	switch status.Phase {
+	case v1Alpha1API.StepWaiting:
+		return corev1.EventTypeNormal, reasons[0], fmt.Sprintf("Started step %s of the migration", status.Step)
+	case v1Alpha1API.StepErrored:
+		return corev1.EventTypeWarning, events.MigrationFailed, fmt.Sprintf("Step %s failed: %s: %s",
+			status.Step, status.Message, status.Reason)
+	}
+	return corev1.EventTypeNormal, reasons[1], status.Message
+}
+
+// recordMigrationEvent emits the event for the status of a migration
+// step on the MigrationTask and on the objects being migrated
+func recordMigrationEvent(mtaskObj *v1Alpha1API.MigrationTask,
+	status v1Alpha1API.MigrationDetailedStatuses, src eventSource) {
+	if src == nil || src.eventRecorder() == nil || mtaskObj == nil {
+		return
+	}
+	recorder := src.eventRecorder()
+	eventtype, reason, message := migrationEvent(status)
+	recorder.Event(mtaskObj, eventtype, reason, message)
+	for _, obj := range src.migratedObjects(mtaskObj) {
+		recorder.Event(obj, eventtype, reason, message)
+	}
+}
+
+func (v *VolumeMigrator) eventRecorder() record.EventRecorder {
+	return v.Recorder
+}
+
+// migratedObjects returns the PV being migrated
+func (v *VolumeMigrator) migratedObjects(mtaskObj *v1Alpha1API.MigrationTask) []runtime.Object {
+	pv, err := v.KubeClientset.CoreV1().PersistentVolumes().
+		Get(context.TODO(), v.PVName, metav1.GetOptions{})
+	if err != nil {
+		klog.Errorf("failed to get the pv migrated by %s for events: %v", mtaskObj.Name, err)
+		return nil
+	}
+	return []runtime.Object{pv}
+}
+
+func (c *CSPCMigrator) eventRecorder() record.EventRecorder {
+	return c.Recorder
+}
+
+// migratedObjects returns the SPC being migrated and the equivalent CSPC
+// once it is created, the SPC is deleted at the end of the migration
+func (c *CSPCMigrator) migratedObjects(mtaskObj *v1Alpha1API.MigrationTask) []runtime.Object {
+	objs := []runtime.Object{}
+	if mtaskObj.Spec.MigrateCStorPool == nil {
+		return objs
+	}
+	spcObj, err := newSPCClient(c.Maya).
+		Get(mtaskObj.Spec.MigrateCStorPool.SPCName, metav1.GetOptions{})
+	if err == nil {
+		// the maya types are not registered in the scheme
+		// of the recorder so the reference is built here
+		objs = append(objs, &corev1.ObjectReference{
+			APIVersion: "openebs.io/v1alpha1",
+			Kind:       "StoragePoolClaim",
+			Name:       spcObj.Name,
+			UID:        spcObj.UID,
+		})
+	}
+	if c.CSPCName == "" {
+		return objs
+	}
+	cspcObj, err := c.OpenebsClientset.CstorV1().CStorPoolClusters(c.OpenebsNamespace).
+		Get(context.TODO(), c.CSPCName, metav1.GetOptions{})
+	if err == nil {
+		objs = append(objs, cspcObj)
+	}
+	return objs
+}
@@ -16,6 +16,12 @@ limitations under the License.
 
 package migrate
 
+const (
+	// DefaultMaxRetries is the number of failed attempts after which a
+	// MigrationTask is failed, same as the default backoffLimit of a Job
+	DefaultMaxRetries = 6
+)
+
 var (
 	// IsMigrationTaskJob is used to determine
 	// whether to report the utask errors. Errors
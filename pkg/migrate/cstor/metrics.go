@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrate
+
+import (
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+
+	"github.com/openebs/upgrade/pkg/metrics"
+)
+
+// migrationTaskResource returns the kind and the name
+// of the resource migrated by the MigrationTask
+func migrationTaskResource(mtaskObj *v1Alpha1API.MigrationTask) (string, string) {
+	spec := mtaskObj.Spec.MigrateResource
+	switch {
+	case spec.MigrateCStorPool != nil:
+		return "cstorPool", spec.MigrateCStorPool.SPCName
+	case spec.MigrateCStorVolume != nil:
+		return "cstorVolume", spec.MigrateCStorVolume.PVName
+	}
+	return "", ""
+}
+
+// observeMigrationStep records the metrics of the given status of a
+// migration step, it is called before the status is recorded on the
+// MigrationTask. A step started again after a failed or interrupted
+// attempt is a retry.
+func observeMigrationStep(mtaskObj *v1Alpha1API.MigrationTask,
+	status v1Alpha1API.MigrationDetailedStatuses) {
+	kind, name := migrationTaskResource(mtaskObj)
+	statuses := mtaskObj.Status.MigrationDetailedStatuses
+	if status.Phase == v1Alpha1API.StepWaiting {
+		retry := false
+		for i := len(statuses) - 1; i >= 0; i-- {
+			if statuses[i].Step == status.Step {
+				retry = statuses[i].Phase != v1Alpha1API.StepCompleted
+				break
+			}
+		}
+		metrics.StepStarted(metrics.Migrate, kind, name, status.Step, retry)
+		return
+	}
+	if len(statuses) == 0 {
+		return
+	}
+	metrics.StepFinished(metrics.Migrate, kind, name, status.Step,
+		statuses[len(statuses)-1].StartTime.Time,
+		status.Phase == v1Alpha1API.StepErrored)
+}
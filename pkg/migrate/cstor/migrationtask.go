@@ -28,7 +28,7 @@ import (
 
 func updateMigrationDetailedStatus(mtaskObj *v1Alpha1API.MigrationTask,
 	mStatusObj v1Alpha1API.MigrationDetailedStatuses,
-	openebsNamespace string, client openebsclientset.Interface,
+	openebsNamespace string, client openebsclientset.Interface, src eventSource,
 ) (*v1Alpha1API.MigrationTask, error) {
 	var err error
 	if !isValidStatus(mStatusObj) {
@@ -37,6 +37,8 @@ func updateMigrationDetailedStatus(mtaskObj *v1Alpha1API.MigrationTask,
 			mStatusObj,
 		)
 	}
+	recordMigrationEvent(mtaskObj, mStatusObj, src)
+	observeMigrationStep(mtaskObj, mStatusObj)
 	mStatusObj.LastUpdatedTime = metav1.Now()
 	if mStatusObj.Phase == v1Alpha1API.StepWaiting {
 		mStatusObj.StartTime = mStatusObj.LastUpdatedTime
@@ -116,6 +118,18 @@ func getOrCreateMigrationTask(kind, name, openebsNamespace string, r Migrator,
 	return mtaskObj, nil
 }
 
+// MigrationTaskName returns the name of the MigrationTask
+// recording the migration of the given resource
+func MigrationTaskName(kind, name string) string {
+	switch kind {
+	case "cstorPool":
+		return "migrate-cstor-pool-" + name
+	case "cstorVolume":
+		return "migrate-cstor-volume-" + name
+	}
+	return ""
+}
+
 func buildMigrationTask(kind, name string, m Migrator) *v1Alpha1API.MigrationTask {
 	// TODO builder
 	mtaskObj := &v1Alpha1API.MigrationTask{
@@ -129,7 +143,7 @@ func buildMigrationTask(kind, name string, m Migrator) *v1Alpha1API.MigrationTas
 	switch kind {
 	case "cstorPool":
 		r := m.(*CSPCMigrator)
-		mtaskObj.Name = "migrate-cstor-pool-" + name
+		mtaskObj.Name = MigrationTaskName(kind, name)
 		mtaskObj.Spec.MigrateResource = v1Alpha1API.MigrateResource{
 			MigrateCStorPool: &v1Alpha1API.MigrateCStorPool{
 				SPCName: name,
@@ -138,7 +152,7 @@ func buildMigrationTask(kind, name string, m Migrator) *v1Alpha1API.MigrationTas
 		}
 	case "cstorVolume":
 		r := m.(*VolumeMigrator)
-		mtaskObj.Name = "migrate-cstor-volume-" + name
+		mtaskObj.Name = MigrationTaskName(kind, name)
 		mtaskObj.Spec.MigrateResource = v1Alpha1API.MigrateResource{
 			MigrateCStorVolume: &v1Alpha1API.MigrateCStorVolume{
 				PVName: r.PVName,
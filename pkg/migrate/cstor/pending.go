@@ -0,0 +1,61 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrate
+
+import (
+	"sort"
+
+	apis "github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openebs/upgrade/pkg/kubeclient"
+)
+
+// PendingSPCs returns the names of the SPCs which are not migrated,
+// the SPC is deleted as the last step of its migration
+func PendingSPCs(m *kubeclient.MayaClients) ([]string, error) {
+	spcList, err := newSPCClient(m).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list spcs")
+	}
+	names := []string{}
+	for _, spcObj := range spcList.Items {
+		names = append(names, spcObj.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// PendingVolumes returns the names of the PVs of the cstor volumes
+// which are not migrated to csi, the old CStorVolume is deleted
+// once the volume is migrated
+func PendingVolumes(m *kubeclient.MayaClients) ([]string, error) {
+	cvList, err := newCVClient(m).WithNamespace("").
+		List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cstorvolumes")
+	}
+	names := []string{}
+	for _, cvObj := range cvList.Items {
+		if pvName := cvObj.Labels[string(apis.PersistentVolumeCPK)]; pvName != "" {
+			names = append(names, pvName)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -27,17 +27,15 @@ import (
 	"github.com/openebs/api/v3/pkg/apis/types"
 	openebsclientset "github.com/openebs/api/v3/pkg/client/clientset/versioned"
 	apis "github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
-	csp "github.com/openebs/maya/pkg/cstor/pool/v1alpha3"
-	cvr "github.com/openebs/maya/pkg/cstor/volumereplica/v1alpha1"
-	spc "github.com/openebs/maya/pkg/storagepoolclaim/v1alpha1"
 	"github.com/pkg/errors"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
+	"github.com/openebs/upgrade/pkg/kubeclient"
 	"github.com/openebs/upgrade/pkg/version"
 )
 
@@ -63,6 +61,12 @@ type CSPCMigrator struct {
 	SPCObj           *apis.StoragePoolClaim
 	OpenebsNamespace string
 	CSPCName         string
+	// Maya holds the legacy maya clients, the maya
+	// defaults are used if it is not set
+	Maya *kubeclient.MayaClients
+	// Recorder emits the events for the steps of the
+	// migration, no events are emitted if it is not set
+	Recorder record.EventRecorder
 }
 
 // SetCSPCName is used to initialize custom name if provided
@@ -73,17 +77,8 @@ func (c *CSPCMigrator) SetCSPCName(name string) {
 // Migrate ...
 func (c *CSPCMigrator) Migrate(name, namespace string) error {
 	c.OpenebsNamespace = namespace
-	cfg, err := rest.InClusterConfig()
-	if err != nil {
-		return errors.Wrap(err, "error building kubeconfig")
-	}
-	c.KubeClientset, err = kubernetes.NewForConfig(cfg)
-	if err != nil {
-		return errors.Wrap(err, "error building kubernetes clientset")
-	}
-	c.OpenebsClientset, err = openebsclientset.NewForConfig(cfg)
-	if err != nil {
-		return errors.Wrap(err, "error building openebs clientset")
+	if c.KubeClientset == nil || c.OpenebsClientset == nil {
+		return errors.Errorf("clients not initialized for migrating spc %s", name)
 	}
 	mtask, err := getOrCreateMigrationTask("cstorPool", name, namespace, c, c.OpenebsClientset)
 	if err != nil {
@@ -91,7 +86,7 @@ func (c *CSPCMigrator) Migrate(name, namespace string) error {
 	}
 	statusObj := v1Alpha1API.MigrationDetailedStatuses{Step: "Pre-migration"}
 	statusObj.Phase = v1Alpha1API.StepWaiting
-	mtask, uerr := updateMigrationDetailedStatus(mtask, statusObj, c.OpenebsNamespace, c.OpenebsClientset)
+	mtask, uerr := updateMigrationDetailedStatus(mtask, statusObj, c.OpenebsNamespace, c.OpenebsClientset, c)
 	if uerr != nil && IsMigrationTaskJob {
 		return uerr
 	}
@@ -99,7 +94,7 @@ func (c *CSPCMigrator) Migrate(name, namespace string) error {
 	if err != nil {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
-		mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, c.OpenebsNamespace, c.OpenebsClientset)
+		mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, c.OpenebsNamespace, c.OpenebsClientset, c)
 		if uerr != nil && IsMigrationTaskJob {
 			return uerr
 		}
@@ -108,14 +103,14 @@ func (c *CSPCMigrator) Migrate(name, namespace string) error {
 	statusObj.Phase = v1Alpha1API.StepCompleted
 	statusObj.Message = "Pre-migration steps were successful"
 	statusObj.Reason = ""
-	mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, c.OpenebsNamespace, c.OpenebsClientset)
+	mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, c.OpenebsNamespace, c.OpenebsClientset, c)
 	if uerr != nil && IsMigrationTaskJob {
 		return uerr
 	}
 
 	statusObj = v1Alpha1API.MigrationDetailedStatuses{Step: "Migrate"}
 	statusObj.Phase = v1Alpha1API.StepWaiting
-	mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, c.OpenebsNamespace, c.OpenebsClientset)
+	mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, c.OpenebsNamespace, c.OpenebsClientset, c)
 	if uerr != nil && IsMigrationTaskJob {
 		return uerr
 	}
@@ -123,7 +118,7 @@ func (c *CSPCMigrator) Migrate(name, namespace string) error {
 	if err != nil {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
-		mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, c.OpenebsNamespace, c.OpenebsClientset)
+		mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, c.OpenebsNamespace, c.OpenebsClientset, c)
 		if uerr != nil && IsMigrationTaskJob {
 			return uerr
 		}
@@ -132,7 +127,7 @@ func (c *CSPCMigrator) Migrate(name, namespace string) error {
 	statusObj.Phase = v1Alpha1API.StepCompleted
 	statusObj.Message = "Migration steps were successful"
 	statusObj.Reason = ""
-	mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, c.OpenebsNamespace, c.OpenebsClientset)
+	mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, c.OpenebsNamespace, c.OpenebsClientset, c)
 	if uerr != nil && IsMigrationTaskJob {
 		return uerr
 	}
@@ -250,7 +245,7 @@ func (c *CSPCMigrator) migrate(spcName string) (string, error) {
 		return msg, err
 	}
 	// Clean up old SPC resources after the migration is complete
-	err = spc.NewKubeClient().
+	err = newSPCClient(c.Maya).
 		Delete(spcName, &metav1.DeleteOptions{})
 	if err != nil {
 		msg = "failed to clean up spc " + spcName
@@ -276,7 +271,7 @@ func (c *CSPCMigrator) migrate(spcName string) (string, error) {
 //     b. spc has diff anno than current cspc-name
 //     return err
 func (c *CSPCMigrator) checkForExistingCSPC(spcName string) error {
-	spcObj, err := spc.NewKubeClient().Get(spcName, metav1.GetOptions{})
+	spcObj, err := newSPCClient(c.Maya).Get(spcName, metav1.GetOptions{})
 	if err != nil && !k8serrors.IsNotFound(err) {
 		return err
 	}
@@ -303,7 +298,7 @@ func (c *CSPCMigrator) checkForExistingCSPC(spcName string) error {
 	}
 	if k8serrors.IsNotFound(err) {
 		if spcObj.Annotations == nil || spcObj.Annotations[types.CStorPoolClusterLabelKey] == "" {
-			return addCSPCAnnotationToSPC(spcObj, c.CSPCName)
+			return c.addCSPCAnnotationToSPC(spcObj, c.CSPCName)
 		}
 		if spcObj.Annotations[types.CStorPoolClusterLabelKey] != c.CSPCName {
 			return errors.Errorf(
@@ -322,7 +317,7 @@ func (c *CSPCMigrator) checkForExistingCSPC(spcName string) error {
 // or the blocldevice list in spc does not match bds from the csp, in case of manual provisioning
 // pool migration can not be allowed.
 func (c *CSPCMigrator) validateSPC() error {
-	cspClient := csp.KubeClient()
+	cspClient := newCSPClient(c.Maya)
 	cspList, err := cspClient.List(metav1.ListOptions{
 		LabelSelector: string(apis.StoragePoolClaimCPK) + "=" + c.SPCObj.Name,
 	})
@@ -364,7 +359,7 @@ func (c *CSPCMigrator) validateSPC() error {
 // migrated or not. The spc will not be present in the cluster as the last step
 // of migration deletes the spc.
 func (c *CSPCMigrator) getSPCWithMigrationStatus(spcName string) (*apis.StoragePoolClaim, bool, error) {
-	spcObj, err := spc.NewKubeClient().
+	spcObj, err := newSPCClient(c.Maya).
 		Get(spcName, metav1.GetOptions{})
 	// verify if the spc is already migrated. If an equivalent cspc exists then
 	// spc is already migrated as spc is only deleted as last step.
@@ -389,7 +384,7 @@ func (c *CSPCMigrator) cspTocspi(cspiObj *cstor.CStorPoolInstance) error {
 	hostnameLabel := types.HostNameLabelKey + "=" + cspiObj.Labels[types.HostNameLabelKey]
 	spcLabel := string(apis.StoragePoolClaimCPK) + "=" + c.SPCObj.Name
 	cspLabel := hostnameLabel + "," + spcLabel
-	cspObj, err := getCSP(cspLabel)
+	cspObj, err := c.getCSP(cspLabel)
 	if err != nil {
 		return err
 	}
@@ -436,11 +431,11 @@ func (c *CSPCMigrator) cspTocspi(cspiObj *cstor.CStorPoolInstance) error {
 	}
 	// remove the finalizers from csp object for cleanup
 	// as pool pod is no longer running to remove them.
-	return removeCSPFinalizers(cspObj)
+	return c.removeCSPFinalizers(cspObj)
 }
 
-func removeCSPFinalizers(cspObj *apis.CStorPool) error {
-	cspClient := csp.KubeClient()
+func (c *CSPCMigrator) removeCSPFinalizers(cspObj *apis.CStorPool) error {
+	cspClient := newCSPClient(c.Maya)
 	newCSP := cspObj.DeepCopy()
 	newCSP.Finalizers = []string{}
 	patchData, err := GetPatchData(cspObj, newCSP)
@@ -457,8 +452,8 @@ func removeCSPFinalizers(cspObj *apis.CStorPool) error {
 
 // get csp for cspi on the basis of cspLabel, which is the combination of
 // hostname label on which cspi came up and the spc label.
-func getCSP(cspLabel string) (*apis.CStorPool, error) {
-	cspClient := csp.KubeClient()
+func (c *CSPCMigrator) getCSP(cspLabel string) (*apis.CStorPool, error) {
+	cspClient := newCSPClient(c.Maya)
 	cspList, err := cspClient.List(metav1.ListOptions{
 		LabelSelector: cspLabel,
 	})
@@ -598,7 +593,7 @@ func (c *CSPCMigrator) updateBDCOwnerRef() error {
 // Update the cvrs on the old csp with the migrated cspi labels and annotations
 // to allow backward compatibility with old external provisioned volumes.
 func (c *CSPCMigrator) updateCVRsLabels(cspObj *apis.CStorPool, cspiObj *cstor.CStorPoolInstance) error {
-	cvrList, err := cvr.NewKubeclient().
+	cvrList, err := newCVRClient(c.Maya).
 		WithNamespace(c.OpenebsNamespace).List(metav1.ListOptions{
 		LabelSelector: cspNameLabel + "=" + cspObj.Name,
 	})
@@ -616,7 +611,7 @@ func (c *CSPCMigrator) updateCVRsLabels(cspObj *apis.CStorPool, cspiObj *cstor.C
 			cvrObj.Labels[cspiNameLabel] = cspiObj.Name
 			cvrObj.Labels[cspiUIDLabel] = string(cspiObj.UID)
 			cvrObj.Annotations[cspiHostnameAnnotation] = cspiObj.Spec.HostName
-			_, err = cvr.NewKubeclient().WithNamespace(c.OpenebsNamespace).
+			_, err = newCVRClient(c.Maya).WithNamespace(c.OpenebsNamespace).
 				Update(cvrObj)
 			if err != nil {
 				return errors.Wrapf(err, "failed to update cvr %s with cspc info", cvrObj.Name)
@@ -628,7 +623,7 @@ func (c *CSPCMigrator) updateCVRsLabels(cspObj *apis.CStorPool, cspiObj *cstor.C
 
 func (c *CSPCMigrator) addSkipAnnotationToSPC(spcName string) error {
 retry:
-	spcObj, err := spc.NewKubeClient().Get(spcName, metav1.GetOptions{})
+	spcObj, err := newSPCClient(c.Maya).Get(spcName, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
@@ -636,7 +631,7 @@ retry:
 		spcObj.Annotations = map[string]string{}
 	}
 	spcObj.Annotations["openebs.io/skip-validations"] = "true"
-	_, err = spc.NewKubeClient().Update(spcObj)
+	_, err = newSPCClient(c.Maya).Update(spcObj)
 	if k8serrors.IsConflict(err) {
 		klog.Errorf("failed to update spc with skip-validation annotation due to conflict error")
 		time.Sleep(2 * time.Second)
@@ -645,13 +640,13 @@ retry:
 	return err
 }
 
-func addCSPCAnnotationToSPC(spcObj *apis.StoragePoolClaim, cspcName string) error {
+func (c *CSPCMigrator) addCSPCAnnotationToSPC(spcObj *apis.StoragePoolClaim, cspcName string) error {
 retry:
 	if spcObj.Annotations == nil {
 		spcObj.Annotations = map[string]string{}
 	}
 	spcObj.Annotations[types.CStorPoolClusterLabelKey] = cspcName
-	_, err := spc.NewKubeClient().Update(spcObj)
+	_, err := newSPCClient(c.Maya).Update(spcObj)
 	if k8serrors.IsConflict(err) {
 		klog.Errorf("failed to update spc with cspc annotation due to conflict error")
 		goto retry
@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrate
+
+import (
+	"context"
+	"time"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	openebsclientset "github.com/openebs/api/v3/pkg/client/clientset/versioned"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/openebs/upgrade/pkg/schedule"
+)
+
+const (
+	// maintenanceWindowStep is the step recorded on the MigrationTask
+	// while waiting for its maintenance window
+	maintenanceWindowStep = "Maintenance-window"
+)
+
+// WaitForMaintenanceWindow waits until the maintenance window of the
+// MigrationTask is open, recording the wait as a step. The windows are
+// read from the MigrationTask while waiting, so that they can be changed
+// or removed.
+func WaitForMaintenanceWindow(ctx context.Context, mtaskObj *v1Alpha1API.MigrationTask,
+	client openebsclientset.Interface) error {
+	next, err := schedule.NextOpen(mtaskObj.Annotations, schedule.Now())
+	if err != nil {
+		return errors.Wrapf(err, "invalid maintenance window of %s", mtaskObj.Name)
+	}
+	if next.IsZero() {
+		return nil
+	}
+	klog.Infof("Migration of %s is waiting for the maintenance window opening at %s",
+		mtaskObj.Name, next.Format(time.RFC3339))
+	statusObj := v1Alpha1API.MigrationDetailedStatuses{Step: maintenanceWindowStep}
+	statusObj.Phase = v1Alpha1API.StepWaiting
+	statusObj.Message = "Waiting for the maintenance window opening at " + next.Format(time.RFC3339)
+	latest, err := updateMigrationDetailedStatus(mtaskObj, statusObj, mtaskObj.Namespace, client, nil)
+	if err != nil {
+		return err
+	}
+	err = schedule.Wait(ctx, "maintenance window of "+latest.Name,
+		func(ctx context.Context) (map[string]string, error) {
+			obj, err := client.OpenebsV1alpha1().MigrationTasks(latest.Namespace).
+				Get(ctx, latest.Name, metav1.GetOptions{})
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get migrationtask %s", latest.Name)
+			}
+			latest = obj
+			return obj.Annotations, nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+	statusObj.Phase = v1Alpha1API.StepCompleted
+	statusObj.Message = "Maintenance window is open"
+	_, err = updateMigrationDetailedStatus(latest, statusObj, latest.Namespace, client, nil)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Maintenance window of %s is open", latest.Name)
+	return nil
+}
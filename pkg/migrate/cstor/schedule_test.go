@@ -0,0 +1,110 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	openebsFakeClientset "github.com/openebs/api/v3/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openebs/upgrade/pkg/schedule"
+)
+
+func TestWaitForMaintenanceWindow(t *testing.T) {
+	defer func(interval time.Duration, clock func() time.Time) {
+		schedule.CheckInterval = interval
+		schedule.Now = clock
+	}(schedule.CheckInterval, schedule.Now)
+	schedule.CheckInterval = time.Millisecond
+	// 2026-10-17 is a Saturday
+	schedule.Now = func() time.Time {
+		return time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	}
+	tests := map[string]struct {
+		window string
+		// reopen moves the window to now once the wait is recorded
+		reopen     bool
+		wantErr    bool
+		wantStatus v1Alpha1API.StepPhase
+	}{
+		"no window":      {},
+		"window open":    {window: "0 10 * * 6 4h"},
+		"invalid window": {window: "0 10 * * 6", wantErr: true},
+		"window closed then moved": {
+			window:     "0 22 * * 1-5 4h",
+			reopen:     true,
+			wantStatus: v1Alpha1API.StepCompleted,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mtaskObj := &v1Alpha1API.MigrationTask{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "migrate-cstor-volume-pvc-1",
+					Namespace: "openebs",
+				},
+			}
+			if test.window != "" {
+				mtaskObj.Annotations = map[string]string{schedule.WindowAnnotation: test.window}
+			}
+			client := openebsFakeClientset.NewSimpleClientset(mtaskObj)
+			tasks := client.OpenebsV1alpha1().MigrationTasks(mtaskObj.Namespace)
+			if test.reopen {
+				go func() {
+					for {
+						obj, err := tasks.Get(context.TODO(), mtaskObj.Name, metav1.GetOptions{})
+						if err != nil {
+							t.Errorf("failed to get migrationtask: %v", err)
+							return
+						}
+						if len(obj.Status.MigrationDetailedStatuses) != 0 {
+							obj.Annotations[schedule.WindowAnnotation] = "0 12 * * 6 1h"
+							_, err = tasks.Update(context.TODO(), obj, metav1.UpdateOptions{})
+							if err != nil {
+								t.Errorf("failed to update migrationtask: %v", err)
+							}
+							return
+						}
+						time.Sleep(time.Millisecond)
+					}
+				}()
+			}
+			err := WaitForMaintenanceWindow(context.TODO(), mtaskObj, client)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("WaitForMaintenanceWindow() error = %v, wantErr %v", err, test.wantErr)
+			}
+			got, err := tasks.Get(context.TODO(), mtaskObj.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get migrationtask: %v", err)
+			}
+			var phase v1Alpha1API.StepPhase
+			for _, s := range got.Status.MigrationDetailedStatuses {
+				if s.Step == maintenanceWindowStep {
+					phase = s.Phase
+				}
+			}
+			if phase != test.wantStatus {
+				t.Errorf("WaitForMaintenanceWindow() window step phase = %q, want %q",
+					phase, test.wantStatus)
+			}
+		})
+	}
+}
@@ -23,20 +23,20 @@ import (
 	snapv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1beta1"
 	snapclientset "github.com/kubernetes-csi/external-snapshotter/client/v4/clientset/versioned"
 	snapv1 "github.com/openebs/maya/pkg/apis/openebs.io/snapshot/v1"
-	snap "github.com/openebs/maya/pkg/kubernetes/snapshot/v1alpha1"
-	snapData "github.com/openebs/maya/pkg/kubernetes/snapshotdata/v1alpha1"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
+
+	"github.com/openebs/upgrade/pkg/kubeclient"
 )
 
 // SnapshotMigrator ...
 type SnapshotMigrator struct {
 	pvName     string
-	snapClient *snapclientset.Clientset
+	snapClient snapclientset.Interface
+	maya       *kubeclient.MayaClients
 }
 
 var (
@@ -45,20 +45,14 @@ var (
 
 func (s *SnapshotMigrator) migrate(pvName string) error {
 	s.pvName = pvName
-	cfg, err := rest.InClusterConfig()
-	if err != nil {
-		return errors.Wrap(err, "error building kubeconfig")
-	}
-	// snapclientset.NewForConfig creates a new Clientset for VolumesnapshotV1beta1Client
-	s.snapClient, err = snapclientset.NewForConfig(cfg)
-	if err != nil {
-		klog.Fatalf("Failed to create snapshot client: %v", err)
+	if s.snapClient == nil {
+		return errors.Errorf("snapshot client not initialized for volume %s", pvName)
 	}
 	return s.migrateSnapshots()
 }
 
 func (s *SnapshotMigrator) migrateSnapshots() error {
-	snapshotList, err := snap.NewKubeClient().
+	snapshotList, err := newSnapClient(s.maya).
 		WithNamespace("").
 		List(metav1.ListOptions{
 			LabelSelector: "SnapshotMetadata-PVName=" + s.pvName,
@@ -89,7 +83,7 @@ func (s *SnapshotMigrator) migrateSnapshots() error {
 }
 
 func (s *SnapshotMigrator) migrateSnapshot(oldSnap *snapv1.VolumeSnapshot) error {
-	snapshotData, err := snapData.NewKubeClient().
+	snapshotData, err := newSnapDataClient(s.maya).
 		Get(oldSnap.Spec.SnapshotDataName, metav1.GetOptions{})
 	if err != nil {
 		return errors.Wrapf(err, "failed to get volumesnapshotdata %s for %s", oldSnap.Spec.SnapshotDataName, oldSnap.Name)
@@ -110,7 +104,7 @@ func (s *SnapshotMigrator) migrateSnapshot(oldSnap *snapv1.VolumeSnapshot) error
 		return errors.Wrapf(err, "failed to validate new volumesnapshot %s", newSnap.Name)
 	}
 	klog.Infof("Cleaing up old volumesnapshot %s", oldSnap.Name)
-	err = snap.NewKubeClient().WithNamespace(oldSnap.Namespace).Delete(oldSnap.Name, &metav1.DeleteOptions{})
+	err = newSnapClient(s.maya).WithNamespace(oldSnap.Namespace).Delete(oldSnap.Name, &metav1.DeleteOptions{})
 	if err != nil {
 		return errors.Wrapf(err, "failed to delete old volumesnapshot %s", oldSnap.Name)
 	}
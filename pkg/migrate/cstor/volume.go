@@ -24,13 +24,12 @@ import (
 	"strings"
 	"time"
 
+	snapclientset "github.com/kubernetes-csi/external-snapshotter/client/v4/clientset/versioned"
 	cstor "github.com/openebs/api/v3/pkg/apis/cstor/v1"
 	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
 	"github.com/openebs/api/v3/pkg/apis/types"
 	openebsclientset "github.com/openebs/api/v3/pkg/client/clientset/versioned"
 	apis "github.com/openebs/maya/pkg/apis/openebs.io/v1alpha1"
-	cv "github.com/openebs/maya/pkg/cstor/volume/v1alpha1"
-	cvr "github.com/openebs/maya/pkg/cstor/volumereplica/v1alpha1"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -40,9 +39,10 @@ import (
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
+	"github.com/openebs/upgrade/pkg/kubeclient"
 	"github.com/openebs/upgrade/pkg/version"
 )
 
@@ -60,27 +60,26 @@ type VolumeMigrator struct {
 	KubeClientset kubernetes.Interface
 	// openebsclientset is a openebs custom resource package generated for custom API group.
 	OpenebsClientset openebsclientset.Interface
+	// snapclientset is the clientset for csi volumesnapshot resources
+	SnapClientset    snapclientset.Interface
 	PVName           string
 	OpenebsNamespace string
 	CVNamespace      string
 	StorageClass     *storagev1.StorageClass
+	// Maya holds the legacy maya clients, the maya
+	// defaults are used if it is not set
+	Maya *kubeclient.MayaClients
+	// Recorder emits the events for the steps of the
+	// migration, no events are emitted if it is not set
+	Recorder record.EventRecorder
 }
 
 // Migrate is the interface implementation for
 func (v *VolumeMigrator) Migrate(pvName, openebsNamespace string) error {
 	v.PVName = pvName
 	v.OpenebsNamespace = openebsNamespace
-	cfg, err := rest.InClusterConfig()
-	if err != nil {
-		return errors.Wrap(err, "error building kubeconfig")
-	}
-	v.KubeClientset, err = kubernetes.NewForConfig(cfg)
-	if err != nil {
-		return errors.Wrap(err, "error building kubernetes clientset")
-	}
-	v.OpenebsClientset, err = openebsclientset.NewForConfig(cfg)
-	if err != nil {
-		return errors.Wrap(err, "error building openebs clientset")
+	if v.KubeClientset == nil || v.OpenebsClientset == nil || v.SnapClientset == nil {
+		return errors.Errorf("clients not initialized for migrating volume %s", pvName)
 	}
 	mtask, err := getOrCreateMigrationTask("cstorVolume", pvName, v.OpenebsNamespace, v, v.OpenebsClientset)
 	if err != nil {
@@ -88,7 +87,7 @@ func (v *VolumeMigrator) Migrate(pvName, openebsNamespace string) error {
 	}
 	statusObj := v1Alpha1API.MigrationDetailedStatuses{Step: "Pre-migration"}
 	statusObj.Phase = v1Alpha1API.StepWaiting
-	mtask, uerr := updateMigrationDetailedStatus(mtask, statusObj, v.OpenebsNamespace, v.OpenebsClientset)
+	mtask, uerr := updateMigrationDetailedStatus(mtask, statusObj, v.OpenebsNamespace, v.OpenebsClientset, v)
 	if uerr != nil && IsMigrationTaskJob {
 		return uerr
 	}
@@ -96,7 +95,7 @@ func (v *VolumeMigrator) Migrate(pvName, openebsNamespace string) error {
 	if err != nil {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
-		mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, v.OpenebsNamespace, v.OpenebsClientset)
+		mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, v.OpenebsNamespace, v.OpenebsClientset, v)
 		if uerr != nil && IsMigrationTaskJob {
 			return uerr
 		}
@@ -105,14 +104,14 @@ func (v *VolumeMigrator) Migrate(pvName, openebsNamespace string) error {
 	statusObj.Phase = v1Alpha1API.StepCompleted
 	statusObj.Message = "Pre-migration steps were successful"
 	statusObj.Reason = ""
-	mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, v.OpenebsNamespace, v.OpenebsClientset)
+	mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, v.OpenebsNamespace, v.OpenebsClientset, v)
 	if uerr != nil && IsMigrationTaskJob {
 		return uerr
 	}
 
 	statusObj = v1Alpha1API.MigrationDetailedStatuses{Step: "Migrate"}
 	statusObj.Phase = v1Alpha1API.StepWaiting
-	mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, v.OpenebsNamespace, v.OpenebsClientset)
+	mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, v.OpenebsNamespace, v.OpenebsClientset, v)
 	if uerr != nil && IsMigrationTaskJob {
 		return uerr
 	}
@@ -120,7 +119,7 @@ func (v *VolumeMigrator) Migrate(pvName, openebsNamespace string) error {
 	if err != nil {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
-		mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, v.OpenebsNamespace, v.OpenebsClientset)
+		mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, v.OpenebsNamespace, v.OpenebsClientset, v)
 		if uerr != nil && IsMigrationTaskJob {
 			return uerr
 		}
@@ -129,7 +128,7 @@ func (v *VolumeMigrator) Migrate(pvName, openebsNamespace string) error {
 	statusObj.Phase = v1Alpha1API.StepCompleted
 	statusObj.Message = "Migration steps were successful"
 	statusObj.Reason = ""
-	mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, v.OpenebsNamespace, v.OpenebsClientset)
+	mtask, uerr = updateMigrationDetailedStatus(mtask, statusObj, v.OpenebsNamespace, v.OpenebsClientset, v)
 	if uerr != nil && IsMigrationTaskJob {
 		return uerr
 	}
@@ -156,7 +155,7 @@ func (v *VolumeMigrator) migrateAll(pvName string) (string, error) {
 		msg = "failed to delete temporary policy " + pvName
 		return msg, err
 	}
-	snap := &SnapshotMigrator{}
+	snap := &SnapshotMigrator{snapClient: v.SnapClientset, maya: v.Maya}
 	err = snap.migrate(pvName)
 	if err != nil {
 		msg = "failed to migrate snapshots for volume " + pvName
@@ -177,7 +176,7 @@ func (v *VolumeMigrator) preMigrate() (string, error) {
 }
 
 func (v *VolumeMigrator) isMigrationRequired() (bool, error) {
-	cvList, err := cv.NewKubeclient().WithNamespace("").
+	cvList, err := newCVClient(v.Maya).WithNamespace("").
 		List(metav1.ListOptions{
 			LabelSelector: "openebs.io/persistent-volume=" + v.PVName,
 		})
@@ -478,7 +477,7 @@ func (v *VolumeMigrator) generateCSIPVFromCV(
 	pvcObj *corev1.PersistentVolumeClaim,
 ) (*corev1.PersistentVolume, error) {
 	klog.Infof("Generating equivalent CSI PV %s", v.PVName)
-	cvObj, err := cv.NewKubeclient().WithNamespace(v.CVNamespace).
+	cvObj, err := newCVClient(v.Maya).WithNamespace(v.CVNamespace).
 		Get(cvName, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
@@ -527,12 +526,12 @@ func (v *VolumeMigrator) createCVC(pvObj *corev1.PersistentVolume) error {
 		return err
 	}
 	if k8serrors.IsNotFound(err) {
-		cvObj, err = cv.NewKubeclient().WithNamespace(v.CVNamespace).
+		cvObj, err = newCVClient(v.Maya).WithNamespace(v.CVNamespace).
 			Get(v.PVName, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
-		cvrList, err := cvr.NewKubeclient().WithNamespace(v.OpenebsNamespace).
+		cvrList, err := newCVRClient(v.Maya).WithNamespace(v.OpenebsNamespace).
 			List(metav1.ListOptions{
 				LabelSelector: "",
 			})
@@ -739,7 +738,7 @@ func (v *VolumeMigrator) createTmpSC(scName string) (*storagev1.StorageClass, er
 }
 
 func (v *VolumeMigrator) getReplicaCount(pvName string) (string, error) {
-	cvObj, err := cv.NewKubeclient().WithNamespace(v.CVNamespace).
+	cvObj, err := newCVClient(v.Maya).WithNamespace(v.CVNamespace).
 		Get(pvName, metav1.GetOptions{})
 	if err != nil {
 		return "", err
@@ -750,7 +749,7 @@ func (v *VolumeMigrator) getReplicaCount(pvName string) (string, error) {
 // the cv can be in the pvc namespace or openebs namespace
 func (v *VolumeMigrator) populateCVNamespace(cvName string) error {
 	v.CVNamespace = v.OpenebsNamespace
-	cvList, err := cv.NewKubeclient().WithNamespace("").
+	cvList, err := newCVClient(v.Maya).WithNamespace("").
 		List(metav1.ListOptions{
 			LabelSelector: "openebs.io/persistent-volume=" + v.PVName,
 		})
@@ -770,7 +769,7 @@ func (v *VolumeMigrator) populateCVNamespace(cvName string) error {
 }
 
 func (v *VolumeMigrator) getCSPCName(pvName string) (string, error) {
-	cvrList, err := cvr.NewKubeclient().WithNamespace(v.OpenebsNamespace).
+	cvrList, err := newCVRClient(v.Maya).WithNamespace(v.OpenebsNamespace).
 		List(metav1.ListOptions{
 			LabelSelector: "openebs.io/persistent-volume=" + pvName,
 		})
@@ -882,7 +881,7 @@ func (v *VolumeMigrator) migrateTargetSVC() error {
 }
 
 func (v *VolumeMigrator) getTargetSVC() (*corev1.Service, error) {
-	cvObj, err := cv.NewKubeclient().WithNamespace(v.CVNamespace).
+	cvObj, err := newCVClient(v.Maya).WithNamespace(v.CVNamespace).
 		Get(v.PVName, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
@@ -954,12 +953,12 @@ func (v *VolumeMigrator) createTempPolicy() error {
 	if err != nil {
 		return err
 	}
-	cvObj, err := cv.NewKubeclient().WithNamespace(v.CVNamespace).
+	cvObj, err := newCVClient(v.Maya).WithNamespace(v.CVNamespace).
 		Get(v.PVName, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
-	replicas, err := cvr.NewKubeclient().
+	replicas, err := newCVRClient(v.Maya).
 		WithNamespace(v.OpenebsNamespace).
 		List(metav1.ListOptions{
 			LabelSelector: "openebs.io/persistent-volume=" + v.PVName,
@@ -1162,7 +1161,7 @@ func (v *VolumeMigrator) patchTargetPodAffinity() error {
 
 func (v *VolumeMigrator) cleanupOldResources() error {
 	klog.Info("Cleaning up old volume resources")
-	cvrList, err := cvr.NewKubeclient().
+	cvrList, err := newCVRClient(v.Maya).
 		WithNamespace(v.OpenebsNamespace).
 		List(metav1.ListOptions{
 			LabelSelector: "openebs.io/persistent-volume=" + v.PVName,
@@ -1173,13 +1172,13 @@ func (v *VolumeMigrator) cleanupOldResources() error {
 	for _, replica := range cvrList.Items {
 		rep := replica // pin it
 		rep.Finalizers = []string{}
-		_, err = cvr.NewKubeclient().
+		_, err = newCVRClient(v.Maya).
 			WithNamespace(v.OpenebsNamespace).
 			Update(&rep)
 		if err != nil {
 			return errors.Wrapf(err, "failed to remove finalizer from cvr %s", rep.Name)
 		}
-		err = cvr.NewKubeclient().
+		err = newCVRClient(v.Maya).
 			WithNamespace(v.OpenebsNamespace).
 			Delete(replica.Name)
 		if err != nil {
@@ -1205,7 +1204,7 @@ func (v *VolumeMigrator) cleanupOldResources() error {
 	if err != nil {
 		return err
 	}
-	err = cv.NewKubeclient().
+	err = newCVClient(v.Maya).
 		WithNamespace(v.CVNamespace).
 		Delete(v.PVName)
 	if err != nil && !k8serrors.IsNotFound(err) {
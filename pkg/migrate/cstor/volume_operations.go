@@ -25,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 )
 
@@ -38,27 +39,40 @@ func (v *VolumeMigrator) IsVolumeMounted(pvName string) (*corev1.PersistentVolum
 	if err != nil {
 		return nil, err
 	}
-	pvcName := pvObj.Spec.ClaimRef.Name
-	pvcNamespace := pvObj.Spec.ClaimRef.Namespace
-	podList, err := v.KubeClientset.CoreV1().Pods(pvcNamespace).
+	pods, err := PodsUsingClaim(v.KubeClientset,
+		pvObj.Spec.ClaimRef.Namespace, pvObj.Spec.ClaimRef.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) != 0 {
+		return nil, errors.Errorf(
+			"the volume %s is mounted on %s, please scale down all apps before migrating",
+			pvName,
+			pods[0].Name,
+		)
+	}
+	return pvObj, nil
+}
+
+// PodsUsingClaim returns the pods of the given namespace
+// which have the given pvc as one of their volumes
+func PodsUsingClaim(client kubernetes.Interface, namespace, claim string) ([]corev1.Pod, error) {
+	podList, err := client.CoreV1().Pods(namespace).
 		List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
+	pods := []corev1.Pod{}
 	for _, podObj := range podList.Items {
 		for _, volume := range podObj.Spec.Volumes {
-			if volume.PersistentVolumeClaim != nil {
-				if volume.PersistentVolumeClaim.ClaimName == pvcName {
-					return nil, errors.Errorf(
-						"the volume %s is mounted on %s, please scale down all apps before migrating",
-						pvName,
-						podObj.Name,
-					)
-				}
+			if volume.PersistentVolumeClaim != nil &&
+				volume.PersistentVolumeClaim.ClaimName == claim {
+				pods = append(pods, podObj)
+				break
 			}
 		}
 	}
-	return pvObj, nil
+	return pods, nil
 }
 
 // RetainPV sets the Retain policy on the PV.
@@ -0,0 +1,172 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// PhaseSucceeded, PhaseFailed, PhaseSkipped, PhaseDryRun and
+	// PhaseCancelled are the final phases of the resources in the report
+	PhaseSucceeded = "Succeeded"
+	PhaseFailed    = "Failed"
+	PhaseSkipped   = "Skipped"
+	PhaseDryRun    = "DryRun"
+	PhaseCancelled = "Cancelled"
+
+	// FormatJSON and FormatYAML are the formats of the report
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// Report is the result of an upgrade or migrate run, the
+// resources can be added concurrently by parallel upgrades
+type Report struct {
+	Operation      string     `json:"operation"`
+	Phase          string     `json:"phase"`
+	StartTime      time.Time  `json:"startTime"`
+	CompletionTime time.Time  `json:"completionTime"`
+	Resources      []Resource `json:"resources"`
+
+	lock sync.Mutex
+}
+
+// Resource is the result of the upgrade or migration of a resource
+type Resource struct {
+	Kind        string  `json:"kind"`
+	Name        string  `json:"name"`
+	FromVersion string  `json:"fromVersion,omitempty"`
+	ToVersion   string  `json:"toVersion,omitempty"`
+	Phase       string  `json:"phase"`
+	Reason      string  `json:"reason,omitempty"`
+	Error       string  `json:"error,omitempty"`
+	Steps       []Step  `json:"steps,omitempty"`
+	Patches     []Patch `json:"patches,omitempty"`
+}
+
+// Step is a step of the upgrade or migration
+// as recorded on the UpgradeTask or MigrationTask
+type Step struct {
+	Name            string    `json:"name"`
+	Phase           string    `json:"phase"`
+	Message         string    `json:"message,omitempty"`
+	Reason          string    `json:"reason,omitempty"`
+	StartTime       time.Time `json:"startTime"`
+	LastUpdatedTime time.Time `json:"lastUpdatedTime"`
+}
+
+// Patch is a patch applied to an object
+type Patch struct {
+	Kind      string          `json:"kind"`
+	Namespace string          `json:"namespace,omitempty"`
+	Name      string          `json:"name"`
+	Patch     json.RawMessage `json:"patch"`
+}
+
+// New returns an empty report for the given operation
+func New(operation string) *Report {
+	return &Report{
+		Operation: operation,
+		StartTime: time.Now(),
+		Resources: []Resource{},
+	}
+}
+
+// Add adds the result of a resource to the report
+func (r *Report) Add(res Resource) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.Resources = append(r.Resources, res)
+}
+
+// ValidateFormat returns an error if the format is not supported
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatJSON, FormatYAML:
+		return nil
+	}
+	return errors.Errorf("invalid report format %q, should be %s or %s",
+		format, FormatJSON, FormatYAML)
+}
+
+// Write completes the report and writes it in the given format,
+// the run failed if any of the resources failed
+func (r *Report) Write(w io.Writer, format string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.CompletionTime = time.Now()
+	r.Phase = PhaseSucceeded
+	for _, res := range r.Resources {
+		if res.Phase == PhaseFailed {
+			r.Phase = PhaseFailed
+		}
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal report")
+	}
+	if format == FormatYAML {
+		data, err = yaml.JSONToYAML(data)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal report")
+		}
+	} else {
+		data = append(data, '\n')
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// UpgradeSteps returns the steps recorded on the UpgradeTask
+func UpgradeSteps(utaskObj *v1Alpha1API.UpgradeTask) []Step {
+	steps := []Step{}
+	for _, s := range utaskObj.Status.UpgradeDetailedStatuses {
+		steps = append(steps, Step{
+			Name:            string(s.Step),
+			Phase:           string(s.Phase),
+			Message:         s.Message,
+			Reason:          s.Reason,
+			StartTime:       s.StartTime.Time,
+			LastUpdatedTime: s.LastUpdatedTime.Time,
+		})
+	}
+	return steps
+}
+
+// MigrationSteps returns the steps recorded on the MigrationTask
+func MigrationSteps(mtaskObj *v1Alpha1API.MigrationTask) []Step {
+	steps := []Step{}
+	for _, s := range mtaskObj.Status.MigrationDetailedStatuses {
+		steps = append(steps, Step{
+			Name:            s.Step,
+			Phase:           string(s.Phase),
+			Message:         s.Message,
+			Reason:          s.Reason,
+			StartTime:       s.StartTime.Time,
+			LastUpdatedTime: s.LastUpdatedTime.Time,
+		})
+	}
+	return steps
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+func TestReport_Write(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources []Resource
+		format    string
+		wantPhase string
+	}{
+		{
+			name: "all succeeded in json",
+			resources: []Resource{
+				{
+					Kind:        "cstorVolume",
+					Name:        "pvc-1",
+					FromVersion: "3.4.0",
+					ToVersion:   "3.5.0",
+					Phase:       PhaseSucceeded,
+					Patches: []Patch{
+						{
+							Kind:      "Deployment",
+							Namespace: "openebs",
+							Name:      "pvc-1-target",
+							Patch:     json.RawMessage(`{"metadata":{"labels":{"openebs.io/version":"3.5.0"}}}`),
+						},
+					},
+				},
+				{Kind: "cstorVolume", Name: "pvc-2", Phase: PhaseSkipped, Reason: "already upgraded"},
+			},
+			format:    FormatJSON,
+			wantPhase: PhaseSucceeded,
+		},
+		{
+			name: "one failed in yaml",
+			resources: []Resource{
+				{Kind: "jivaVolume", Name: "pvc-1", Phase: PhaseSucceeded},
+				{Kind: "jivaVolume", Name: "pvc-2", Phase: PhaseFailed, Error: "timed out"},
+			},
+			format:    FormatYAML,
+			wantPhase: PhaseFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New("upgrade")
+			for _, res := range tt.resources {
+				r.Add(res)
+			}
+			var buf bytes.Buffer
+			err := r.Write(&buf, tt.format)
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			data := buf.Bytes()
+			if tt.format == FormatYAML {
+				data, err = yaml.YAMLToJSON(data)
+				if err != nil {
+					t.Fatalf("Write() wrote invalid yaml: %v", err)
+				}
+			}
+			got := &Report{}
+			err = json.Unmarshal(data, got)
+			if err != nil {
+				t.Fatalf("Write() wrote invalid report: %v", err)
+			}
+			if got.Operation != "upgrade" || got.Phase != tt.wantPhase ||
+				len(got.Resources) != len(tt.resources) {
+				t.Errorf("Write() = %s", buf.String())
+			}
+			for i, res := range got.Resources {
+				if len(res.Patches) != len(tt.resources[i].Patches) {
+					t.Errorf("Write() patches of %s = %v", res.Name, res.Patches)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatYAML} {
+		if err := ValidateFormat(format); err != nil {
+			t.Errorf("ValidateFormat(%s) error = %v", format, err)
+		}
+	}
+	err := ValidateFormat("table")
+	if err == nil || !strings.Contains(err.Error(), "invalid report format") {
+		t.Errorf("ValidateFormat(table) error = %v", err)
+	}
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	// the timezones are embedded as the job image
+	// does not have the timezone database
+	_ "time/tzdata"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// WindowAnnotation is set on an UpgradeTask or a MigrationTask to
+	// restrict the task to maintenance windows. Each window is a cron
+	// expression for its start followed by its duration, the windows
+	// are separated by ";", for example "0 22 * * 1-5 4h; 0 2 * * 6 6h"
+	WindowAnnotation = "openebs.io/maintenance-window"
+	// TimezoneAnnotation is the IANA timezone of the maintenance
+	// windows, for example "Europe/Berlin", the default is UTC
+	TimezoneAnnotation = "openebs.io/maintenance-window-timezone"
+
+	// searchLimit bounds the search of the start of a window
+	// so that an expression that never matches, like the
+	// 30th of February, does not loop forever
+	searchLimit = 5 * 366 * 24 * time.Hour
+)
+
+// Schedule is a set of maintenance windows
+type Schedule struct {
+	Windows  []Window
+	Location *time.Location
+}
+
+// Window is a maintenance window starting when its
+// cron expression matches and open for its duration
+type Window struct {
+	start    cron
+	Duration time.Duration
+}
+
+// FromAnnotations returns the schedule of the maintenance
+// window annotations, or nil if there are no windows
+func FromAnnotations(annotations map[string]string) (*Schedule, error) {
+	windows := strings.TrimSpace(annotations[WindowAnnotation])
+	if windows == "" {
+		return nil, nil
+	}
+	return Parse(windows, annotations[TimezoneAnnotation])
+}
+
+// Parse returns the schedule of the given windows in the given timezone
+func Parse(windows, timezone string) (*Schedule, error) {
+	loc := time.UTC
+	if timezone = strings.TrimSpace(timezone); timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(timezone)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid maintenance window timezone %q", timezone)
+		}
+	}
+	s := &Schedule{Location: loc}
+	for _, w := range strings.Split(windows, ";") {
+		if strings.TrimSpace(w) == "" {
+			continue
+		}
+		window, err := parseWindow(w)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid maintenance window %q", strings.TrimSpace(w))
+		}
+		s.Windows = append(s.Windows, window)
+	}
+	if len(s.Windows) == 0 {
+		return nil, errors.Errorf("no maintenance window in %q", windows)
+	}
+	return s, nil
+}
+
+func parseWindow(w string) (Window, error) {
+	fields := strings.Fields(w)
+	if len(fields) != 6 {
+		return Window{}, errors.Errorf("expected 5 cron fields and a duration, got %d fields", len(fields))
+	}
+	start, err := parseCron(fields[:5])
+	if err != nil {
+		return Window{}, err
+	}
+	d, err := time.ParseDuration(fields[5])
+	if err != nil {
+		return Window{}, errors.Wrap(err, "invalid duration")
+	}
+	if d < time.Minute {
+		return Window{}, errors.Errorf("duration %s is shorter than a minute", d)
+	}
+	return Window{start: start, Duration: d}, nil
+}
+
+// Open returns true if the given time is in one of the windows
+func (s *Schedule) Open(t time.Time) bool {
+	t = t.In(s.Location)
+	for _, w := range s.Windows {
+		// the window is open if it started after t - duration,
+		// the window is closed at start + duration
+		start := w.start.next(t.Add(-w.Duration).Add(time.Nanosecond))
+		if !start.IsZero() && !start.After(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the given time if it is in one of the windows, or
+// the start of the next window. The zero time is returned if
+// no window starts within the next five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.Open(t) {
+		return t
+	}
+	t = t.In(s.Location)
+	next := time.Time{}
+	for _, w := range s.Windows {
+		start := w.start.next(t)
+		if !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	return next
+}
+
+// cron is a cron expression, each field is the bitset of its values
+type cron struct {
+	minute, hour, dom, month, dow uint64
+	// domAll and dowAll are set if the day of month or the
+	// day of week is *, as a day matches either of them
+	// when both are restricted
+	domAll, dowAll bool
+}
+
+// cronFields are the bounds of the fields of a cron expression
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+func parseCron(fields []string) (cron, error) {
+	bits := make([]uint64, len(fields))
+	for i, f := range fields {
+		var err error
+		bits[i], err = parseField(f, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return cron{}, errors.Wrapf(err, "invalid %s %q", cronFields[i].name, f)
+		}
+	}
+	// 7 is also Sunday
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return cron{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAll: fields[2] == "*",
+		dowAll: fields[4] == "*",
+	}, nil
+}
+
+// parseField parses a comma separated list of *, values and
+// ranges, each with an optional step, like "*/15" or "1-5/2"
+func parseField(f string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(f, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rng = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, errors.Errorf("invalid step %q", part[i+1:])
+			}
+		}
+		lo, hi := min, max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			i := strings.Index(rng, "-")
+			var err error
+			lo, err = parseValue(rng[:i], min, max)
+			if err != nil {
+				return 0, err
+			}
+			hi, err = parseValue(rng[i+1:], min, max)
+			if err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, errors.Errorf("invalid range %q", rng)
+			}
+		default:
+			var err error
+			lo, err = parseValue(rng, min, max)
+			if err != nil {
+				return 0, err
+			}
+			hi = lo
+			if step != 1 {
+				hi = max
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s string, min, max int) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.Errorf("invalid value %q", s)
+	}
+	if v < min || v > max {
+		return 0, errors.Errorf("value %d out of range %d-%d", v, min, max)
+	}
+	return v, nil
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
+
+func (c cron) matchesDay(t time.Time) bool {
+	dom, dow := has(c.dom, t.Day()), has(c.dow, int(t.Weekday()))
+	if c.domAll || c.dowAll {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// next returns the first minute at or after t matching the
+// expression, in the location of t, or the zero time if
+// none matches within the search limit
+func (c cron) next(t time.Time) time.Time {
+	loc := t.Location()
+	if r := t.Truncate(time.Minute); r.Before(t) {
+		t = r.Add(time.Minute)
+	}
+	limit := t.Add(searchLimit)
+	for t.Before(limit) {
+		prev := t
+		switch {
+		case !has(c.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !has(c.hour, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !has(c.minute, t.Minute()):
+			t = t.Truncate(time.Minute).Add(time.Minute)
+		default:
+			return t
+		}
+		// the clock can go back at the end of the
+		// daylight saving time, always move forward
+		if !t.After(prev) {
+			t = prev.Truncate(time.Minute).Add(time.Minute)
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		windows, timezone string
+		wantErr           bool
+	}{
+		"single window":          {windows: "0 22 * * 1-5 4h"},
+		"several windows":        {windows: "0 22 * * 1-5 4h; */30 2 1,15 * 0 30m;"},
+		"timezone":               {windows: "0 22 * * * 4h", timezone: "Europe/Berlin"},
+		"missing duration":       {windows: "0 22 * * *", wantErr: true},
+		"invalid duration":       {windows: "0 22 * * * 4", wantErr: true},
+		"duration below minute":  {windows: "0 22 * * * 30s", wantErr: true},
+		"minute out of range":    {windows: "60 22 * * * 4h", wantErr: true},
+		"inverted range":         {windows: "0 22 * * 5-1 4h", wantErr: true},
+		"invalid step":           {windows: "*/0 22 * * * 4h", wantErr: true},
+		"no window":              {windows: " ; ", wantErr: true},
+		"unknown timezone":       {windows: "0 22 * * * 4h", timezone: "Mars/Olympus", wantErr: true},
+		"named values":           {windows: "0 22 * * MON 4h", wantErr: true},
+		"sunday as seven":        {windows: "0 22 * * 7 4h"},
+		"step from single value": {windows: "5/20 * * * * 10m"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(test.windows, test.timezone)
+			if (err != nil) != test.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestFromAnnotations(t *testing.T) {
+	s, err := FromAnnotations(map[string]string{})
+	if err != nil || s != nil {
+		t.Errorf("FromAnnotations() = %v, %v, want no schedule", s, err)
+	}
+	s, err = FromAnnotations(map[string]string{
+		WindowAnnotation:   "0 22 * * * 4h",
+		TimezoneAnnotation: "Asia/Kolkata",
+	})
+	if err != nil {
+		t.Fatalf("FromAnnotations() error = %v", err)
+	}
+	if s.Location.String() != "Asia/Kolkata" || len(s.Windows) != 1 {
+		t.Errorf("FromAnnotations() = %v, want one window in Asia/Kolkata", s)
+	}
+}
+
+func TestScheduleOpenAndNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := func(loc *time.Location, month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, loc)
+	}
+	tests := map[string]struct {
+		windows, timezone string
+		at                time.Time
+		wantOpen          bool
+		wantNext          time.Time
+	}{
+		// 2026-10-16 is a Friday
+		"inside a weekday window": {
+			windows:  "0 22 * * 1-5 4h",
+			at:       date(time.UTC, time.October, 16, 23, 30),
+			wantOpen: true,
+			wantNext: date(time.UTC, time.October, 16, 23, 30),
+		},
+		"window spanning midnight": {
+			windows:  "0 22 * * 1-5 4h",
+			at:       date(time.UTC, time.October, 17, 1, 59),
+			wantOpen: true,
+			wantNext: date(time.UTC, time.October, 17, 1, 59),
+		},
+		"window closed at its end": {
+			windows:  "0 22 * * 1-5 4h",
+			at:       date(time.UTC, time.October, 17, 2, 0),
+			wantNext: date(time.UTC, time.October, 19, 22, 0),
+		},
+		"before the window": {
+			windows:  "0 22 * * 1-5 4h",
+			at:       date(time.UTC, time.October, 16, 21, 59),
+			wantNext: date(time.UTC, time.October, 16, 22, 0),
+		},
+		"earliest of several windows": {
+			windows:  "0 22 * * 1-5 4h; 0 2 * * 6 6h",
+			at:       date(time.UTC, time.October, 17, 2, 0),
+			wantOpen: true,
+			wantNext: date(time.UTC, time.October, 17, 2, 0),
+		},
+		"day of month or day of week": {
+			windows:  "0 3 1 * 0 1h",
+			at:       date(time.UTC, time.October, 12, 0, 0),
+			wantNext: date(time.UTC, time.October, 18, 3, 0),
+		},
+		"window in a timezone": {
+			windows:  "0 22 * * * 1h",
+			timezone: "Europe/Berlin",
+			at:       date(time.UTC, time.October, 16, 20, 30),
+			wantOpen: true,
+			wantNext: date(time.UTC, time.October, 16, 20, 30),
+		},
+		"window across the end of daylight saving time": {
+			windows:  "30 2 * * * 1h",
+			timezone: "Europe/Berlin",
+			at:       date(berlin, time.October, 24, 12, 0),
+			wantNext: date(berlin, time.October, 25, 2, 30),
+		},
+		"expression never matching": {
+			windows: "0 0 30 2 * 1h",
+			at:      date(time.UTC, time.October, 16, 0, 0),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := Parse(test.windows, test.timezone)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := s.Open(test.at); got != test.wantOpen {
+				t.Errorf("Open(%s) = %v, want %v", test.at, got, test.wantOpen)
+			}
+			if got := s.Next(test.at); !got.Equal(test.wantNext) {
+				t.Errorf("Next(%s) = %s, want %s", test.at, got, test.wantNext)
+			}
+		})
+	}
+}
+
+func TestNextOpen(t *testing.T) {
+	// 2026-10-17 is a Saturday
+	at := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		window   string
+		wantNext time.Time
+		wantErr  bool
+	}{
+		"no window":   {},
+		"window open": {window: "0 10 * * 6 4h"},
+		"window closed": {
+			window:   "0 22 * * 1-5 4h",
+			wantNext: time.Date(2026, time.October, 19, 22, 0, 0, 0, time.UTC),
+		},
+		"invalid window":       {window: "0 10 * * 6", wantErr: true},
+		"window never opening": {window: "0 0 30 2 * 1h", wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			annotations := map[string]string{}
+			if test.window != "" {
+				annotations[WindowAnnotation] = test.window
+			}
+			next, err := NextOpen(annotations, at)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("NextOpen() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !next.Equal(test.wantNext) {
+				t.Errorf("NextOpen() = %v, want %v", next, test.wantNext)
+			}
+		})
+	}
+}
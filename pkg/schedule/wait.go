@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/openebs/upgrade/pkg/upgrade/wait"
+)
+
+var (
+	// CheckInterval is the interval between two checks
+	// of the maintenance window while it is closed
+	CheckInterval = time.Minute
+
+	// Now returns the current time, it is replaced by the tests
+	Now = time.Now
+)
+
+// AnnotationsFunc returns the latest annotations of the
+// task whose maintenance window is waited for
+type AnnotationsFunc func(ctx context.Context) (map[string]string, error)
+
+// NextOpen returns the start of the next maintenance window of the
+// annotations, or the zero time if a window is open at the given time
+// or there are no windows. An error is returned if the windows are
+// invalid or if none of them opens within the next five years.
+func NextOpen(annotations map[string]string, now time.Time) (time.Time, error) {
+	s, err := FromAnnotations(annotations)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if s == nil || s.Open(now) {
+		return time.Time{}, nil
+	}
+	next := s.Next(now)
+	if next.IsZero() {
+		return next, errors.Errorf("no maintenance window opens within five years")
+	}
+	return next, nil
+}
+
+// Wait waits until the maintenance window of the annotations returned by
+// get is open. The annotations are read after every CheckInterval, so that
+// the windows can be changed or removed while waiting. The description is
+// of the form "maintenance window of <task>".
+func Wait(ctx context.Context, desc string, get AnnotationsFunc) error {
+	return wait.For(ctx, desc, CheckInterval, 0,
+		func(ctx context.Context) (bool, error) {
+			annotations, err := get(ctx)
+			if err != nil {
+				return false, err
+			}
+			s, err := FromAnnotations(annotations)
+			if err != nil {
+				return false, errors.Wrap(err, "invalid "+desc)
+			}
+			return s == nil || s.Open(Now()), nil
+		},
+	)
+}
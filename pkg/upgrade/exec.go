@@ -17,24 +17,103 @@ limitations under the License.
 package executor
 
 import (
+	"context"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	"github.com/pkg/errors"
+
+	"github.com/openebs/upgrade/pkg/kubeclient"
+	"github.com/openebs/upgrade/pkg/metrics"
 	upgrader "github.com/openebs/upgrade/pkg/upgrade/upgrader"
 )
 
-// Exec ...
+// Exec upgrades the given resource, the result is
+// recorded in the resource counts of the metrics
 func Exec(fromVersion, toVersion, kind, name,
-	openebsNamespace, urlprefix, imagetag string) error {
-	rp := upgrader.NewResourcePatch(
-		upgrader.FromVersion(fromVersion),
-		upgrader.ToVersion(toVersion),
-		upgrader.WithName(name),
-		upgrader.WithOpenebsNamespace(openebsNamespace),
-		upgrader.WithBaseURL(urlprefix),
-		upgrader.WithImageTag(imagetag),
-	)
-	u := upgrader.NewUpgrade()
-	err := u.UpgradeMap[kind](rp, u.Client).Upgrade()
+	openebsNamespace, urlprefix, imagetag string,
+	clientset *kubeclient.Clientset, opts ...upgrader.ResourcePatchOptions) (err error) {
+	defer func() {
+		metrics.ResourceDone(metrics.Upgrade, kind, err)
+	}()
+	u, err := newUpgrader(fromVersion, toVersion, kind, name,
+		openebsNamespace, urlprefix, imagetag, clientset, opts...)
+	if err != nil {
+		return err
+	}
+	err = u.Upgrade()
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// WaitToStart waits until the upgrade of the given UpgradeTask can
+// start: it is not paused and its maintenance window is open
+func WaitToStart(ctx context.Context, utaskObj *v1Alpha1API.UpgradeTask,
+	clientset *kubeclient.Clientset) error {
+	return upgrader.WaitToStart(ctx, utaskObj, newClient(clientset))
+}
+
+// DryRun returns the changes that would be applied to upgrade
+// the given resource without modifying anything in the cluster
+func DryRun(fromVersion, toVersion, kind, name,
+	openebsNamespace, urlprefix, imagetag string,
+	clientset *kubeclient.Clientset, opts ...upgrader.ResourcePatchOptions) ([]upgrader.Change, error) {
+	u, err := newUpgrader(fromVersion, toVersion, kind, name,
+		openebsNamespace, urlprefix, imagetag, clientset, opts...)
+	if err != nil {
+		return nil, err
+	}
+	d, ok := u.(upgrader.DryRunner)
+	if !ok {
+		return nil, errors.Errorf("dry-run is not supported for %s", kind)
+	}
+	return d.DryRun()
+}
+
+// Rollback restores the given resource to the objects
+// recorded before it was upgraded
+func Rollback(kind, name, openebsNamespace string,
+	clientset *kubeclient.Clientset, opts ...upgrader.ResourcePatchOptions) error {
+	u, err := newUpgrader("", "", kind, name,
+		openebsNamespace, "", "", clientset, opts...)
+	if err != nil {
+		return err
+	}
+	r, ok := u.(upgrader.Rollbacker)
+	if !ok {
+		return errors.Errorf("rollback is not supported for %s", kind)
+	}
+	return r.Rollback()
+}
+
+func newUpgrader(fromVersion, toVersion, kind, name,
+	openebsNamespace, urlprefix, imagetag string,
+	clientset *kubeclient.Clientset, opts ...upgrader.ResourcePatchOptions) (upgrader.Upgrader, error) {
+	u := upgrader.NewUpgrade(newClient(clientset))
+	rp := upgrader.NewResourcePatch(
+		append([]upgrader.ResourcePatchOptions{
+			upgrader.FromVersion(fromVersion),
+			upgrader.ToVersion(toVersion),
+			upgrader.WithName(name),
+			upgrader.WithOpenebsNamespace(openebsNamespace),
+			upgrader.WithBaseURL(urlprefix),
+			upgrader.WithImageTag(imagetag),
+			upgrader.WithUpgradeTaskJob(u.IsUpgradeTaskJob),
+		}, opts...)...,
+	)
+	newFunc, ok := u.UpgradeMap[kind]
+	if !ok {
+		return nil, errors.Errorf("invalid resource kind %s", kind)
+	}
+	return newFunc(rp, u.Client), nil
+}
+
+func newClient(clientset *kubeclient.Clientset) *upgrader.Client {
+	return &upgrader.Client{
+		KubeClientset:    clientset.KubeClientset,
+		OpenebsClientset: clientset.OpenebsClientset,
+		RuntimeClient:    clientset.RuntimeClient,
+		Recorder:         clientset.Recorder,
+	}
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	cstor "github.com/openebs/api/v3/pkg/apis/cstor/v1"
+	"github.com/openebs/api/v3/pkg/apis/types"
+	jv "github.com/openebs/jiva-operator/pkg/apis/openebs/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openebs/upgrade/pkg/kubeclient"
+	migrate "github.com/openebs/upgrade/pkg/migrate/cstor"
+	upgrader "github.com/openebs/upgrade/pkg/upgrade/upgrader"
+)
+
+const (
+	// cstorCSIDriver and jivaCSIDriver are the drivers
+	// of the cstor and jiva csi volumes
+	cstorCSIDriver = "cstor.csi.openebs.io"
+	jivaCSIDriver  = "jiva.csi.openebs.io"
+)
+
+var (
+	// targetRestartPause is the expected IO pause while the cstor
+	// target or the jiva controller pod is recreated and the iSCSI
+	// initiator logs in again
+	targetRestartPause = 30 * time.Second
+	// replicaRestartPause is the expected IO pause while the only
+	// replica of a jiva volume is recreated, the replicas of the
+	// other volumes are restarted one at a time without any pause
+	replicaRestartPause = 30 * time.Second
+)
+
+// ImpactedPod is a pod using the volume
+type ImpactedPod struct {
+	Name     string
+	Node     string
+	Workload string
+}
+
+// VolumeImpact is who feels the restart of the target
+// and of the replicas of a volume during its upgrade
+type VolumeImpact struct {
+	Name string
+	Kind string
+	// PVC is the namespace/name of the claim bound to the volume
+	PVC       string
+	Pods      []ImpactedPod
+	Workloads []string
+	// InitiatorNodes are the nodes the iSCSI initiator
+	// logged in to the volume target runs on
+	InitiatorNodes  []string
+	Replicas        int
+	HealthyReplicas int
+	ExpectedIOPause time.Duration
+	Warnings        []string
+}
+
+// AnalyzeImpact returns the applications affected by the upgrade of the
+// given volume, the kind of the volume is read from the csi driver of the pv
+func AnalyzeImpact(name, openebsNamespace string, clientset *kubeclient.Clientset) (*VolumeImpact, error) {
+	pv, err := clientset.KubeClientset.CoreV1().PersistentVolumes().
+		Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get pv %s", name)
+	}
+	impact := &VolumeImpact{Name: name}
+	switch {
+	case pv.Spec.CSI != nil && pv.Spec.CSI.Driver == cstorCSIDriver:
+		impact.Kind = "cstorVolume"
+		err = cstorReplicaImpact(impact, openebsNamespace, clientset)
+	case pv.Spec.CSI != nil && pv.Spec.CSI.Driver == jivaCSIDriver:
+		impact.Kind = "jivaVolume"
+		err = jivaReplicaImpact(impact, openebsNamespace, clientset)
+	default:
+		return nil, errors.Errorf("pv %s is not a cstor or jiva csi volume", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if pv.Spec.ClaimRef != nil {
+		impact.PVC = pv.Spec.ClaimRef.Namespace + "/" + pv.Spec.ClaimRef.Name
+		err = consumerImpact(impact, pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name, clientset)
+		if err != nil {
+			return nil, err
+		}
+	}
+	impact.InitiatorNodes, err = initiatorNodes(impact, clientset)
+	if err != nil {
+		return nil, err
+	}
+	return impact, nil
+}
+
+// cstorReplicaImpact sets the replicas of the cstor volume, restarting
+// the target pauses the IO and needs a quorum of healthy replicas
+func cstorReplicaImpact(impact *VolumeImpact, openebsNamespace string,
+	clientset *kubeclient.Clientset) error {
+	cv, err := clientset.OpenebsClientset.CstorV1().CStorVolumes(openebsNamespace).
+		Get(context.TODO(), impact.Name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get cstorvolume %s", impact.Name)
+	}
+	cvrList, err := clientset.OpenebsClientset.CstorV1().CStorVolumeReplicas(openebsNamespace).
+		List(context.TODO(), metav1.ListOptions{
+			LabelSelector: types.PersistentVolumeLabelKey + "=" + impact.Name,
+		})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list cvrs of %s", impact.Name)
+	}
+	impact.Replicas = cv.Spec.ReplicationFactor
+	if impact.Replicas < len(cvrList.Items) {
+		impact.Replicas = len(cvrList.Items)
+	}
+	for _, cvr := range cvrList.Items {
+		if cvr.Status.Phase == cstor.CVRStatusOnline {
+			impact.HealthyReplicas++
+			continue
+		}
+		impact.Warnings = append(impact.Warnings,
+			fmt.Sprintf("cvr %s is %s", cvr.Name, upgrader.PhaseOf(string(cvr.Status.Phase))))
+	}
+	impact.ExpectedIOPause = targetRestartPause
+	if impact.Replicas == 1 {
+		impact.Warnings = append(impact.Warnings, "single replica volume")
+	}
+	switch quorum := impact.Replicas/2 + 1; {
+	case impact.HealthyReplicas < quorum:
+		impact.Warnings = append(impact.Warnings, fmt.Sprintf(
+			"%d of %d replicas are healthy, the volume stays offline after the target restart until %d are",
+			impact.HealthyReplicas, impact.Replicas, quorum))
+	case impact.HealthyReplicas < impact.Replicas:
+		impact.Warnings = append(impact.Warnings, fmt.Sprintf(
+			"%d of %d replicas are healthy", impact.HealthyReplicas, impact.Replicas))
+	}
+	return nil
+}
+
+// jivaReplicaImpact sets the replicas of the jiva volume, restarting the
+// controller pauses the IO and so does restarting the only replica
+func jivaReplicaImpact(impact *VolumeImpact, openebsNamespace string,
+	clientset *kubeclient.Clientset) error {
+	v := &jv.JivaVolume{}
+	err := clientset.RuntimeClient.Get(context.TODO(), client.ObjectKey{
+		Namespace: openebsNamespace,
+		Name:      impact.Name,
+	}, v)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get jivavolume %s", impact.Name)
+	}
+	impact.Replicas = v.Spec.Policy.Target.ReplicationFactor
+	if impact.Replicas < len(v.Status.ReplicaStatuses) {
+		impact.Replicas = len(v.Status.ReplicaStatuses)
+	}
+	for _, rep := range v.Status.ReplicaStatuses {
+		if rep.Mode == upgrader.JivaReplicaModeRW {
+			impact.HealthyReplicas++
+			continue
+		}
+		impact.Warnings = append(impact.Warnings,
+			fmt.Sprintf("replica %s is %s", rep.Address, upgrader.PhaseOf(rep.Mode)))
+	}
+	impact.ExpectedIOPause = targetRestartPause
+	if impact.Replicas == 1 {
+		impact.ExpectedIOPause += replicaRestartPause
+		impact.Warnings = append(impact.Warnings, "single replica volume")
+	}
+	if impact.HealthyReplicas < impact.Replicas {
+		impact.Warnings = append(impact.Warnings, fmt.Sprintf(
+			"%d of %d replicas are healthy", impact.HealthyReplicas, impact.Replicas))
+	}
+	return nil
+}
+
+// consumerImpact sets the running pods using the claim
+// and the workloads managing them
+func consumerImpact(impact *VolumeImpact, namespace, claim string,
+	clientset *kubeclient.Clientset) error {
+	pods, err := migrate.PodsUsingClaim(clientset.KubeClientset, namespace, claim)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list pods using pvc %s/%s", namespace, claim)
+	}
+	workloads := map[string]bool{}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		workload, err := workloadOf(pod, clientset)
+		if err != nil {
+			return err
+		}
+		impact.Pods = append(impact.Pods, ImpactedPod{
+			Name:     pod.Name,
+			Node:     pod.Spec.NodeName,
+			Workload: workload,
+		})
+		workloads[workload] = true
+	}
+	sort.Slice(impact.Pods, func(i, j int) bool {
+		return impact.Pods[i].Name < impact.Pods[j].Name
+	})
+	impact.Workloads = sortedKeys(workloads)
+	return nil
+}
+
+// workloadOf returns the kind/name of the workload managing the pod,
+// the replicaset of a deployment is resolved to the deployment
+func workloadOf(pod corev1.Pod, clientset *kubeclient.Clientset) (string, error) {
+	owner := metav1.GetControllerOf(&pod)
+	if owner == nil {
+		return "Pod/" + pod.Name, nil
+	}
+	if owner.Kind != "ReplicaSet" {
+		return owner.Kind + "/" + owner.Name, nil
+	}
+	rs, err := clientset.KubeClientset.AppsV1().ReplicaSets(pod.Namespace).
+		Get(context.TODO(), owner.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get replicaset %s of pod %s", owner.Name, pod.Name)
+	}
+	if deploy := metav1.GetControllerOf(rs); deploy != nil {
+		return deploy.Kind + "/" + deploy.Name, nil
+	}
+	return owner.Kind + "/" + owner.Name, nil
+}
+
+// initiatorNodes returns the nodes the volume is attached to, the
+// nodes of the pods are used if no volumeattachment is found
+func initiatorNodes(impact *VolumeImpact, clientset *kubeclient.Clientset) ([]string, error) {
+	vaList, err := clientset.KubeClientset.StorageV1().VolumeAttachments().
+		List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list volumeattachments")
+	}
+	nodes := map[string]bool{}
+	for _, va := range vaList.Items {
+		source := va.Spec.Source.PersistentVolumeName
+		if source != nil && *source == impact.Name && va.Status.Attached {
+			nodes[va.Spec.NodeName] = true
+		}
+	}
+	if len(nodes) == 0 {
+		for _, pod := range impact.Pods {
+			if pod.Node != "" {
+				nodes[pod.Node] = true
+			}
+		}
+	}
+	return sortedKeys(nodes), nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"reflect"
+	"testing"
+
+	cstor "github.com/openebs/api/v3/pkg/apis/cstor/v1"
+	openebsFakeClientset "github.com/openebs/api/v3/pkg/client/clientset/versioned/fake"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openebs/upgrade/pkg/kubeclient"
+)
+
+func newCSIPV(name, driver, pvcNamespace, pvcName string) *corev1.PersistentVolume {
+	pv := newPV(name, "cstor-sc", pvcNamespace)
+	pv.Spec.ClaimRef.Name = pvcName
+	pv.Spec.CSI = &corev1.CSIPersistentVolumeSource{Driver: driver}
+	return pv
+}
+
+func newPVCPod(name, node, claim string, phase corev1.PodPhase,
+	owner *metav1.OwnerReference) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "app",
+		},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Volumes: []corev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: claim,
+						},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+	if owner != nil {
+		pod.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+	return pod
+}
+
+func controllerRef(kind, name string) *metav1.OwnerReference {
+	controller := true
+	return &metav1.OwnerReference{Kind: kind, Name: name, Controller: &controller}
+}
+
+func newCVRWithPhase(name, pv string, phase cstor.CStorVolumeReplicaPhase) *cstor.CStorVolumeReplica {
+	cvr := newCVR(name, pv, name+"-cspi")
+	cvr.Status.Phase = phase
+	return cvr
+}
+
+func TestAnalyzeImpact(t *testing.T) {
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "web-5d8f",
+			Namespace:       "app",
+			OwnerReferences: []metav1.OwnerReference{*controllerRef("Deployment", "web")},
+		},
+	}
+	attached := "pv1"
+	va := &storagev1.VolumeAttachment{
+		ObjectMeta: metav1.ObjectMeta{Name: "csi-pv1"},
+		Spec: storagev1.VolumeAttachmentSpec{
+			NodeName: "node-a",
+			Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &attached},
+		},
+		Status: storagev1.VolumeAttachmentStatus{Attached: true},
+	}
+	cv1 := newCV("pv1", "3.4.0", nil)
+	cv1.Spec.ReplicationFactor = 3
+	cv2 := newCV("pv2", "3.4.0", nil)
+	cv2.Spec.ReplicationFactor = 1
+	cv5 := newCV("pv5", "3.4.0", nil)
+	cv5.Spec.ReplicationFactor = 3
+	openebsObjects := []runtime.Object{
+		cv1,
+		cv2,
+		newCVRWithPhase("pv1-a", "pv1", cstor.CVRStatusOnline),
+		newCVRWithPhase("pv1-b", "pv1", cstor.CVRStatusOnline),
+		newCVRWithPhase("pv1-c", "pv1", cstor.CVRStatusDegraded),
+		newCVRWithPhase("pv2-a", "pv2", cstor.CVRStatusOnline),
+		cv5,
+		newCVRWithPhase("pv5-a", "pv5", cstor.CVRStatusOnline),
+		newCVRWithPhase("pv5-b", "pv5", cstor.CVRStatusOffline),
+	}
+	kubeObjects := []runtime.Object{
+		newCSIPV("pv1", cstorCSIDriver, "app", "data-web"),
+		newCSIPV("pv2", cstorCSIDriver, "app", "data-db"),
+		newCSIPV("pv3", "ebs.csi.aws.com", "app", "data-other"),
+		newCSIPV("pv5", cstorCSIDriver, "app", "data-idle"),
+		rs,
+		va,
+		newPVCPod("web-5d8f-x", "node-a", "data-web", corev1.PodRunning,
+			controllerRef("ReplicaSet", "web-5d8f")),
+		newPVCPod("web-5d8f-y", "node-a", "data-web", corev1.PodSucceeded,
+			controllerRef("ReplicaSet", "web-5d8f")),
+		newPVCPod("db-0", "node-b", "data-db", corev1.PodRunning,
+			controllerRef("StatefulSet", "db")),
+		newPVCPod("debug", "node-c", "data-db", corev1.PodPending, nil),
+	}
+	tests := map[string]struct {
+		name    string
+		want    *VolumeImpact
+		wantErr bool
+	}{
+		"deployment with a degraded replica": {
+			name: "pv1",
+			want: &VolumeImpact{
+				Name: "pv1",
+				Kind: "cstorVolume",
+				PVC:  "app/data-web",
+				Pods: []ImpactedPod{
+					{Name: "web-5d8f-x", Node: "node-a", Workload: "Deployment/web"},
+				},
+				Workloads:       []string{"Deployment/web"},
+				InitiatorNodes:  []string{"node-a"},
+				Replicas:        3,
+				HealthyReplicas: 2,
+				ExpectedIOPause: targetRestartPause,
+				Warnings:        []string{"cvr pv1-c is Degraded", "2 of 3 replicas are healthy"},
+			},
+		},
+		"single replica without volumeattachment": {
+			name: "pv2",
+			want: &VolumeImpact{
+				Name: "pv2",
+				Kind: "cstorVolume",
+				PVC:  "app/data-db",
+				Pods: []ImpactedPod{
+					{Name: "db-0", Node: "node-b", Workload: "StatefulSet/db"},
+					{Name: "debug", Node: "node-c", Workload: "Pod/debug"},
+				},
+				Workloads:       []string{"Pod/debug", "StatefulSet/db"},
+				InitiatorNodes:  []string{"node-b", "node-c"},
+				Replicas:        1,
+				HealthyReplicas: 1,
+				ExpectedIOPause: targetRestartPause,
+				Warnings:        []string{"single replica volume"},
+			},
+		},
+		"below quorum without consumers": {
+			name: "pv5",
+			want: &VolumeImpact{
+				Name:            "pv5",
+				Kind:            "cstorVolume",
+				PVC:             "app/data-idle",
+				Workloads:       []string{},
+				InitiatorNodes:  []string{},
+				Replicas:        3,
+				HealthyReplicas: 1,
+				ExpectedIOPause: targetRestartPause,
+				Warnings: []string{
+					"cvr pv5-b is Offline",
+					"1 of 3 replicas are healthy, the volume stays offline after the target restart until 2 are",
+				},
+			},
+		},
+		"not an openebs volume": {
+			name:    "pv3",
+			wantErr: true,
+		},
+		"missing pv": {
+			name:    "pv4",
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clientset := &kubeclient.Clientset{
+				KubeClientset:    fake.NewSimpleClientset(kubeObjects...),
+				OpenebsClientset: openebsFakeClientset.NewSimpleClientset(openebsObjects...),
+			}
+			got, err := AnalyzeImpact(test.name, "openebs", clientset)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("AnalyzeImpact() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+				t.Errorf("AnalyzeImpact() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
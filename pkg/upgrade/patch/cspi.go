@@ -72,7 +72,7 @@ func (c *CSPI) PreChecks(from, to string) error {
 }
 
 // Patch ...
-func (c *CSPI) Patch(from, to string) error {
+func (c *CSPI) Patch(ctx context.Context, from, to string) error {
 	klog.Info("patching cspi ", c.Object.Name)
 	version := c.Object.Labels["openebs.io/version"]
 	if version == to {
@@ -82,7 +82,7 @@ func (c *CSPI) Patch(from, to string) error {
 	if version == from {
 		patch := c.Data
 		_, err := c.Client.CstorV1().CStorPoolInstances(c.Object.Namespace).Patch(
-			context.TODO(),
+			ctx,
 			c.Object.Name,
 			types.MergePatchType,
 			[]byte(patch),
@@ -72,7 +72,7 @@ func (c *CVC) PreChecks(from, to string) error {
 }
 
 // Patch ...
-func (c *CVC) Patch(from, to string) error {
+func (c *CVC) Patch(ctx context.Context, from, to string) error {
 	klog.Info("patching cvc ", c.Object.Name)
 	version := c.Object.VersionDetails.Desired
 	if version == to {
@@ -82,7 +82,7 @@ func (c *CVC) Patch(from, to string) error {
 	if version == from {
 		patch := c.Data
 		_, err := c.Client.CstorV1().CStorVolumeConfigs(c.Object.Namespace).Patch(
-			context.TODO(),
+			ctx,
 			c.Object.Name,
 			types.MergePatchType,
 			[]byte(patch),
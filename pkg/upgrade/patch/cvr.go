@@ -72,7 +72,7 @@ func (c *CVR) PreChecks(from, to string) error {
 }
 
 // Patch ...
-func (c *CVR) Patch(from, to string) error {
+func (c *CVR) Patch(ctx context.Context, from, to string) error {
 	klog.Info("patching cvr ", c.Object.Name)
 	version := c.Object.VersionDetails.Desired
 	if version == to {
@@ -82,7 +82,7 @@ func (c *CVR) Patch(from, to string) error {
 	if version == from {
 		patch := c.Data
 		_, err := c.Client.CstorV1().CStorVolumeReplicas(c.Object.Namespace).Patch(
-			context.TODO(),
+			ctx,
 			c.Object.Name,
 			types.MergePatchType,
 			[]byte(patch),
@@ -28,6 +28,8 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	deploymentutil "k8s.io/kubectl/pkg/util/deployment"
+
+	"github.com/openebs/upgrade/pkg/upgrade/wait"
 )
 
 // Deployment ...
@@ -74,7 +76,7 @@ func (d *Deployment) PreChecks(from, to string) error {
 }
 
 // Patch ...
-func (d *Deployment) Patch(from, to string) error {
+func (d *Deployment) Patch(ctx context.Context, from, to string) error {
 	klog.Info("patching deployment ", d.Object.Name)
 	version := d.Object.Labels["openebs.io/version"]
 	if version == to {
@@ -83,7 +85,7 @@ func (d *Deployment) Patch(from, to string) error {
 	}
 	if version == from {
 		_, err := d.Client.AppsV1().Deployments(d.Object.Namespace).Patch(
-			context.TODO(),
+			ctx,
 			d.Object.Name,
 			types.StrategicMergePatchType,
 			d.Data,
@@ -96,28 +98,28 @@ func (d *Deployment) Patch(from, to string) error {
 				d.Object.Name,
 			)
 		}
-		time.Sleep(2 * time.Second)
-		for {
-			deployObj, err1 := d.Client.AppsV1().Deployments(d.Object.Namespace).
-				Get(context.TODO(), d.Object.Name, metav1.GetOptions{})
-			if err1 != nil {
-				return err1
-			}
-			revision, err1 := deploymentutil.Revision(deployObj)
-			if err1 != nil {
-				return err1
-			}
-			statusViewer := DeploymentStatusViewer{}
-			msg, rolledOut, err1 := statusViewer.Status(deployObj, revision)
-			if err1 != nil {
-				return err1
-			}
-			klog.Info("rollout status: ", msg)
-			if !rolledOut {
-				time.Sleep(5 * time.Second)
-			} else {
-				break
-			}
+		err = wait.For(ctx, "rollout of deployment "+d.Object.Name, 5*time.Second, 0,
+			func(ctx context.Context) (bool, error) {
+				deployObj, err := d.Client.AppsV1().Deployments(d.Object.Namespace).
+					Get(ctx, d.Object.Name, metav1.GetOptions{})
+				if err != nil {
+					return false, err
+				}
+				revision, err := deploymentutil.Revision(deployObj)
+				if err != nil {
+					return false, err
+				}
+				statusViewer := DeploymentStatusViewer{}
+				msg, rolledOut, err := statusViewer.Status(deployObj, revision)
+				if err != nil {
+					return false, err
+				}
+				klog.Info("rollout status: ", msg)
+				return rolledOut, nil
+			},
+		)
+		if err != nil {
+			return err
 		}
 		klog.Infof("deployment %s patched successfully", d.Object.Name)
 	}
@@ -16,10 +16,12 @@ limitations under the License.
 
 package patch
 
+import "context"
+
 // Patcher abstracts the patching of components
 type Patcher interface {
 	PreChecks(from, to string) error
-	Patch(from, to string) error
+	Patch(ctx context.Context, from, to string) error
 	// TODO
 	// Validate() error
 }
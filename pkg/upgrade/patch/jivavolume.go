@@ -72,7 +72,7 @@ func (j *JV) PreChecks(from, to string) error {
 }
 
 // Patch ...
-func (j *JV) Patch(from, to string) error {
+func (j *JV) Patch(ctx context.Context, from, to string) error {
 	klog.Info("patching jivaVolume ", j.Object.Name)
 	version := j.Object.VersionDetails.Desired
 	if version == to {
@@ -82,7 +82,7 @@ func (j *JV) Patch(from, to string) error {
 	if version == from {
 		patch := client.MergeFrom(j.Object)
 		err := j.Client.Patch(
-			context.TODO(),
+			ctx,
 			j.NewObject,
 			patch,
 		)
@@ -98,6 +98,15 @@ func (j *JV) Patch(from, to string) error {
 	return nil
 }
 
+// Data returns the merge patch computed between the
+// current and the new jivaVolume
+func (j *JV) Data() ([]byte, error) {
+	if j.Object == nil || j.NewObject == nil {
+		return nil, errors.Errorf("nil jivaVolume object")
+	}
+	return client.MergeFrom(j.Object).Data(j.NewObject)
+}
+
 func (j *JV) Get(name, namespace string) error {
 	instance := &jv.JivaVolume{}
 	if err := j.Client.Get(context.TODO(),
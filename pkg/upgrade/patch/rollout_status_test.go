@@ -157,9 +157,9 @@ func TestStatefulSetStatusViewerStatus(t *testing.T) {
 		err        bool
 	}{
 		{
-			name:       "on delete returns an error",
+			name:       "unknown strategy returns an error",
 			generation: 1,
-			strategy:   apps.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType},
+			strategy:   apps.StatefulSetUpdateStrategy{Type: "Unknown"},
 			status: apps.StatefulSetStatus{
 				ObservedGeneration: 1,
 				Replicas:           0,
@@ -172,6 +172,38 @@ func TestStatefulSetStatusViewerStatus(t *testing.T) {
 			done: true,
 			err:  true,
 		},
+		{
+			name:       "on delete update is in progress until all pods are recreated",
+			generation: 1,
+			strategy:   apps.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType},
+			status: apps.StatefulSetStatus{
+				ObservedGeneration: 1,
+				Replicas:           3,
+				ReadyReplicas:      3,
+				CurrentReplicas:    2,
+				UpdatedReplicas:    1,
+			},
+
+			msg:  fmt.Sprintf("Waiting for %d pods to be deleted and recreated...\n", 2),
+			done: false,
+			err:  false,
+		},
+		{
+			name:       "on delete update completes when all pods are updated",
+			generation: 1,
+			strategy:   apps.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType},
+			status: apps.StatefulSetStatus{
+				ObservedGeneration: 1,
+				Replicas:           3,
+				ReadyReplicas:      3,
+				CurrentReplicas:    0,
+				UpdatedReplicas:    3,
+			},
+
+			msg:  fmt.Sprintf("on delete roll out complete: %d new pods have been updated...\n", 3),
+			done: true,
+			err:  false,
+		},
 		{
 			name:       "unobserved update is not complete",
 			generation: 2,
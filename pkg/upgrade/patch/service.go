@@ -73,7 +73,7 @@ func (s *Service) PreChecks(from, to string) error {
 }
 
 // Patch ...
-func (s *Service) Patch(from, to string) error {
+func (s *Service) Patch(ctx context.Context, from, to string) error {
 	klog.Info("Patching service ", s.Object.Name)
 	version := s.Object.Labels["openebs.io/version"]
 	if version == to {
@@ -83,7 +83,7 @@ func (s *Service) Patch(from, to string) error {
 	if version == from {
 		patch := s.Data
 		_, err := s.Client.CoreV1().Services(s.Object.Namespace).Patch(
-			context.TODO(),
+			ctx,
 			s.Object.Name,
 			types.StrategicMergePatchType,
 			[]byte(patch),
@@ -18,15 +18,35 @@ package patch
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
+
+	"github.com/openebs/upgrade/pkg/upgrade/wait"
+)
+
+const (
+	// partitionAnnotation records the partition of the statefulset
+	// before the upgrade while its pods are updated one at a time
+	partitionAnnotation = "openebs.io/upgrade-partition"
+	// revisionLabel is the revision of the statefulset a pod runs
+	revisionLabel = "controller-revision-hash"
+)
+
+var (
+	// rolloutInterval is the interval between two
+	// checks of the rollout of the statefulset pods
+	rolloutInterval = 5 * time.Second
 )
 
 // StatefulSet ...
@@ -34,6 +54,9 @@ type StatefulSet struct {
 	Object *appsv1.StatefulSet
 	Data   []byte
 	Client kubernetes.Interface
+	// PodUpdated is called after each pod is updated and ready,
+	// the next pod is updated once it returns without error
+	PodUpdated func(ctx context.Context, ordinal int32) error
 }
 
 // StatefulSetOptions ...
@@ -55,6 +78,14 @@ func WithStatefulSetClient(c kubernetes.Interface) StatefulSetOptions {
 	}
 }
 
+// WithStatefulSetPodUpdated sets the function called
+// after each pod of the statefulset is updated
+func WithStatefulSetPodUpdated(f func(ctx context.Context, ordinal int32) error) StatefulSetOptions {
+	return func(obj *StatefulSet) {
+		obj.PodUpdated = f
+	}
+}
+
 // PreChecks ...
 func (s *StatefulSet) PreChecks(from, to string) error {
 	if s.Object == nil {
@@ -72,17 +103,29 @@ func (s *StatefulSet) PreChecks(from, to string) error {
 	return nil
 }
 
-// Patch ...
-func (s *StatefulSet) Patch(from, to string) error {
+// Patch updates the statefulset and its pods one at a time, from the
+// highest ordinal to the lowest. With the RollingUpdate strategy the
+// partition is stepped down one ordinal at a time, with the OnDelete
+// strategy the pods are deleted one at a time. A rollout interrupted
+// by a previous attempt is resumed.
+func (s *StatefulSet) Patch(ctx context.Context, from, to string) error {
 	klog.Info("patching statefulset ", s.Object.Name)
 	version := s.Object.Labels["openebs.io/version"]
-	if version == to {
+	if version == to && !s.rolloutPending() {
 		klog.Infof("statefulset already in %s version", to)
 		return nil
 	}
 	if version == from {
+		onDelete := s.Object.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType
+		if !onDelete {
+			// hold back all the pods before the new template is applied
+			err := s.pause(ctx)
+			if err != nil {
+				return err
+			}
+		}
 		_, err := s.Client.AppsV1().StatefulSets(s.Object.Namespace).Patch(
-			context.TODO(),
+			ctx,
 			s.Object.Name,
 			types.StrategicMergePatchType,
 			s.Data,
@@ -95,27 +138,226 @@ func (s *StatefulSet) Patch(from, to string) error {
 				s.Object.Name,
 			)
 		}
-		for {
-			stsObj, err1 := s.Client.AppsV1().StatefulSets(s.Object.Namespace).
-				Get(context.TODO(), s.Object.Name, metav1.GetOptions{})
+	} else if version != to {
+		return nil
+	}
+	err := s.rollout(ctx)
+	if err != nil {
+		return err
+	}
+	klog.Infof("statefulset %s patched successfully", s.Object.Name)
+	return nil
+}
+
+// rolloutPending returns true if the pods of the statefulset
+// may not all be updated by a previous attempt
+func (s *StatefulSet) rolloutPending() bool {
+	if s.Object.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		return true
+	}
+	_, ok := s.Object.Annotations[partitionAnnotation]
+	return ok
+}
+
+// pause records the current partition of the statefulset
+// and raises it so that none of the pods are updated
+func (s *StatefulSet) pause(ctx context.Context) error {
+	replicas := specReplicas(s.Object)
+	original := strconv.Itoa(int(partitionOf(s.Object)))
+	// keep the partition recorded by an interrupted attempt
+	if value, ok := s.Object.Annotations[partitionAnnotation]; ok {
+		original = value
+	}
+	data := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}},`+
+		`"spec":{"updateStrategy":{"rollingUpdate":{"partition":%d}}}}`,
+		partitionAnnotation, original, replicas)
+	_, err := s.Client.AppsV1().StatefulSets(s.Object.Namespace).Patch(
+		ctx, s.Object.Name, types.MergePatchType, []byte(data), metav1.PatchOptions{},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "failed to pause rollout of statefulset %s", s.Object.Name)
+	}
+	return nil
+}
+
+// rollout updates the pods of the statefulset one at a time
+func (s *StatefulSet) rollout(ctx context.Context) error {
+	stsObj, err := s.Client.AppsV1().StatefulSets(s.Object.Namespace).
+		Get(ctx, s.Object.Name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get statefulset %s", s.Object.Name)
+	}
+	if stsObj.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		return s.rolloutOnDelete(ctx, stsObj)
+	}
+	return s.rolloutPartitions(ctx, stsObj)
+}
+
+// rolloutPartitions steps the partition down one ordinal at a
+// time until the partition recorded before the upgrade is reached
+func (s *StatefulSet) rolloutPartitions(ctx context.Context, stsObj *appsv1.StatefulSet) error {
+	original := int32(0)
+	if value, ok := stsObj.Annotations[partitionAnnotation]; ok {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s annotation on statefulset %s",
+				partitionAnnotation, stsObj.Name)
+		}
+		original = int32(n)
+	}
+	for _, partition := range partitionSteps(partitionOf(stsObj), original, specReplicas(stsObj)) {
+		klog.Infof("updating pod %s-%d of statefulset %s", stsObj.Name, partition, stsObj.Name)
+		data := fmt.Sprintf(`{"spec":{"updateStrategy":{"rollingUpdate":{"partition":%d}}}}`, partition)
+		_, err := s.Client.AppsV1().StatefulSets(stsObj.Namespace).Patch(
+			ctx, stsObj.Name, types.MergePatchType, []byte(data), metav1.PatchOptions{},
+		)
+		if err != nil {
+			return errors.Wrapf(err, "failed to set partition of statefulset %s", stsObj.Name)
+		}
+		err = s.waitForRollout(ctx)
+		if err != nil {
+			return err
+		}
+		err = s.podUpdated(ctx, partition)
+		if err != nil {
+			return err
+		}
+	}
+	if _, ok := stsObj.Annotations[partitionAnnotation]; ok {
+		data := fmt.Sprintf(`{"metadata":{"annotations":{%q:null}}}`, partitionAnnotation)
+		_, err := s.Client.AppsV1().StatefulSets(stsObj.Namespace).Patch(
+			ctx, stsObj.Name, types.MergePatchType, []byte(data), metav1.PatchOptions{},
+		)
+		if err != nil {
+			return errors.Wrapf(err, "failed to resume rollout of statefulset %s", stsObj.Name)
+		}
+	}
+	return s.waitForRollout(ctx)
+}
+
+// partitionSteps returns the partitions releasing one pod at a time,
+// from the current partition down to the original one. The current
+// partition is repeated when it already releases a pod as the pod
+// may not have been verified by an interrupted attempt.
+func partitionSteps(current, original, replicas int32) []int32 {
+	if current > replicas-1 {
+		current = replicas - 1
+	}
+	steps := []int32{}
+	for partition := current; partition >= original; partition-- {
+		steps = append(steps, partition)
+	}
+	return steps
+}
+
+// rolloutOnDelete deletes the pods running an old revision one
+// at a time, waiting for each of them to be recreated and ready
+func (s *StatefulSet) rolloutOnDelete(ctx context.Context, stsObj *appsv1.StatefulSet) error {
+	// wait for the new template to be observed
+	// so that the update revision is current
+	err := wait.For(ctx, "observed generation of statefulset "+stsObj.Name, rolloutInterval, 0,
+		func(ctx context.Context) (bool, error) {
+			obj, err := s.Client.AppsV1().StatefulSets(stsObj.Namespace).
+				Get(ctx, stsObj.Name, metav1.GetOptions{})
 			if err != nil {
-				return err1
+				return false, err
+			}
+			stsObj = obj
+			return obj.Status.ObservedGeneration >= obj.Generation, nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+	for ordinal := specReplicas(stsObj) - 1; ordinal >= 0; ordinal-- {
+		name := fmt.Sprintf("%s-%d", stsObj.Name, ordinal)
+		pod, err := s.Client.CoreV1().Pods(stsObj.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get pod %s", name)
+		}
+		if err == nil && pod.Labels[revisionLabel] == stsObj.Status.UpdateRevision {
+			continue
+		}
+		if err == nil {
+			klog.Infof("deleting pod %s to update it to revision %s", name, stsObj.Status.UpdateRevision)
+			err = s.Client.CoreV1().Pods(stsObj.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to delete pod %s", name)
+			}
+		}
+		err = wait.For(ctx, "update of pod "+name, rolloutInterval, 0,
+			func(ctx context.Context) (bool, error) {
+				pod, err := s.Client.CoreV1().Pods(stsObj.Namespace).Get(ctx, name, metav1.GetOptions{})
+				if k8serrors.IsNotFound(err) {
+					return false, nil
+				}
+				if err != nil {
+					return false, err
+				}
+				return pod.Labels[revisionLabel] == stsObj.Status.UpdateRevision && isPodReady(pod), nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+		err = s.podUpdated(ctx, ordinal)
+		if err != nil {
+			return err
+		}
+	}
+	return s.waitForRollout(ctx)
+}
+
+func (s *StatefulSet) podUpdated(ctx context.Context, ordinal int32) error {
+	if s.PodUpdated == nil {
+		return nil
+	}
+	return s.PodUpdated(ctx, ordinal)
+}
+
+// waitForRollout waits until the pods released
+// by the current partition are updated and ready
+func (s *StatefulSet) waitForRollout(ctx context.Context) error {
+	return wait.For(ctx, "rollout of statefulset "+s.Object.Name, rolloutInterval, 0,
+		func(ctx context.Context) (bool, error) {
+			stsObj, err := s.Client.AppsV1().StatefulSets(s.Object.Namespace).
+				Get(ctx, s.Object.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
 			}
 			statusViewer := StatefulSetStatusViewer{}
-			msg, rolledOut, err1 := statusViewer.Status(stsObj)
-			if err1 != nil {
-				return err1
+			msg, rolledOut, err := statusViewer.Status(stsObj)
+			if err != nil {
+				return false, err
 			}
 			klog.Info("rollout status: ", msg)
-			if !rolledOut {
-				time.Sleep(5 * time.Second)
-			} else {
-				break
-			}
+			return rolledOut, nil
+		},
+	)
+}
+
+func specReplicas(sts *appsv1.StatefulSet) int32 {
+	if sts.Spec.Replicas == nil {
+		return 1
+	}
+	return *sts.Spec.Replicas
+}
+
+func partitionOf(sts *appsv1.StatefulSet) int32 {
+	if sts.Spec.UpdateStrategy.RollingUpdate == nil ||
+		sts.Spec.UpdateStrategy.RollingUpdate.Partition == nil {
+		return 0
+	}
+	return *sts.Spec.UpdateStrategy.RollingUpdate.Partition
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
 		}
-		klog.Infof("statefulset %s patched successfully", s.Object.Name)
 	}
-	return nil
+	return false
 }
 
 // Get ...
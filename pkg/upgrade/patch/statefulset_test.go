@@ -0,0 +1,193 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	api "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func Test_partitionSteps(t *testing.T) {
+	tests := map[string]struct {
+		current, original, replicas int32
+		want                        []int32
+	}{
+		"paused":            {3, 0, 3, []int32{2, 1, 0}},
+		"resumed":           {1, 0, 3, []int32{1, 0}},
+		"original kept":     {3, 1, 3, []int32{2, 1}},
+		"already completed": {0, 0, 3, []int32{0}},
+		"single replica":    {1, 0, 1, []int32{0}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := partitionSteps(test.current, test.original, test.replicas)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("partitionSteps() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// rolledOutStatefulSet returns a statefulset whose
+// status reports all its pods updated and ready
+func rolledOutStatefulSet(strategy apps.StatefulSetUpdateStrategyType, version string) *apps.StatefulSet {
+	sts := newStatefulSet(3)
+	sts.Labels["openebs.io/version"] = version
+	sts.Generation = 1
+	sts.Spec.UpdateStrategy.Type = strategy
+	sts.Status = apps.StatefulSetStatus{
+		ObservedGeneration: 1,
+		Replicas:           3,
+		ReadyReplicas:      3,
+		UpdatedReplicas:    3,
+		UpdateRevision:     "rev-2",
+	}
+	return sts
+}
+
+func TestStatefulSetPatch(t *testing.T) {
+	defer func(interval time.Duration) {
+		rolloutInterval = interval
+	}(rolloutInterval)
+	rolloutInterval = time.Millisecond
+	partition := func(n int32) *apps.RollingUpdateStatefulSetStrategy {
+		return &apps.RollingUpdateStatefulSetStrategy{Partition: &n}
+	}
+	tests := map[string]struct {
+		sts          *apps.StatefulSet
+		wantOrdinals []int32
+	}{
+		"rolling update one pod at a time": {
+			sts:          rolledOutStatefulSet(apps.RollingUpdateStatefulSetStrategyType, "3.4.0"),
+			wantOrdinals: []int32{2, 1, 0},
+		},
+		"interrupted rolling update is resumed": {
+			sts: func() *apps.StatefulSet {
+				sts := rolledOutStatefulSet(apps.RollingUpdateStatefulSetStrategyType, "3.5.0")
+				sts.Annotations = map[string]string{partitionAnnotation: "0"}
+				sts.Spec.UpdateStrategy.RollingUpdate = partition(1)
+				return sts
+			}(),
+			wantOrdinals: []int32{1, 0},
+		},
+		"completed rolling update is skipped": {
+			sts:          rolledOutStatefulSet(apps.RollingUpdateStatefulSetStrategyType, "3.5.0"),
+			wantOrdinals: []int32{},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(test.sts)
+			ordinals := []int32{}
+			s := NewStatefulSet(
+				WithStatefulSetClient(client),
+				WithStatefulSetPodUpdated(func(ctx context.Context, ordinal int32) error {
+					ordinals = append(ordinals, ordinal)
+					return nil
+				}),
+			)
+			s.Object = test.sts
+			s.Data = []byte(`{"metadata":{"labels":{"openebs.io/version":"3.5.0"}}}`)
+			err := s.Patch(context.TODO(), "3.4.0", "3.5.0")
+			if err != nil {
+				t.Fatalf("Patch() error = %v", err)
+			}
+			if !reflect.DeepEqual(ordinals, test.wantOrdinals) {
+				t.Errorf("Patch() updated pods %v, want %v", ordinals, test.wantOrdinals)
+			}
+			got, err := client.AppsV1().StatefulSets(test.sts.Namespace).
+				Get(context.TODO(), test.sts.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get statefulset: %v", err)
+			}
+			if got.Labels["openebs.io/version"] != "3.5.0" {
+				t.Errorf("Patch() version = %q, want 3.5.0", got.Labels["openebs.io/version"])
+			}
+			if _, ok := got.Annotations[partitionAnnotation]; ok {
+				t.Errorf("Patch() left the %s annotation", partitionAnnotation)
+			}
+			if p := partitionOf(got); p != 0 {
+				t.Errorf("Patch() partition = %d, want 0", p)
+			}
+		})
+	}
+}
+
+func TestStatefulSetPatchOnDelete(t *testing.T) {
+	defer func(interval time.Duration) {
+		rolloutInterval = interval
+	}(rolloutInterval)
+	rolloutInterval = time.Millisecond
+	sts := rolledOutStatefulSet(apps.OnDeleteStatefulSetStrategyType, "3.4.0")
+	pod := func(name, revision string) *api.Pod {
+		return &api.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: sts.Namespace,
+				Labels:    map[string]string{revisionLabel: revision},
+			},
+			Status: api.PodStatus{
+				Conditions: []api.PodCondition{
+					{Type: api.PodReady, Status: api.ConditionTrue},
+				},
+			},
+		}
+	}
+	client := fake.NewSimpleClientset(sts,
+		pod("foo-0", "rev-1"), pod("foo-1", "rev-2"), pod("foo-2", "rev-1"))
+	// recreate the deleted pods with the new revision
+	// like the statefulset controller
+	deleted := []string{}
+	client.PrependReactor("delete", "pods",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			name := action.(k8stesting.DeleteAction).GetName()
+			deleted = append(deleted, name)
+			err := client.Tracker().Update(api.SchemeGroupVersion.WithResource("pods"),
+				pod(name, "rev-2"), sts.Namespace)
+			return true, nil, err
+		},
+	)
+	ordinals := []int32{}
+	s := NewStatefulSet(
+		WithStatefulSetClient(client),
+		WithStatefulSetPodUpdated(func(ctx context.Context, ordinal int32) error {
+			ordinals = append(ordinals, ordinal)
+			return nil
+		}),
+	)
+	s.Object = sts
+	s.Data = []byte(`{"metadata":{"labels":{"openebs.io/version":"3.5.0"}}}`)
+	err := s.Patch(context.TODO(), "3.4.0", "3.5.0")
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+	if want := []string{"foo-2", "foo-0"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("Patch() deleted pods %v, want %v", deleted, want)
+	}
+	if want := []int32{2, 0}; !reflect.DeepEqual(ordinals, want) {
+		t.Errorf("Patch() updated pods %v, want %v", ordinals, want)
+	}
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/openebs/api/v3/pkg/apis/types"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openebs/upgrade/pkg/kubeclient"
+)
+
+// ScheduledVolume is a volume to be upgraded along with the locks
+// it needs to hold while the upgrade is in progress
+type ScheduledVolume struct {
+	Name  string
+	Locks []string
+}
+
+// VolumeLocks returns the keys of the resources disrupted while
+// upgrading the given volume. These are the node running the target
+// and for cstor volumes the pool instances hosting the replicas which
+// are rolling, so that two volumes are not upgraded while a pool they
+// share is restarted. Volumes sharing healthy pool instances are not
+// serialized.
+func VolumeLocks(kind, name, openebsNamespace string, rolling map[string]bool,
+	clientset *kubeclient.Clientset) ([]string, error) {
+	pvLabel := types.PersistentVolumeLabelKey + "=" + name
+	locks := []string{}
+	switch kind {
+	case "cstorVolume":
+		nodes, err := targetNodes(pvLabel, openebsNamespace, clientset)
+		if err != nil {
+			return nil, err
+		}
+		locks = append(locks, nodes...)
+		cvrList, err := clientset.OpenebsClientset.CstorV1().CStorVolumeReplicas(openebsNamespace).
+			List(context.TODO(), metav1.ListOptions{
+				LabelSelector: pvLabel,
+			})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list cvrs for volume %s", name)
+		}
+		for _, cvr := range cvrList.Items {
+			if cspi := cvr.Labels[types.CStorPoolInstanceNameLabelKey]; rolling[cspi] {
+				locks = append(locks, "cspi/"+cspi)
+			}
+		}
+	case "jivaVolume":
+		nodes, err := targetNodes("openebs.io/component=jiva-controller,"+pvLabel,
+			openebsNamespace, clientset)
+		if err != nil {
+			return nil, err
+		}
+		locks = append(locks, nodes...)
+	default:
+		return nil, errors.Errorf("parallel upgrade is not supported for %s", kind)
+	}
+	sort.Strings(locks)
+	return locks, nil
+}
+
+// RollingCSPIs returns the pool instances being upgraded or restarted:
+// their version is not reconciled yet or their pool pod is not ready
+func RollingCSPIs(openebsNamespace string, clientset *kubeclient.Clientset) (map[string]bool, error) {
+	cspiList, err := clientset.OpenebsClientset.CstorV1().CStorPoolInstances(openebsNamespace).
+		List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cspis")
+	}
+	podList, err := clientset.KubeClientset.CoreV1().Pods(openebsNamespace).
+		List(context.TODO(), metav1.ListOptions{
+			LabelSelector: types.CStorPoolInstanceLabelKey,
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cstor pool pods")
+	}
+	ready := map[string]bool{}
+	for _, pod := range podList.Items {
+		if isPodReady(pod) {
+			ready[pod.Labels[types.CStorPoolInstanceLabelKey]] = true
+		}
+	}
+	rolling := map[string]bool{}
+	for _, cspi := range cspiList.Items {
+		if cspi.VersionDetails.Status.Current != cspi.VersionDetails.Desired || !ready[cspi.Name] {
+			rolling[cspi.Name] = true
+		}
+	}
+	return rolling, nil
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// targetNodes returns the nodes running the pods of the target
+// deployment matching the given label
+func targetNodes(label, namespace string, clientset *kubeclient.Clientset) ([]string, error) {
+	deployList, err := clientset.KubeClientset.AppsV1().Deployments(namespace).
+		List(context.TODO(), metav1.ListOptions{
+			LabelSelector: label,
+		})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list target deployment with label %s", label)
+	}
+	nodes := []string{}
+	for _, d := range deployList.Items {
+		selector, err := metav1.LabelSelectorAsSelector(d.Spec.Selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid selector on deployment %s", d.Name)
+		}
+		podList, err := clientset.KubeClientset.CoreV1().Pods(namespace).
+			List(context.TODO(), metav1.ListOptions{
+				LabelSelector: selector.String(),
+			})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list pods for deployment %s", d.Name)
+		}
+		for _, pod := range podList.Items {
+			if pod.Spec.NodeName != "" {
+				nodes = append(nodes, "node/"+pod.Spec.NodeName)
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// Schedule runs upgrade for all the volumes with at most parallelism
+// upgrades in progress at a time. A volume is started only when none
+// of its locks are held by a running upgrade, volumes are picked in
+// the given order otherwise. The locks of a volume are recomputed with
+// locksOf, if set, when it is picked as the pools rolling and the nodes
+// of the targets change while the volumes are upgraded. The errors are
+// returned in the same order as the volumes.
+func Schedule(volumes []ScheduledVolume, parallelism int,
+	locksOf func(name string) ([]string, error),
+	upgrade func(name string) error) []error {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	// the locks are updated on a copy of the volumes
+	volumes = append([]ScheduledVolume{}, volumes...)
+	errs := make([]error, len(volumes))
+	started := make([]bool, len(volumes))
+	held := map[string]bool{}
+	running, pending := 0, len(volumes)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := sync.NewCond(&mu)
+
+	mu.Lock()
+	for pending > 0 {
+		i := nextVolume(volumes, started, held)
+		if running == parallelism || i == -1 {
+			// wait for a running upgrade to release its locks
+			done.Wait()
+			continue
+		}
+		if locksOf != nil {
+			// the running upgrades only release locks
+			// while the locks of the volume are read
+			mu.Unlock()
+			locks, err := locksOf(volumes[i].Name)
+			mu.Lock()
+			if err != nil {
+				errs[i] = err
+				started[i] = true
+				pending--
+				continue
+			}
+			volumes[i].Locks = locks
+			if !locksFree(locks, held) {
+				continue
+			}
+		}
+		started[i] = true
+		pending--
+		running++
+		for _, l := range volumes[i].Locks {
+			held[l] = true
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := upgrade(volumes[i].Name)
+			mu.Lock()
+			defer mu.Unlock()
+			errs[i] = err
+			for _, l := range volumes[i].Locks {
+				delete(held, l)
+			}
+			running--
+			done.Signal()
+		}(i)
+	}
+	mu.Unlock()
+	wg.Wait()
+	return errs
+}
+
+// nextVolume returns the index of the first volume not yet started
+// whose locks are all free, or -1 if there is none
+func nextVolume(volumes []ScheduledVolume, started []bool, held map[string]bool) int {
+	for i, v := range volumes {
+		if !started[i] && locksFree(v.Locks, held) {
+			return i
+		}
+	}
+	return -1
+}
+
+func locksFree(locks []string, held map[string]bool) bool {
+	for _, l := range locks {
+		if held[l] {
+			return false
+		}
+	}
+	return true
+}
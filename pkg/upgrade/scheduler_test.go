@@ -0,0 +1,293 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	cstor "github.com/openebs/api/v3/pkg/apis/cstor/v1"
+	"github.com/openebs/api/v3/pkg/apis/types"
+	openebsFakeClientset "github.com/openebs/api/v3/pkg/client/clientset/versioned/fake"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openebs/upgrade/pkg/kubeclient"
+)
+
+func newTarget(pv, node string) []runtime.Object {
+	labels := map[string]string{"openebs.io/persistent-volume": pv}
+	podLabels := map[string]string{"app": "cstor-volume-manager", "pv": pv}
+	return []runtime.Object{
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pv + "-target",
+				Namespace: "openebs",
+				Labels:    labels,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pv + "-target-pod",
+				Namespace: "openebs",
+				Labels:    podLabels,
+			},
+			Spec: corev1.PodSpec{NodeName: node},
+		},
+	}
+}
+
+func TestVolumeLocks(t *testing.T) {
+	kubeObjects := append(newTarget("pv1", "node-1"), newTarget("pv2", "")...)
+	openebsObjects := []runtime.Object{
+		newCVR("pv1-cspi-a", "pv1", "cspi-a"),
+		newCVR("pv1-cspi-b", "pv1", "cspi-b"),
+		newCVR("pv2-cspi-b", "pv2", "cspi-b"),
+	}
+	clientset := &kubeclient.Clientset{
+		KubeClientset:    fake.NewSimpleClientset(kubeObjects...),
+		OpenebsClientset: openebsFakeClientset.NewSimpleClientset(openebsObjects...),
+	}
+	tests := []struct {
+		name    string
+		kind    string
+		volume  string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "target node and rolling pools",
+			kind:   "cstorVolume",
+			volume: "pv1",
+			want:   []string{"cspi/cspi-b", "node/node-1"},
+		},
+		{
+			name:   "unscheduled target",
+			kind:   "cstorVolume",
+			volume: "pv2",
+			want:   []string{"cspi/cspi-b"},
+		},
+		{
+			name:    "unsupported kind",
+			kind:    "cstorCSPC",
+			volume:  "pv1",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := VolumeLocks(test.kind, test.volume, "openebs",
+				map[string]bool{"cspi-b": true}, clientset)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("VolumeLocks() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+				t.Errorf("VolumeLocks() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func newPoolPod(cspi string, ready corev1.ConditionStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cspi + "-pod",
+			Namespace: "openebs",
+			Labels:    map[string]string{types.CStorPoolInstanceLabelKey: cspi},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: ready},
+			},
+		},
+	}
+}
+
+func newCSPIWithVersion(name, current, desired string) *cstor.CStorPoolInstance {
+	cspi := newCSPI(name, "cspc-a")
+	cspi.VersionDetails.Status.Current = current
+	cspi.VersionDetails.Desired = desired
+	return cspi
+}
+
+func TestRollingCSPIs(t *testing.T) {
+	clientset := &kubeclient.Clientset{
+		KubeClientset: fake.NewSimpleClientset(
+			newPoolPod("cspi-a", corev1.ConditionTrue),
+			newPoolPod("cspi-b", corev1.ConditionTrue),
+			newPoolPod("cspi-c", corev1.ConditionFalse),
+		),
+		OpenebsClientset: openebsFakeClientset.NewSimpleClientset(
+			newCSPIWithVersion("cspi-a", "3.5.0", "3.5.0"),
+			newCSPIWithVersion("cspi-b", "3.4.0", "3.5.0"),
+			newCSPIWithVersion("cspi-c", "3.5.0", "3.5.0"),
+			newCSPIWithVersion("cspi-d", "3.5.0", "3.5.0"),
+		),
+	}
+	got, err := RollingCSPIs("openebs", clientset)
+	if err != nil {
+		t.Fatalf("RollingCSPIs() error = %v", err)
+	}
+	want := map[string]bool{"cspi-b": true, "cspi-c": true, "cspi-d": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RollingCSPIs() = %v, want %v", got, want)
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	tests := []struct {
+		name        string
+		volumes     []ScheduledVolume
+		parallelism int
+		wantMax     int
+	}{
+		{
+			name: "sequential",
+			volumes: []ScheduledVolume{
+				{Name: "pv1"}, {Name: "pv2"}, {Name: "pv3"},
+			},
+			parallelism: 1,
+			wantMax:     1,
+		},
+		{
+			name: "bounded by parallelism",
+			volumes: []ScheduledVolume{
+				{Name: "pv1"}, {Name: "pv2"}, {Name: "pv3"}, {Name: "pv4"},
+			},
+			parallelism: 2,
+			wantMax:     2,
+		},
+		{
+			name: "serialized by shared locks",
+			volumes: []ScheduledVolume{
+				{Name: "pv1", Locks: []string{"node/node-1", "cspi/cspi-a"}},
+				{Name: "pv2", Locks: []string{"node/node-2", "cspi/cspi-a"}},
+				{Name: "pv3", Locks: []string{"node/node-1", "cspi/cspi-b"}},
+			},
+			parallelism: 3,
+			wantMax:     2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var mu sync.Mutex
+			held := map[string]string{}
+			running, maxRunning := 0, 0
+			locks := map[string][]string{}
+			for _, v := range test.volumes {
+				locks[v.Name] = v.Locks
+			}
+			errs := Schedule(test.volumes, test.parallelism, nil, func(name string) error {
+				mu.Lock()
+				for _, l := range locks[name] {
+					if held[l] != "" {
+						mu.Unlock()
+						return fmt.Errorf("%s held by %s", l, held[l])
+					}
+					held[l] = name
+				}
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mu.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				mu.Lock()
+				for _, l := range locks[name] {
+					delete(held, l)
+				}
+				running--
+				mu.Unlock()
+				if name == "pv2" {
+					return fmt.Errorf("failed")
+				}
+				return nil
+			})
+			for i, v := range test.volumes {
+				if (errs[i] != nil) != (v.Name == "pv2") {
+					t.Errorf("Schedule() error for %s = %v", v.Name, errs[i])
+				}
+			}
+			if maxRunning != test.wantMax {
+				t.Errorf("Schedule() ran %d upgrades at once, want %d", maxRunning, test.wantMax)
+			}
+		})
+	}
+}
+
+func TestScheduleRecomputesLocks(t *testing.T) {
+	// the locks given when queued are stale, pv2 shares a
+	// pool with pv1 which started rolling since then
+	volumes := []ScheduledVolume{
+		{Name: "pv1"}, {Name: "pv2"}, {Name: "pv3"}, {Name: "pv4"},
+	}
+	current := map[string][]string{
+		"pv1": {"cspi/cspi-a"},
+		"pv2": {"cspi/cspi-a"},
+		"pv3": {"node/node-1"},
+	}
+	var mu sync.Mutex
+	held := map[string]string{}
+	ran := map[string]bool{}
+	errs := Schedule(volumes, 4,
+		func(name string) ([]string, error) {
+			if name == "pv4" {
+				return nil, fmt.Errorf("failed to list cvrs")
+			}
+			return current[name], nil
+		},
+		func(name string) error {
+			mu.Lock()
+			ran[name] = true
+			for _, l := range current[name] {
+				if held[l] != "" {
+					mu.Unlock()
+					return fmt.Errorf("%s held by %s", l, held[l])
+				}
+				held[l] = name
+			}
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			for _, l := range current[name] {
+				delete(held, l)
+			}
+			mu.Unlock()
+			return nil
+		},
+	)
+	for i, v := range volumes {
+		if (errs[i] != nil) != (v.Name == "pv4") {
+			t.Errorf("Schedule() error for %s = %v", v.Name, errs[i])
+		}
+	}
+	if ran["pv4"] {
+		t.Errorf("Schedule() upgraded pv4 whose locks could not be read")
+	}
+	if !reflect.DeepEqual(volumes[1].Locks, []string(nil)) {
+		t.Errorf("Schedule() changed the locks of the given volumes")
+	}
+}
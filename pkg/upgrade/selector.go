@@ -0,0 +1,249 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"context"
+	"sort"
+
+	"github.com/openebs/api/v3/pkg/apis/types"
+	jv "github.com/openebs/jiva-operator/pkg/apis/openebs/v1"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openebs/upgrade/pkg/kubeclient"
+)
+
+const (
+	// SkipUpgradeAnnotation excludes a volume from the bulk upgrade
+	// when set to true
+	SkipUpgradeAnnotation = "openebs.io/skip-upgrade"
+)
+
+// VolumeSelector holds the criteria used to select
+// the volumes to be upgraded
+type VolumeSelector struct {
+	All           bool
+	CSPC          string
+	StorageClass  string
+	PVCNamespace  string
+	LabelSelector string
+}
+
+// IsEmpty returns true if no criteria is set
+func (s VolumeSelector) IsEmpty() bool {
+	return !s.All && s.CSPC == "" && s.StorageClass == "" &&
+		s.PVCNamespace == "" && s.LabelSelector == ""
+}
+
+// SelectedVolume is a volume matching the selector, volumes
+// which need not be upgraded have the SkipReason set
+type SelectedVolume struct {
+	Name       string
+	Version    string
+	SkipReason string
+}
+
+// volumeInfo is the version details common to cstor and jiva volumes
+type volumeInfo struct {
+	name        string
+	version     string
+	annotations map[string]string
+}
+
+// SelectVolumes returns the volumes of the given kind matching the selector
+func SelectVolumes(kind, openebsNamespace, toVersion string, s VolumeSelector,
+	clientset *kubeclient.Clientset) ([]SelectedVolume, error) {
+	if s.IsEmpty() {
+		return nil, errors.Errorf("no volume selector provided")
+	}
+	var volumes []volumeInfo
+	var err error
+	switch kind {
+	case "cstorVolume":
+		volumes, err = listCStorVolumes(openebsNamespace, s, clientset)
+	case "jivaVolume":
+		if s.CSPC != "" {
+			return nil, errors.Errorf("cspc selector is not supported for jiva volumes")
+		}
+		volumes, err = listJivaVolumes(openebsNamespace, s, clientset)
+	default:
+		return nil, errors.Errorf("volume selector is not supported for %s", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	selected := []SelectedVolume{}
+	for _, v := range volumes {
+		ok, err := matchPV(v.name, s, clientset)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		sv := SelectedVolume{
+			Name:    v.name,
+			Version: v.version,
+		}
+		switch {
+		case v.annotations[SkipUpgradeAnnotation] == "true":
+			sv.SkipReason = "annotated with " + SkipUpgradeAnnotation
+		case v.version == toVersion:
+			sv.SkipReason = "already in " + toVersion + " version"
+		}
+		selected = append(selected, sv)
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Name < selected[j].Name
+	})
+	return selected, nil
+}
+
+func listCStorVolumes(openebsNamespace string, s VolumeSelector,
+	clientset *kubeclient.Clientset) ([]volumeInfo, error) {
+	cvList, err := clientset.OpenebsClientset.CstorV1().CStorVolumes(openebsNamespace).
+		List(context.TODO(), metav1.ListOptions{
+			LabelSelector: s.LabelSelector,
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cstorvolumes")
+	}
+	var onCSPC map[string]bool
+	if s.CSPC != "" {
+		onCSPC, err = volumesOnCSPC(openebsNamespace, s.CSPC, clientset)
+		if err != nil {
+			return nil, err
+		}
+	}
+	versions, err := cvcVersions(openebsNamespace, clientset)
+	if err != nil {
+		return nil, err
+	}
+	volumes := []volumeInfo{}
+	for _, cv := range cvList.Items {
+		if onCSPC != nil && !onCSPC[cv.Name] {
+			continue
+		}
+		volumes = append(volumes, volumeInfo{
+			name:        cv.Name,
+			version:     versions[cv.Name],
+			annotations: cv.Annotations,
+		})
+	}
+	return volumes, nil
+}
+
+// cvcVersions returns the versions of the CVCs by name, the CVC is
+// patched last so a volume whose upgrade failed after its CV was
+// reconciled is not taken as upgraded, like in CurrentVersion
+func cvcVersions(openebsNamespace string,
+	clientset *kubeclient.Clientset) (map[string]string, error) {
+	cvcList, err := clientset.OpenebsClientset.CstorV1().CStorVolumeConfigs(openebsNamespace).
+		List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cstorvolumeconfigs")
+	}
+	versions := map[string]string{}
+	for _, cvc := range cvcList.Items {
+		version := cvc.VersionDetails.Status.Current
+		if version == "" {
+			version = cvc.Labels[types.OpenEBSVersionLabelKey]
+		}
+		versions[cvc.Name] = version
+	}
+	return versions, nil
+}
+
+// volumesOnCSPC returns the volumes having replicas on the given cspc
+func volumesOnCSPC(openebsNamespace, cspcName string,
+	clientset *kubeclient.Clientset) (map[string]bool, error) {
+	cspiList, err := clientset.OpenebsClientset.CstorV1().CStorPoolInstances(openebsNamespace).
+		List(context.TODO(), metav1.ListOptions{
+			LabelSelector: types.CStorPoolClusterLabelKey + "=" + cspcName,
+		})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list cspis for cspc %s", cspcName)
+	}
+	cspis := map[string]bool{}
+	for _, cspi := range cspiList.Items {
+		cspis[cspi.Name] = true
+	}
+	cvrList, err := clientset.OpenebsClientset.CstorV1().CStorVolumeReplicas(openebsNamespace).
+		List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cvrs")
+	}
+	volumes := map[string]bool{}
+	for _, cvr := range cvrList.Items {
+		if cspis[cvr.Labels[types.CStorPoolInstanceNameLabelKey]] {
+			volumes[cvr.Labels[types.PersistentVolumeLabelKey]] = true
+		}
+	}
+	return volumes, nil
+}
+
+func listJivaVolumes(openebsNamespace string, s VolumeSelector,
+	clientset *kubeclient.Clientset) ([]volumeInfo, error) {
+	selector, err := labels.Parse(s.LabelSelector)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid label selector %q", s.LabelSelector)
+	}
+	jvList := &jv.JivaVolumeList{}
+	err = clientset.RuntimeClient.List(context.TODO(), jvList,
+		client.InNamespace(openebsNamespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list jivavolumes")
+	}
+	volumes := []volumeInfo{}
+	for _, v := range jvList.Items {
+		volumes = append(volumes, volumeInfo{
+			name:        v.Name,
+			version:     v.VersionDetails.Status.Current,
+			annotations: v.Annotations,
+		})
+	}
+	return volumes, nil
+}
+
+// matchPV checks the storageclass and the pvc namespace of the volume
+func matchPV(name string, s VolumeSelector, clientset *kubeclient.Clientset) (bool, error) {
+	if s.StorageClass == "" && s.PVCNamespace == "" {
+		return true, nil
+	}
+	pv, err := clientset.KubeClientset.CoreV1().PersistentVolumes().
+		Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get pv %s", name)
+	}
+	if s.StorageClass != "" && pv.Spec.StorageClassName != s.StorageClass {
+		return false, nil
+	}
+	if s.PVCNamespace != "" &&
+		(pv.Spec.ClaimRef == nil || pv.Spec.ClaimRef.Namespace != s.PVCNamespace) {
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"reflect"
+	"testing"
+
+	cstor "github.com/openebs/api/v3/pkg/apis/cstor/v1"
+	"github.com/openebs/api/v3/pkg/apis/types"
+	openebsFakeClientset "github.com/openebs/api/v3/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openebs/upgrade/pkg/kubeclient"
+)
+
+func newCV(name, version string, annotations map[string]string) *cstor.CStorVolume {
+	cv := &cstor.CStorVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "openebs",
+			Labels:      map[string]string{"app": name},
+			Annotations: annotations,
+		},
+	}
+	cv.VersionDetails.Status.Current = version
+	return cv
+}
+
+func newCVC(name, version string) *cstor.CStorVolumeConfig {
+	cvc := &cstor.CStorVolumeConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "openebs",
+			Labels:    map[string]string{},
+		},
+	}
+	cvc.VersionDetails.Status.Current = version
+	return cvc
+}
+
+func newPV(name, sc, pvcNamespace string) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: corev1.PersistentVolumeSpec{
+			StorageClassName: sc,
+			ClaimRef: &corev1.ObjectReference{
+				Namespace: pvcNamespace,
+			},
+		},
+	}
+}
+
+func newCVR(name, pv, cspi string) *cstor.CStorVolumeReplica {
+	return &cstor.CStorVolumeReplica{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "openebs",
+			Labels: map[string]string{
+				types.PersistentVolumeLabelKey:      pv,
+				types.CStorPoolInstanceNameLabelKey: cspi,
+			},
+		},
+	}
+}
+
+func newCSPI(name, cspc string) *cstor.CStorPoolInstance {
+	return &cstor.CStorPoolInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "openebs",
+			Labels: map[string]string{
+				types.CStorPoolClusterLabelKey: cspc,
+			},
+		},
+	}
+}
+
+func TestSelectVolumes(t *testing.T) {
+	openebsObjects := []runtime.Object{
+		newCV("pv1", "3.4.0", nil),
+		newCV("pv2", "3.5.0", nil),
+		newCV("pv3", "3.4.0", map[string]string{SkipUpgradeAnnotation: "true"}),
+		// the upgrade of pv4 failed after its CV was reconciled
+		newCV("pv4", "3.5.0", nil),
+		newCVC("pv1", "3.4.0"),
+		newCVC("pv2", "3.5.0"),
+		newCVC("pv3", "3.4.0"),
+		newCVC("pv4", "3.4.0"),
+		newCSPI("cspi-a", "cspc-a"),
+		newCSPI("cspi-b", "cspc-b"),
+		newCVR("pv1-cspi-a", "pv1", "cspi-a"),
+		newCVR("pv4-cspi-b", "pv4", "cspi-b"),
+	}
+	kubeObjects := []runtime.Object{
+		newPV("pv1", "cstor-sc", "app"),
+		newPV("pv2", "cstor-sc", "app"),
+		newPV("pv3", "cstor-sc", "db"),
+		newPV("pv4", "other-sc", "db"),
+	}
+	tests := []struct {
+		name     string
+		selector VolumeSelector
+		want     []SelectedVolume
+		wantErr  bool
+	}{
+		{
+			name:     "no selector",
+			selector: VolumeSelector{},
+			wantErr:  true,
+		},
+		{
+			name:     "all volumes",
+			selector: VolumeSelector{All: true},
+			want: []SelectedVolume{
+				{Name: "pv1", Version: "3.4.0"},
+				{Name: "pv2", Version: "3.5.0", SkipReason: "already in 3.5.0 version"},
+				{Name: "pv3", Version: "3.4.0", SkipReason: "annotated with " + SkipUpgradeAnnotation},
+				{Name: "pv4", Version: "3.4.0"},
+			},
+		},
+		{
+			name:     "volumes on cspc",
+			selector: VolumeSelector{CSPC: "cspc-b"},
+			want: []SelectedVolume{
+				{Name: "pv4", Version: "3.4.0"},
+			},
+		},
+		{
+			name:     "volumes by storageclass and pvc namespace",
+			selector: VolumeSelector{StorageClass: "cstor-sc", PVCNamespace: "db"},
+			want: []SelectedVolume{
+				{Name: "pv3", Version: "3.4.0", SkipReason: "annotated with " + SkipUpgradeAnnotation},
+			},
+		},
+		{
+			name:     "volumes by label",
+			selector: VolumeSelector{LabelSelector: "app=pv1"},
+			want: []SelectedVolume{
+				{Name: "pv1", Version: "3.4.0"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientset := &kubeclient.Clientset{
+				KubeClientset:    fake.NewSimpleClientset(kubeObjects...),
+				OpenebsClientset: openebsFakeClientset.NewSimpleClientset(openebsObjects...),
+			}
+			got, err := SelectVolumes("cstorVolume", "openebs", "3.5.0", test.selector, clientset)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("SelectVolumes() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+				t.Errorf("SelectVolumes() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"context"
+	"time"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/openebs/upgrade/pkg/upgrade/wait"
+)
+
+const (
+	// UpgradeControlAnnotation is set on an UpgradeTask to pause,
+	// resume or cancel the upgrade, it is checked between the
+	// steps of the upgrade so that no resource is left half patched
+	UpgradeControlAnnotation = "openebs.io/upgrade-control"
+	// ControlPause, ControlResume and ControlCancel are
+	// the values of the upgrade control annotation
+	ControlPause  = "pause"
+	ControlResume = "resume"
+	ControlCancel = "cancel"
+
+	// Paused is the step recorded on the UpgradeTask while
+	// the upgrade is paused, it completes once resumed
+	Paused v1Alpha1API.UpgradeStep = "PAUSED"
+)
+
+var (
+	// ErrUpgradeCancelled is returned by the upgrade of a
+	// resource cancelled with the upgrade control annotation
+	ErrUpgradeCancelled = errors.New("upgrade cancelled")
+
+	// controlInterval is the interval between two checks
+	// of the upgrade control annotation while paused
+	controlInterval = 10 * time.Second
+)
+
+// IsCancelled returns true if the upgrade was
+// cancelled with the upgrade control annotation
+func IsCancelled(err error) bool {
+	return err != nil && errors.Cause(err) == ErrUpgradeCancelled
+}
+
+// checkControl is called between the steps of the upgrade. It waits
+// while the UpgradeTask is annotated to be paused, recording the wait
+// as a step, and returns ErrUpgradeCancelled if it is annotated to be
+// cancelled. The returned UpgradeTask is the latest one.
+func (r *ResourcePatch) checkControl(utaskObj *v1Alpha1API.UpgradeTask,
+	client *Client) (*v1Alpha1API.UpgradeTask, error) {
+	if utaskObj == nil {
+		return utaskObj, nil
+	}
+	return r.waitForResume(utaskObj, client)
+}
+
+// waitForResume waits while the UpgradeTask is annotated to be paused
+func (r *ResourcePatch) waitForResume(utaskObj *v1Alpha1API.UpgradeTask,
+	client *Client) (*v1Alpha1API.UpgradeTask, error) {
+	latest, err := getUpgradeTask(r.baseContext(), utaskObj, client)
+	if err != nil {
+		return utaskObj, err
+	}
+	switch latest.Annotations[UpgradeControlAnnotation] {
+	case ControlCancel:
+		return r.cancel(latest, client)
+	case ControlPause:
+	default:
+		return latest, nil
+	}
+
+	klog.Infof("Upgrade of %s is paused, waiting for the %s annotation of %s to be changed",
+		r.Name, UpgradeControlAnnotation, latest.Name)
+	interrupted := interruptedStep(latest)
+	statusObj := v1Alpha1API.UpgradeDetailedStatuses{Step: Paused}
+	statusObj.Phase = v1Alpha1API.StepWaiting
+	statusObj.Message = "Upgrade paused by the " + UpgradeControlAnnotation + " annotation"
+	latest, err = updateUpgradeDetailedStatus(latest, statusObj, r.OpenebsNamespace, client)
+	if err != nil {
+		return utaskObj, err
+	}
+	control := ControlPause
+	err = wait.For(r.baseContext(), "resume of "+latest.Name, controlInterval, 0,
+		func(ctx context.Context) (bool, error) {
+			obj, err := getUpgradeTask(ctx, latest, client)
+			if err != nil {
+				return false, err
+			}
+			latest = obj
+			control = obj.Annotations[UpgradeControlAnnotation]
+			return control != ControlPause, nil
+		},
+	)
+	if err != nil {
+		return latest, err
+	}
+	statusObj.Phase = v1Alpha1API.StepCompleted
+	statusObj.Message = "Upgrade resumed"
+	if control == ControlCancel {
+		statusObj.Message = "Upgrade cancelled while paused"
+	}
+	latest, err = updateUpgradeDetailedStatus(latest, statusObj, r.OpenebsNamespace, client)
+	if err != nil {
+		return utaskObj, err
+	}
+	if control == ControlCancel {
+		return r.cancel(latest, client)
+	}
+	klog.Infof("Upgrade of %s is resumed", r.Name)
+	return r.resumeStep(latest, interrupted, client)
+}
+
+// interruptedStep returns a copy of the step in progress, if any,
+// it is resumed once the step interrupting it is completed
+func interruptedStep(utaskObj *v1Alpha1API.UpgradeTask) *v1Alpha1API.UpgradeDetailedStatuses {
+	l := len(utaskObj.Status.UpgradeDetailedStatuses)
+	if l == 0 || utaskObj.Status.UpgradeDetailedStatuses[l-1].Phase != v1Alpha1API.StepWaiting {
+		return nil
+	}
+	s := utaskObj.Status.UpgradeDetailedStatuses[l-1]
+	return &s
+}
+
+// resumeStep records the interrupted step as in progress again, so
+// that the step is completed by the following status updates
+func (r *ResourcePatch) resumeStep(utaskObj *v1Alpha1API.UpgradeTask,
+	interrupted *v1Alpha1API.UpgradeDetailedStatuses,
+	client *Client) (*v1Alpha1API.UpgradeTask, error) {
+	if interrupted == nil {
+		return utaskObj, nil
+	}
+	interrupted.Phase = v1Alpha1API.StepWaiting
+	latest, err := updateUpgradeDetailedStatus(utaskObj, *interrupted, r.OpenebsNamespace, client)
+	if err != nil {
+		return utaskObj, err
+	}
+	return latest, nil
+}
+
+// cancel fails the step interrupted by the cancellation
+// and the UpgradeTask, and returns ErrUpgradeCancelled
+func (r *ResourcePatch) cancel(utaskObj *v1Alpha1API.UpgradeTask,
+	client *Client) (*v1Alpha1API.UpgradeTask, error) {
+	klog.Infof("Upgrade of %s is cancelled by the %s annotation of %s",
+		r.Name, UpgradeControlAnnotation, utaskObj.Name)
+	if l := len(utaskObj.Status.UpgradeDetailedStatuses); l != 0 &&
+		utaskObj.Status.UpgradeDetailedStatuses[l-1].Phase == v1Alpha1API.StepWaiting {
+		statusObj := utaskObj.Status.UpgradeDetailedStatuses[l-1]
+		statusObj.Phase = v1Alpha1API.StepErrored
+		statusObj.Message = "Upgrade cancelled"
+		statusObj.Reason = "cancelled by the " + UpgradeControlAnnotation + " annotation"
+		obj, err := updateUpgradeDetailedStatus(utaskObj, statusObj, r.OpenebsNamespace, client)
+		if err != nil {
+			return utaskObj, err
+		}
+		utaskObj = obj
+	}
+	utaskObj.Status.Phase = v1Alpha1API.UpgradeError
+	utaskObj.Status.CompletedTime = metav1.Now()
+	obj, err := client.OpenebsClientset.OpenebsV1alpha1().UpgradeTasks(utaskObj.Namespace).
+		Update(context.TODO(), utaskObj, metav1.UpdateOptions{})
+	if err != nil {
+		return utaskObj, errors.Wrapf(err, "failed to record cancellation of %s", utaskObj.Name)
+	}
+	return obj, ErrUpgradeCancelled
+}
+
+func getUpgradeTask(ctx context.Context, utaskObj *v1Alpha1API.UpgradeTask,
+	client *Client) (*v1Alpha1API.UpgradeTask, error) {
+	obj, err := client.OpenebsClientset.OpenebsV1alpha1().UpgradeTasks(utaskObj.Namespace).
+		Get(ctx, utaskObj.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get upgradetask %s", utaskObj.Name)
+	}
+	return obj, nil
+}
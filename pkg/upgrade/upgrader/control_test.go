@@ -0,0 +1,148 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	openebsFakeClientset "github.com/openebs/api/v3/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// stepPhase is the step and the phase of a detailed status
+type stepPhase struct {
+	step  v1Alpha1API.UpgradeStep
+	phase v1Alpha1API.StepPhase
+}
+
+func TestCheckControl(t *testing.T) {
+	defer func(interval time.Duration) {
+		controlInterval = interval
+	}(controlInterval)
+	controlInterval = time.Millisecond
+	r := &ResourcePatch{
+		Name:             "pvc-1",
+		OpenebsNamespace: "openebs",
+		From:             "3.4.0",
+		To:               "3.5.0",
+	}
+	tests := map[string]struct {
+		// control is the annotation when checked, then
+		// the annotation set once the upgrade is paused
+		control, then string
+		wantErr       bool
+		wantPhase     v1Alpha1API.UpgradePhase
+		wantStatuses  []stepPhase
+	}{
+		"no annotation": {
+			wantPhase: v1Alpha1API.UpgradeStarted,
+			wantStatuses: []stepPhase{
+				{v1Alpha1API.ReplicaUpgrade, v1Alpha1API.StepWaiting},
+			},
+		},
+		"resume": {
+			wantPhase: v1Alpha1API.UpgradeStarted,
+			control:   ControlResume,
+			wantStatuses: []stepPhase{
+				{v1Alpha1API.ReplicaUpgrade, v1Alpha1API.StepWaiting},
+			},
+		},
+		"cancel": {
+			control:   ControlCancel,
+			wantErr:   true,
+			wantPhase: v1Alpha1API.UpgradeError,
+			wantStatuses: []stepPhase{
+				{v1Alpha1API.ReplicaUpgrade, v1Alpha1API.StepErrored},
+			},
+		},
+		"pause then resume": {
+			wantPhase: v1Alpha1API.UpgradeStarted,
+			control:   ControlPause,
+			then:      ControlResume,
+			wantStatuses: []stepPhase{
+				{v1Alpha1API.ReplicaUpgrade, v1Alpha1API.StepWaiting},
+				{Paused, v1Alpha1API.StepCompleted},
+				{v1Alpha1API.ReplicaUpgrade, v1Alpha1API.StepWaiting},
+			},
+		},
+		"pause then cancel": {
+			control:   ControlPause,
+			then:      ControlCancel,
+			wantErr:   true,
+			wantPhase: v1Alpha1API.UpgradeError,
+			wantStatuses: []stepPhase{
+				{v1Alpha1API.ReplicaUpgrade, v1Alpha1API.StepWaiting},
+				{Paused, v1Alpha1API.StepCompleted},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			utaskObj := buildUpgradeTask("cstorVolume", r)
+			if test.control != "" {
+				utaskObj.Annotations = map[string]string{UpgradeControlAnnotation: test.control}
+			}
+			status := v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.ReplicaUpgrade}
+			status.Phase = v1Alpha1API.StepWaiting
+			utaskObj.Status.UpgradeDetailedStatuses = []v1Alpha1API.UpgradeDetailedStatuses{status}
+			client := &Client{
+				OpenebsClientset: openebsFakeClientset.NewSimpleClientset(utaskObj),
+			}
+			tasks := client.OpenebsClientset.OpenebsV1alpha1().UpgradeTasks(utaskObj.Namespace)
+			if test.then != "" {
+				// change the annotation once the Paused step is recorded
+				go func() {
+					for {
+						obj, err := tasks.Get(context.TODO(), utaskObj.Name, metav1.GetOptions{})
+						if err != nil {
+							t.Errorf("failed to get upgradetask: %v", err)
+							return
+						}
+						l := len(obj.Status.UpgradeDetailedStatuses)
+						if obj.Status.UpgradeDetailedStatuses[l-1].Step == Paused {
+							obj.Annotations[UpgradeControlAnnotation] = test.then
+							_, err = tasks.Update(context.TODO(), obj, metav1.UpdateOptions{})
+							if err != nil {
+								t.Errorf("failed to update upgradetask: %v", err)
+							}
+							return
+						}
+						time.Sleep(time.Millisecond)
+					}
+				}()
+			}
+			got, err := r.checkControl(utaskObj, client)
+			if IsCancelled(err) != test.wantErr {
+				t.Fatalf("checkControl() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if got.Status.Phase != test.wantPhase {
+				t.Errorf("checkControl() phase = %q, want %q", got.Status.Phase, test.wantPhase)
+			}
+			statuses := []stepPhase{}
+			for _, s := range got.Status.UpgradeDetailedStatuses {
+				statuses = append(statuses, stepPhase{s.Step, s.Phase})
+			}
+			if !reflect.DeepEqual(statuses, test.wantStatuses) {
+				t.Errorf("checkControl() statuses = %v, want %v", statuses, test.wantStatuses)
+			}
+		})
+	}
+}
@@ -18,10 +18,12 @@ package upgrader
 
 import (
 	"context"
-	"time"
 
 	cstor "github.com/openebs/api/v3/pkg/apis/cstor/v1"
 	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 
@@ -33,6 +35,9 @@ type CSPCPatch struct {
 	*ResourcePatch
 	Namespace string
 	CSPC      *patch.CSPC
+	// CSPIs are the names of the cspis belonging to the CSPC
+	CSPIs []string
+	Utask *v1Alpha1API.UpgradeTask
 	*Client
 }
 
@@ -63,27 +68,77 @@ func NewCSPCPatch(opts ...CSPCPatchOptions) *CSPCPatch {
 }
 
 // PreUpgrade ...
-func (obj *CSPCPatch) PreUpgrade() error {
-	err := isOperatorUpgraded("cspc-operator", obj.Namespace, obj.To, obj.KubeClientset)
+func (obj *CSPCPatch) PreUpgrade() (string, error) {
+	err := obj.ImageMapping.Validate()
 	if err != nil {
-		return err
+		return "failed to validate image mapping", err
+	}
+	err = isOperatorUpgraded("cspc-operator", obj.Namespace, obj.To, obj.KubeClientset)
+	if err != nil {
+		return "failed to verify cspc-operator", err
 	}
 	err = obj.CSPC.PreChecks(obj.From, obj.To)
-	return err
+	if err != nil {
+		return "failed to verify cstor pool cluster", err
+	}
+	return "", nil
+}
+
+// checkCSPIImages probes the new images of the pool deployments of all
+// the cspis at once, so that a missing image fails the upgrade before
+// any pool instance is patched
+func (obj *CSPCPatch) checkCSPIImages() (string, error) {
+	if !obj.ImagePullCheck {
+		return "", nil
+	}
+	checks := []imageCheck{}
+	for _, name := range obj.CSPIs {
+		if obj.isCSPIUpgraded(name) {
+			continue
+		}
+		res := *obj.ResourcePatch
+		res.Name = name
+		cspi := NewCSPIPatch(
+			WithCSPIResorcePatch(&res),
+			WithCSPIClient(obj.Client),
+		)
+		msg, err := cspi.Init()
+		if err != nil {
+			return msg + " " + name, err
+		}
+		checks = append(checks, cspi.imageChecks...)
+	}
+	return obj.checkImages(obj.KubeClientset, checks...)
 }
 
 // Init initializes all the fields of the CSPCPatch
-func (obj *CSPCPatch) Init() error {
+func (obj *CSPCPatch) Init() (string, error) {
 	obj.Namespace = obj.OpenebsNamespace
 	obj.CSPC = patch.NewCSPC(
 		patch.WithCSPCClient(obj.OpenebsClientset),
 	)
 	err := obj.CSPC.Get(obj.Name, obj.Namespace)
 	if err != nil {
-		return err
+		return "failed to get cstor pool cluster", err
 	}
 	err = getCSPCPatchData(obj)
-	return err
+	if err != nil {
+		return "failed to create cstor pool cluster patch", err
+	}
+	cspiList, err := obj.Client.OpenebsClientset.CstorV1().
+		CStorPoolInstances(obj.Namespace).List(context.TODO(),
+		metav1.ListOptions{
+			LabelSelector: "openebs.io/cstor-pool-cluster=" + obj.Name,
+		},
+	)
+	if err != nil {
+		return "failed to list cstor pool instances", err
+	}
+	obj.CSPIs = []string{}
+	for _, cspiObj := range cspiList.Items {
+		obj.CSPIs = append(obj.CSPIs, cspiObj.Name)
+	}
+	return "", nil
 }
 
 func getCSPCPatchData(obj *CSPCPatch) error {
@@ -102,103 +157,385 @@ func transformCSPC(c *cstor.CStorPoolCluster, res *ResourcePatch) error {
 }
 
 // CSPCUpgrade ...
-func (obj *CSPCPatch) CSPCUpgrade() error {
-	err := obj.CSPC.Patch(obj.From, obj.To)
+func (obj *CSPCPatch) CSPCUpgrade(ctx context.Context) (string, error) {
+	err := obj.CSPC.Patch(ctx, obj.From, obj.To)
 	if err != nil {
-		return err
+		return "failed to patch cstor pool cluster", err
 	}
-	return nil
+	obj.recordPatch("CStorPoolCluster", obj.CSPC.Object, obj.CSPC.Data)
+	return "", nil
+}
+
+func (obj *CSPCPatch) backup() (string, error) {
+	err := saveBackup("cstorPoolCluster", obj.ResourcePatch, obj.Client,
+		backupObject{kind: "CStorPoolCluster", obj: obj.CSPC.Object.DeepCopy()},
+	)
+	if err != nil {
+		return "failed to backup cstor pool cluster", err
+	}
+	return "", nil
 }
 
 // Upgrade execute the steps to upgrade CSPC
 func (obj *CSPCPatch) Upgrade() error {
-	err := obj.Init()
+	var err, uerr error
+	obj.Utask, uerr = getOrCreateUpgradeTask(
+		"cstorPoolCluster",
+		obj.ResourcePatch,
+		obj.Client,
+	)
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+	statusObj := v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.PreUpgrade}
+	statusObj.Phase = v1Alpha1API.StepWaiting
+	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+	statusObj.Phase = v1Alpha1API.StepErrored
+	msg, err := obj.Init()
 	if err != nil {
-		return err
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
 	}
-	err = obj.PreUpgrade()
+	msg, err = obj.PreUpgrade()
 	if err != nil {
-		return err
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
 	}
-	res := *obj.ResourcePatch
-	cspiList, err := obj.Client.OpenebsClientset.CstorV1().
-		CStorPoolInstances(obj.Namespace).List(context.TODO(),
-		metav1.ListOptions{
-			LabelSelector: "openebs.io/cstor-pool-cluster=" + obj.Name,
-		},
-	)
+	msg, err = obj.checkCSPIImages()
 	if err != nil {
-		return err
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
 	}
-	for _, cspiObj := range cspiList.Items {
-		res.Name = cspiObj.Name
-		dependant := NewCSPIPatch(
-			WithCSPIResorcePatch(&res),
-			WithCSPIClient(obj.Client),
-		)
-		err = dependant.Upgrade()
+	msg, err = obj.backup()
+	if err != nil {
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
+	}
+	statusObj.Phase = v1Alpha1API.StepCompleted
+	statusObj.Message = "Pre-upgrade steps were successful"
+	statusObj.Reason = ""
+	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+
+	for _, cspiName := range obj.CSPIs {
+		key := "cspi/" + cspiName
+		// revalidate the cspis upgraded by a previous attempt
+		if isCompleted(obj.Utask, key) && obj.isCSPIUpgraded(cspiName) {
+			klog.Infof("cspi %s is already upgraded to %s, skipping", cspiName, obj.To)
+			continue
+		}
+		// the upgrade can be paused or cancelled between two pool instances
+		obj.Utask, uerr = obj.checkControl(obj.Utask, obj.Client)
+		if uerr != nil && (obj.IsUpgradeTaskJob || IsCancelled(uerr)) {
+			return uerr
+		}
+		statusObj = v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.PoolInstanceUpgrade}
+		statusObj.Phase = v1Alpha1API.StepWaiting
+		statusObj.Message = "Upgrading pool instance " + cspiName
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		statusObj.Phase = v1Alpha1API.StepErrored
+		msg, err = obj.upgradeCSPI(cspiName)
 		if err != nil {
-			utaskObj, uerr := obj.OpenebsClientset.OpenebsV1alpha1().
-				UpgradeTasks(obj.OpenebsNamespace).
-				Get(context.TODO(), "upgrade-cstor-cspi-"+cspiObj.Name, metav1.GetOptions{})
-			if uerr != nil && isUpgradeTaskJob {
-				return uerr
-			}
-			backoffLimit, uerr := getBackoffLimit(obj.OpenebsNamespace, obj.Client)
-			if uerr != nil && isUpgradeTaskJob {
-				return uerr
-			}
-			utaskObj.Status.Retries = utaskObj.Status.Retries + 1
-			if utaskObj.Status.Retries == backoffLimit {
-				utaskObj.Status.Phase = v1Alpha1API.UpgradeError
-				utaskObj.Status.CompletedTime = metav1.Now()
-			}
-			_, uerr = obj.OpenebsClientset.OpenebsV1alpha1().UpgradeTasks(obj.OpenebsNamespace).
-				Update(context.TODO(), utaskObj, metav1.UpdateOptions{})
-			if uerr != nil && isUpgradeTaskJob {
+			statusObj.Message = msg
+			statusObj.Reason = err.Error()
+			obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+			if uerr != nil && obj.IsUpgradeTaskJob {
 				return uerr
 			}
-			return err
+			return errors.Wrap(err, msg)
 		}
-		utaskObj, uerr := obj.OpenebsClientset.OpenebsV1alpha1().UpgradeTasks(obj.OpenebsNamespace).
-			Get(context.TODO(), "upgrade-cstor-cspi-"+cspiObj.Name, metav1.GetOptions{})
-		if uerr != nil && isUpgradeTaskJob {
+		statusObj.Phase = v1Alpha1API.StepCompleted
+		statusObj.Message = "Pool instance " + cspiName + " upgrade was successful"
+		statusObj.Reason = ""
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
 			return uerr
 		}
-		utaskObj.Status.Phase = v1Alpha1API.UpgradeSuccess
-		utaskObj.Status.CompletedTime = metav1.Now()
+		obj.Utask, uerr = markCompleted(obj.Utask, key, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+	}
+
+	obj.Utask, uerr = obj.checkControl(obj.Utask, obj.Client)
+	if uerr != nil && (obj.IsUpgradeTaskJob || IsCancelled(uerr)) {
+		return uerr
+	}
+
+	statusObj = v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.Verify}
+	statusObj.Phase = v1Alpha1API.StepWaiting
+	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+	statusObj.Phase = v1Alpha1API.StepErrored
+	ctx, cancel := obj.stepContext()
+	defer cancel()
+	msg, err = obj.CSPCUpgrade(ctx)
+	if err != nil {
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
+	}
+	msg, err = obj.verifyCSPCVersionReconcile(ctx)
+	if err != nil {
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
+	}
+	statusObj.Phase = v1Alpha1API.StepCompleted
+	statusObj.Message = "Cstor pool cluster version reconcile was successful"
+	statusObj.Reason = ""
+	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+	return nil
+}
+
+// isCSPIUpgraded checks that both the cspi and its
+// deployment are still in the desired version
+func (obj *CSPCPatch) isCSPIUpgraded(name string) bool {
+	cspiObj, err := obj.OpenebsClientset.CstorV1().CStorPoolInstances(obj.Namespace).
+		Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		klog.Warningf("failed to get cspi %s: %v", name, err)
+		return false
+	}
+	if cspiObj.Labels["openebs.io/version"] != obj.To ||
+		cspiObj.VersionDetails.Status.Current != obj.To {
+		return false
+	}
+	deployList, err := obj.KubeClientset.AppsV1().Deployments(obj.Namespace).
+		List(context.TODO(), metav1.ListOptions{
+			LabelSelector: "openebs.io/cstor-pool-instance=" + name,
+		})
+	if err != nil {
+		klog.Warningf("failed to get deployment for cspi %s: %v", name, err)
+		return false
+	}
+	if len(deployList.Items) != 1 ||
+		deployList.Items[0].Labels["openebs.io/version"] != obj.To {
+		return false
+	}
+	return true
+}
+
+// upgradeCSPI upgrades the given cspi of the CSPC and records the
+// result on its UpgradeTask, which is linked to the CSPC UpgradeTask
+func (obj *CSPCPatch) upgradeCSPI(name string) (string, error) {
+	res := *obj.ResourcePatch
+	res.Name = name
+	// the images of all the cspis are probed before the upgrade
+	res.ImagePullCheck = false
+	dependant := NewCSPIPatch(
+		WithCSPIResorcePatch(&res),
+		WithCSPIClient(obj.Client),
+		WithCSPIParentUpgradeTask(obj.Utask),
+	)
+	err := dependant.Upgrade()
+	if err != nil {
+		utaskObj, uerr := obj.OpenebsClientset.OpenebsV1alpha1().
+			UpgradeTasks(obj.OpenebsNamespace).
+			Get(context.TODO(), "upgrade-cstor-cspi-"+name, metav1.GetOptions{})
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return "failed to get upgradetask for pool instance " + name, uerr
+		}
+		// the retries are bounded by the controller if it runs
+		// the upgrade, else by the backoffLimit of the job
+		maxRetries := obj.MaxRetries
+		if maxRetries == 0 {
+			maxRetries, uerr = getBackoffLimit(obj.OpenebsNamespace, obj.Client)
+			if uerr != nil && obj.IsUpgradeTaskJob {
+				return "failed to get backoff limit", uerr
+			}
+		}
+		utaskObj.Status.Retries = utaskObj.Status.Retries + 1
+		if utaskObj.Status.Retries == maxRetries {
+			utaskObj.Status.Phase = v1Alpha1API.UpgradeError
+			utaskObj.Status.CompletedTime = metav1.Now()
+		}
 		_, uerr = obj.OpenebsClientset.OpenebsV1alpha1().UpgradeTasks(obj.OpenebsNamespace).
 			Update(context.TODO(), utaskObj, metav1.UpdateOptions{})
-		if uerr != nil && isUpgradeTaskJob {
-			return uerr
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return "failed to update upgradetask for pool instance " + name, uerr
 		}
+		return "failed to upgrade pool instance " + name, err
+	}
+	utaskObj, uerr := obj.OpenebsClientset.OpenebsV1alpha1().UpgradeTasks(obj.OpenebsNamespace).
+		Get(context.TODO(), "upgrade-cstor-cspi-"+name, metav1.GetOptions{})
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return "failed to get upgradetask for pool instance " + name, uerr
+	}
+	utaskObj.Status.Phase = v1Alpha1API.UpgradeSuccess
+	utaskObj.Status.CompletedTime = metav1.Now()
+	_, uerr = obj.OpenebsClientset.OpenebsV1alpha1().UpgradeTasks(obj.OpenebsNamespace).
+		Update(context.TODO(), utaskObj, metav1.UpdateOptions{})
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return "failed to update upgradetask for pool instance " + name, uerr
+	}
+	return "", nil
+}
+
+// DryRun returns the changes required to upgrade the CSPC
+// and all the cspis belonging to it
+func (obj *CSPCPatch) DryRun() ([]Change, error) {
+	msg, err := obj.Init()
+	if err != nil {
+		return nil, errors.Wrap(err, msg)
 	}
-	err = obj.CSPCUpgrade()
+	msg, err = obj.PreUpgrade()
 	if err != nil {
-		return err
+		return nil, errors.Wrap(err, msg)
 	}
-	err = obj.verifyCSPCVersionReconcile()
-	return err
+	changes := []Change{}
+	res := *obj.ResourcePatch
+	for _, cspiName := range obj.CSPIs {
+		res.Name = cspiName
+		dependant := NewCSPIPatch(
+			WithCSPIResorcePatch(&res),
+			WithCSPIClient(obj.Client),
+		)
+		cspiChanges, err := dependant.DryRun()
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, cspiChanges...)
+	}
+	changes = appendChange(changes, "CStorPoolCluster", obj.CSPC.Object, obj.CSPC.Data)
+	return changes, nil
 }
 
-func (obj *CSPCPatch) verifyCSPCVersionReconcile() error {
-	// get the latest cspc object
-	err := obj.CSPC.Get(obj.Name, obj.Namespace)
+// Rollback restores the CSPC and all the cspis belonging
+// to it to the objects recorded before the upgrade
+func (obj *CSPCPatch) Rollback() error {
+	cm, err := getBackup("cstorPoolCluster", obj.ResourcePatch, obj.Client)
 	if err != nil {
 		return err
 	}
-	// waiting for the current version to be equal to desired version
-	for obj.CSPC.Object.VersionDetails.Status.Current != obj.To {
-		klog.Infof("Verifying the reconciliation of version for %s", obj.CSPC.Object.Name)
-		// Sleep equal to the default sync time
-		time.Sleep(10 * time.Second)
-		err = obj.CSPC.Get(obj.Name, obj.Namespace)
+	setRollbackVersions(obj.ResourcePatch, cm)
+	var uerr error
+	obj.Utask, uerr = getUpgradeTaskForRollback(
+		"cstorPoolCluster",
+		obj.ResourcePatch,
+		obj.Client,
+	)
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+	statusObj := v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.Rollback}
+	statusObj.Phase = v1Alpha1API.StepWaiting
+	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+	statusObj.Phase = v1Alpha1API.StepErrored
+	ctx, cancel := obj.stepContext()
+	defer cancel()
+	msg, err := obj.rollback(ctx, cm)
+	if err != nil {
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
+	}
+	statusObj.Phase = v1Alpha1API.StepCompleted
+	statusObj.Message = "Rollback was successful"
+	statusObj.Reason = ""
+	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+	return nil
+}
+
+// rollback restores the CSPC and the cspis reached by the upgrade
+func (obj *CSPCPatch) rollback(ctx context.Context, cm *corev1.ConfigMap) (string, error) {
+	msg, err := obj.Init()
+	if err != nil {
+		return msg, err
+	}
+	err = rollbackCSPC(ctx, obj.CSPC, cm)
+	if err != nil {
+		return "failed to rollback cstor pool cluster", err
+	}
+	res := *obj.ResourcePatch
+	for _, cspiName := range obj.CSPIs {
+		res.Name = cspiName
+		_, err = getBackup("cstorPoolInstance", &res, obj.Client)
 		if err != nil {
-			return err
+			if k8serror.IsNotFound(errors.Cause(err)) {
+				// the upgrade did not reach this cspi
+				klog.Infof("no backup found for cspi %s, skipping", cspiName)
+				continue
+			}
+			return "failed to get backup of pool instance " + cspiName, err
 		}
-		if obj.CSPC.Object.VersionDetails.Status.Message != "" {
-			klog.Errorf("failed to reconcile: %s", obj.CSPC.Object.VersionDetails.Status.Reason)
+		dependant := NewCSPIPatch(
+			WithCSPIResorcePatch(&res),
+			WithCSPIClient(obj.Client),
+		)
+		err = dependant.Rollback()
+		if err != nil {
+			return "failed to rollback pool instance " + cspiName, err
 		}
 	}
-	return nil
+	return "", nil
+}
+
+func (obj *CSPCPatch) verifyCSPCVersionReconcile(ctx context.Context) (string, error) {
+	err := waitForVersionReconcile(ctx, obj.Name, obj.To,
+		func() (versionStatus, error) {
+			// get the latest object
+			err := obj.CSPC.Get(obj.Name, obj.Namespace)
+			if err != nil {
+				return versionStatus{}, err
+			}
+			s := obj.CSPC.Object.VersionDetails.Status
+			return versionStatus{s.Current, s.Message, s.Reason}, nil
+		},
+	)
+	if err != nil {
+		return "failed to verify cstor pool cluster version reconcile", err
+	}
+	return "", nil
 }
@@ -18,16 +18,15 @@ package upgrader
 
 import (
 	"context"
-	"time"
 
 	cstor "github.com/openebs/api/v3/pkg/apis/cstor/v1"
 	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
 	"github.com/openebs/api/v3/pkg/apis/types"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/klog/v2"
 
 	translate "github.com/openebs/upgrade/pkg/migrate/cstor"
 	"github.com/openebs/upgrade/pkg/upgrade/patch"
@@ -40,6 +39,18 @@ type CSPIPatch struct {
 	Deploy    *patch.Deployment
 	CSPI      *patch.CSPI
 	Utask     *v1Alpha1API.UpgradeTask
+	// ServiceAccount is the service account of the cspc-operator
+	// used for the pool deployment
+	ServiceAccount string
+	// ParentUtask is the UpgradeTask of the CSPC when the cspi
+	// is upgraded as part of the CSPC upgrade
+	ParentUtask *v1Alpha1API.UpgradeTask
+	// skippedContainers are the containers of the pool
+	// deployment which are not managed by openebs
+	skippedContainers []string
+	// imageChecks are the workloads whose new images
+	// are verified before they are patched
+	imageChecks []imageCheck
 	*Client
 }
 
@@ -67,6 +78,13 @@ func WithCSPIDeploy(t *patch.Deployment) CSPIPatchOptions {
 	}
 }
 
+// WithCSPIParentUpgradeTask ...
+func WithCSPIParentUpgradeTask(parent *v1Alpha1API.UpgradeTask) CSPIPatchOptions {
+	return func(obj *CSPIPatch) {
+		obj.ParentUtask = parent
+	}
+}
+
 // NewCSPIPatch ...
 func NewCSPIPatch(opts ...CSPIPatchOptions) *CSPIPatch {
 	obj := &CSPIPatch{}
@@ -78,7 +96,11 @@ func NewCSPIPatch(opts ...CSPIPatchOptions) *CSPIPatch {
 
 // PreUpgrade ...
 func (obj *CSPIPatch) PreUpgrade() (string, error) {
-	err := obj.Deploy.PreChecks(obj.From, obj.To)
+	err := obj.ImageMapping.Validate()
+	if err != nil {
+		return "failed to validate image mapping", err
+	}
+	err = obj.Deploy.PreChecks(obj.From, obj.To)
 	if err != nil {
 		return "failed to verify cstor pool deployment", err
 	}
@@ -90,38 +112,46 @@ func (obj *CSPIPatch) PreUpgrade() (string, error) {
 }
 
 // DeployUpgrade ...
-func (obj *CSPIPatch) DeployUpgrade() (string, error) {
-	err := obj.Deploy.Patch(obj.From, obj.To)
+func (obj *CSPIPatch) DeployUpgrade(ctx context.Context) (string, error) {
+	err := obj.Deploy.Patch(ctx, obj.From, obj.To)
 	if err != nil {
 		return "failed to patch cstor pool deployment", err
 	}
+	obj.recordPatch("Deployment", obj.Deploy.Object, obj.Deploy.Data)
 	return "", nil
 }
 
 // CSPIUpgrade ...
-func (obj *CSPIPatch) CSPIUpgrade() (string, error) {
-	err := obj.CSPI.Patch(obj.From, obj.To)
+func (obj *CSPIPatch) CSPIUpgrade(ctx context.Context) (string, error) {
+	err := obj.CSPI.Patch(ctx, obj.From, obj.To)
 	if err != nil {
 		return "failed to verify cstor pool instance", err
 	}
+	obj.recordPatch("CStorPoolInstance", obj.CSPI.Object, obj.CSPI.Data)
 	return "", nil
 }
 
 // Upgrade execute the steps to upgrade cspi
 func (obj *CSPIPatch) Upgrade() error {
 	var err, uerr error
-	obj.Utask, err = getOrCreateUpgradeTask(
+	obj.Utask, uerr = getOrCreateUpgradeTask(
 		"cstorPoolInstance",
 		obj.ResourcePatch,
 		obj.Client,
 	)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
+	if obj.ParentUtask != nil && obj.Utask != nil {
+		obj.Utask, uerr = setParentUpgradeTask(obj.Utask, obj.ParentUtask, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+	}
 	statusObj := v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.PreUpgrade}
 	statusObj.Phase = v1Alpha1API.StepWaiting
 	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
 	statusObj.Phase = v1Alpha1API.StepErrored
@@ -130,7 +160,7 @@ func (obj *CSPIPatch) Upgrade() error {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
 		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-		if uerr != nil && isUpgradeTaskJob {
+		if uerr != nil && obj.IsUpgradeTaskJob {
 			return uerr
 		}
 		return errors.Wrap(err, msg)
@@ -140,7 +170,27 @@ func (obj *CSPIPatch) Upgrade() error {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
 		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-		if uerr != nil && isUpgradeTaskJob {
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
+	}
+	msg, err = obj.checkImages(obj.KubeClientset, obj.imageChecks...)
+	if err != nil {
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
+	}
+	msg, err = obj.backup()
+	if err != nil {
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
 			return uerr
 		}
 		return errors.Wrap(err, msg)
@@ -149,43 +199,69 @@ func (obj *CSPIPatch) Upgrade() error {
 	statusObj.Message = "Pre-upgrade steps were successful"
 	statusObj.Reason = ""
 	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
 
+	obj.Utask, uerr = obj.checkControl(obj.Utask, obj.Client)
+	if uerr != nil && (obj.IsUpgradeTaskJob || IsCancelled(uerr)) {
+		return uerr
+	}
 	statusObj = v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.PoolInstanceUpgrade}
 	statusObj.Phase = v1Alpha1API.StepWaiting
 	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
 	statusObj.Phase = v1Alpha1API.StepErrored
-	msg, err = obj.DeployUpgrade()
+	msg, err = obj.verifyReplicaQuorum()
 	if err != nil {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
 		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-		if uerr != nil && isUpgradeTaskJob {
+		if uerr != nil && obj.IsUpgradeTaskJob {
 			return uerr
 		}
 		return errors.Wrap(err, msg)
 	}
-	msg, err = obj.CSPIUpgrade()
+	ctx, cancel := obj.stepContext()
+	defer cancel()
+	msg, err = obj.DeployUpgrade(ctx)
 	if err != nil {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
 		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-		if uerr != nil && isUpgradeTaskJob {
+		if uerr != nil && obj.IsUpgradeTaskJob {
 			return uerr
 		}
 		return errors.Wrap(err, msg)
 	}
-	msg, err = obj.verifyCSPIVersionReconcile()
+	msg, err = obj.CSPIUpgrade(ctx)
 	if err != nil {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
 		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-		if uerr != nil && isUpgradeTaskJob {
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
+	}
+	msg, err = obj.verifyCSPIVersionReconcile(ctx)
+	if err != nil {
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
+	}
+	msg, err = obj.verifyReplicaRebuild(ctx)
+	if err != nil {
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
 			return uerr
 		}
 		return errors.Wrap(err, msg)
@@ -195,7 +271,7 @@ func (obj *CSPIPatch) Upgrade() error {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
 		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-		if uerr != nil && isUpgradeTaskJob {
+		if uerr != nil && obj.IsUpgradeTaskJob {
 			return uerr
 		}
 		return errors.Wrap(err, msg)
@@ -204,12 +280,101 @@ func (obj *CSPIPatch) Upgrade() error {
 	statusObj.Message = "Pool instance upgrade was successful"
 	statusObj.Reason = ""
 	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+	return nil
+}
+
+// DryRun returns the changes required to upgrade the cspi
+func (obj *CSPIPatch) DryRun() ([]Change, error) {
+	msg, err := obj.Init()
+	if err != nil {
+		return nil, errors.Wrap(err, msg)
+	}
+	msg, err = obj.PreUpgrade()
+	if err != nil {
+		return nil, errors.Wrap(err, msg)
+	}
+	changes := appendChange(nil, "Deployment", obj.Deploy.Object, obj.Deploy.Data,
+		obj.skippedContainers...)
+	changes = appendChange(changes, "CStorPoolInstance", obj.CSPI.Object, obj.CSPI.Data)
+	return changes, nil
+}
+
+func (obj *CSPIPatch) backup() (string, error) {
+	err := saveBackup("cstorPoolInstance", obj.ResourcePatch, obj.Client,
+		backupObject{kind: "Deployment", obj: obj.Deploy.Object.DeepCopy()},
+		backupObject{kind: "CStorPoolInstance", obj: obj.CSPI.Object.DeepCopy()},
+	)
+	if err != nil {
+		return "failed to backup cstor pool instance", err
+	}
+	return "", nil
+}
+
+// Rollback restores the cspi to the objects
+// recorded before the upgrade
+func (obj *CSPIPatch) Rollback() error {
+	cm, err := getBackup("cstorPoolInstance", obj.ResourcePatch, obj.Client)
+	if err != nil {
+		return err
+	}
+	setRollbackVersions(obj.ResourcePatch, cm)
+	var uerr error
+	obj.Utask, uerr = getUpgradeTaskForRollback(
+		"cstorPoolInstance",
+		obj.ResourcePatch,
+		obj.Client,
+	)
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+	statusObj := v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.Rollback}
+	statusObj.Phase = v1Alpha1API.StepWaiting
+	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+	statusObj.Phase = v1Alpha1API.StepErrored
+	ctx, cancel := obj.stepContext()
+	defer cancel()
+	msg, err := obj.rollback(ctx, cm)
+	if err != nil {
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
+	}
+	statusObj.Phase = v1Alpha1API.StepCompleted
+	statusObj.Message = "Rollback was successful"
+	statusObj.Reason = ""
+	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
 	return nil
 }
 
+func (obj *CSPIPatch) rollback(ctx context.Context, cm *corev1.ConfigMap) (string, error) {
+	msg, err := obj.Init()
+	if err != nil {
+		return msg, err
+	}
+	err = rollbackCSPI(ctx, obj.CSPI, cm)
+	if err != nil {
+		return "failed to rollback cstor pool instance", err
+	}
+	err = rollbackDeployment(ctx, obj.Deploy, cm)
+	if err != nil {
+		return "failed to rollback cstor pool deployment", err
+	}
+	return "", nil
+}
+
 // Init initializes all the fields of the CSPIPatch
 func (obj *CSPIPatch) Init() (string, error) {
 	var err error
@@ -231,6 +396,10 @@ func (obj *CSPIPatch) Init() (string, error) {
 	if err != nil {
 		return "failed to get cstor pool instance", err
 	}
+	obj.ServiceAccount, err = getOperatorServiceAccount("cspc-operator", obj.Namespace, obj.KubeClientset)
+	if err != nil {
+		return "failed to get cspc-operator service account", err
+	}
 	err = getCSPIDeployPatchData(obj)
 	if err != nil {
 		return "failed to create cstor pool deployment patch", err
@@ -244,37 +413,27 @@ func (obj *CSPIPatch) Init() (string, error) {
 
 func getCSPIDeployPatchData(obj *CSPIPatch) error {
 	newDeploy := obj.Deploy.Object.DeepCopy()
-	err := transformCSPIDeploy(newDeploy, obj.ResourcePatch)
+	skipped, err := transformCSPIDeploy(newDeploy, obj.ResourcePatch, obj.ServiceAccount)
 	if err != nil {
 		return err
 	}
+	obj.skippedContainers = skipped
+	obj.imageChecks = []imageCheck{deploymentImageCheck(obj.Deploy.Object, newDeploy)}
 	obj.Deploy.Data, err = GetPatchData(obj.Deploy.Object, newDeploy)
 	return err
 }
 
-func transformCSPIDeploy(d *appsv1.Deployment, res *ResourcePatch) error {
-	// update deployment images
-	tag := res.To
-	if res.ImageTag != "" {
-		tag = res.ImageTag
-	}
-	cons := len(d.Spec.Template.Spec.Containers)
-	for i := 0; i < cons; i++ {
-		url, err := getImageURL(
-			d.Spec.Template.Spec.Containers[i].Image,
-			res.BaseURL,
-		)
-		if err != nil {
-			return err
-		}
-		// remove the -amd64 prefix from the image
-		url = removeSuffixFromEnd(url, "-amd64")
-		d.Spec.Template.Spec.Containers[i].Image = url + ":" + tag
+func transformCSPIDeploy(d *appsv1.Deployment, res *ResourcePatch, serviceAccount string) ([]string, error) {
+	// update the images of the openebs containers
+	skipped, err := res.rewriteImages(&d.Spec.Template.Spec, cstorPoolContainers)
+	if err != nil {
+		return nil, err
 	}
+	logSkippedContainers("Deployment", d.Name, skipped)
 	d.Labels["openebs.io/version"] = res.To
 	d.Spec.Template.Labels["openebs.io/version"] = res.To
-	d.Spec.Template.Spec.ServiceAccountName = cstorOperatorServiceAccount
-	return nil
+	d.Spec.Template.Spec.ServiceAccountName = serviceAccount
+	return skipped, nil
 }
 
 func getCSPIPatchData(obj *CSPIPatch) error {
@@ -293,24 +452,20 @@ func transformCSPI(c *cstor.CStorPoolInstance, res *ResourcePatch) error {
 	return nil
 }
 
-func (obj *CSPIPatch) verifyCSPIVersionReconcile() (string, error) {
-	// get the latest cspi object
-	err := obj.CSPI.Get(obj.Name, obj.Namespace)
+func (obj *CSPIPatch) verifyCSPIVersionReconcile(ctx context.Context) (string, error) {
+	err := waitForVersionReconcile(ctx, obj.Name, obj.To,
+		func() (versionStatus, error) {
+			// get the latest object
+			err := obj.CSPI.Get(obj.Name, obj.Namespace)
+			if err != nil {
+				return versionStatus{}, err
+			}
+			s := obj.CSPI.Object.VersionDetails.Status
+			return versionStatus{s.Current, s.Message, s.Reason}, nil
+		},
+	)
 	if err != nil {
-		return "failed to get cstor pool to verify ", err
-	}
-	// waiting for the current version to be equal to desired version
-	for obj.CSPI.Object.VersionDetails.Status.Current != obj.To {
-		klog.Infof("Verifying the reconciliation of version for %s", obj.CSPI.Object.Name)
-		// Sleep equal to the default sync time
-		time.Sleep(10 * time.Second)
-		err = obj.CSPI.Get(obj.Name, obj.Namespace)
-		if err != nil {
-			return "failed to get cstor pool to verify ", err
-		}
-		if obj.CSPI.Object.VersionDetails.Status.Message != "" {
-			klog.Errorf("failed to reconcile: %s", obj.CSPI.Object.VersionDetails.Status.Reason)
-		}
+		return "failed to verify cstor pool version reconcile", err
 	}
 	return "", nil
 }
@@ -18,7 +18,6 @@ package upgrader
 
 import (
 	"context"
-	"time"
 
 	apis "github.com/openebs/api/v3/pkg/apis/cstor/v1"
 	"github.com/pkg/errors"
@@ -73,11 +72,12 @@ func (obj *CVRPatch) PreUpgrade() error {
 }
 
 // CVRUpgrade ...
-func (obj *CVRPatch) CVRUpgrade() error {
-	err := obj.CVR.Patch(obj.From, obj.To)
+func (obj *CVRPatch) CVRUpgrade(ctx context.Context) error {
+	err := obj.CVR.Patch(ctx, obj.From, obj.To)
 	if err != nil {
 		return err
 	}
+	obj.recordPatch("CStorVolumeReplica", obj.CVR.Object, obj.CVR.Data)
 	return nil
 }
 
@@ -91,14 +91,36 @@ func (obj *CVRPatch) Upgrade() error {
 	if err != nil {
 		return err
 	}
-	err = obj.CVRUpgrade()
+	// the cvr is upgraded as part of the replica upgrade
+	// step of the volume and is bounded by its context
+	ctx := obj.baseContext()
+	err = obj.CVRUpgrade(ctx)
 	if err != nil {
 		return err
 	}
-	err = obj.verifyCVRVersionReconcile()
+	err = obj.verifyCVRVersionReconcile(ctx)
 	return err
 }
 
+// DryRun returns the changes required to upgrade the cvr
+func (obj *CVRPatch) DryRun() ([]Change, error) {
+	err := obj.Init()
+	if err != nil {
+		return nil, err
+	}
+	// pools are upgraded before the volumes, so the cspi may not be
+	// in the desired version yet while previewing the volume upgrade
+	err = obj.verifyCSPIVersion()
+	if err != nil {
+		klog.Warningf("cvr %s: %v", obj.Name, err)
+	}
+	err = obj.CVR.PreChecks(obj.From, obj.To)
+	if err != nil {
+		return nil, err
+	}
+	return appendChange(nil, "CStorVolumeReplica", obj.CVR.Object, obj.CVR.Data), nil
+}
+
 // Init initializes all the fields of the CVRPatch
 func (obj *CVRPatch) Init() error {
 	obj.Namespace = obj.OpenebsNamespace
@@ -129,26 +151,18 @@ func transformCVR(c *apis.CStorVolumeReplica, res *ResourcePatch) error {
 	return nil
 }
 
-func (obj *CVRPatch) verifyCVRVersionReconcile() error {
-	// get the latest cvr object
-	err := obj.CVR.Get(obj.Name, obj.Namespace)
-	if err != nil {
-		return err
-	}
-	// waiting for the current version to be equal to desired version
-	for obj.CVR.Object.VersionDetails.Status.Current != obj.To {
-		klog.Infof("Verifying the reconciliation of version for %s", obj.CVR.Object.Name)
-		// Sleep equal to the default sync time
-		time.Sleep(10 * time.Second)
-		err = obj.CVR.Get(obj.Name, obj.Namespace)
-		if err != nil {
-			return err
-		}
-		if obj.CVR.Object.VersionDetails.Status.Message != "" {
-			klog.Errorf("failed to reconcile: %s", obj.CVR.Object.VersionDetails.Status.Reason)
-		}
-	}
-	return nil
+func (obj *CVRPatch) verifyCVRVersionReconcile(ctx context.Context) error {
+	return waitForVersionReconcile(ctx, obj.Name, obj.To,
+		func() (versionStatus, error) {
+			// get the latest object
+			err := obj.CVR.Get(obj.Name, obj.Namespace)
+			if err != nil {
+				return versionStatus{}, err
+			}
+			s := obj.CVR.Object.VersionDetails.Status
+			return versionStatus{s.Current, s.Message, s.Reason}, nil
+		},
+	)
 }
 
 func (obj *CVRPatch) verifyCSPIVersion() error {
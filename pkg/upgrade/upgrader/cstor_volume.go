@@ -18,7 +18,6 @@ package upgrader
 
 import (
 	"context"
-	"time"
 
 	cstor "github.com/openebs/api/v3/pkg/apis/cstor/v1"
 	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
@@ -40,6 +39,15 @@ type CStorVolumePatch struct {
 	Deploy    *patch.Deployment
 	Service   *patch.Service
 	Utask     *v1Alpha1API.UpgradeTask
+	// ServiceAccount is the service account of the cvc-operator
+	// used for the target deployment
+	ServiceAccount string
+	// skippedContainers are the containers of the target
+	// deployment which are not managed by openebs
+	skippedContainers []string
+	// imageChecks are the workloads whose new images
+	// are verified before they are patched
+	imageChecks []imageCheck
 	*Client
 }
 
@@ -71,10 +79,18 @@ func NewCStorVolumePatch(opts ...CStorVolumePatchOptions) *CStorVolumePatch {
 
 // PreUpgrade ...
 func (obj *CStorVolumePatch) PreUpgrade() (string, error) {
-	err := isOperatorUpgraded("cvc-operator", obj.Namespace, obj.To, obj.KubeClientset)
+	err := obj.ImageMapping.Validate()
+	if err != nil {
+		return "failed to validate image mapping", err
+	}
+	err = isOperatorUpgraded("cvc-operator", obj.Namespace, obj.To, obj.KubeClientset)
 	if err != nil {
 		return "failed to verify cvc-operator", err
 	}
+	obj.ServiceAccount, err = getOperatorServiceAccount("cvc-operator", obj.Namespace, obj.KubeClientset)
+	if err != nil {
+		return "failed to get cvc-operator service account", err
+	}
 	err = obj.CVC.PreChecks(obj.From, obj.To)
 	if err != nil {
 		return "failed to verify CVC", err
@@ -193,44 +209,34 @@ func (obj *CStorVolumePatch) transformCV(c *cstor.CStorVolume, res *ResourcePatc
 
 func (obj *CStorVolumePatch) getCVDeployPatchData() error {
 	newDeploy := obj.Deploy.Object.DeepCopy()
-	err := obj.transformCVDeploy(newDeploy, obj.ResourcePatch)
+	skipped, err := obj.transformCVDeploy(newDeploy, obj.ResourcePatch)
 	if err != nil {
 		return err
 	}
+	obj.skippedContainers = skipped
+	obj.imageChecks = []imageCheck{deploymentImageCheck(obj.Deploy.Object, newDeploy)}
 	obj.Deploy.Data, err = GetPatchData(obj.Deploy.Object, newDeploy)
 	return err
 }
 
-func (obj *CStorVolumePatch) transformCVDeploy(d *appsv1.Deployment, res *ResourcePatch) error {
-	// update deployment images
-	tag := res.To
-	if res.ImageTag != "" {
-		tag = res.ImageTag
-	}
-	cons := len(d.Spec.Template.Spec.Containers)
-	for i := 0; i < cons; i++ {
-		url, err := getImageURL(
-			d.Spec.Template.Spec.Containers[i].Image,
-			res.BaseURL,
-		)
-		if err != nil {
-			return err
-		}
-		// remove the -amd64 prefix from the image
-		url = removeSuffixFromEnd(url, "-amd64")
-		d.Spec.Template.Spec.Containers[i].Image = url + ":" + tag
+func (obj *CStorVolumePatch) transformCVDeploy(d *appsv1.Deployment, res *ResourcePatch) ([]string, error) {
+	// update the images of the openebs containers
+	skipped, err := res.rewriteImages(&d.Spec.Template.Spec, cstorTargetContainers)
+	if err != nil {
+		return nil, err
 	}
+	logSkippedContainers("Deployment", d.Name, skipped)
 	pvObj, err := obj.KubeClientset.CoreV1().PersistentVolumes().
 		Get(context.TODO(), obj.Name, metav1.GetOptions{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	d.Labels["openebs.io/persistent-volume-claim"] = pvObj.Spec.ClaimRef.Name
 	d.Spec.Template.Labels["openebs.io/persistent-volume-claim"] = pvObj.Spec.ClaimRef.Name
 	d.Labels["openebs.io/version"] = res.To
 	d.Spec.Template.Labels["openebs.io/version"] = res.To
-	d.Spec.Template.Spec.ServiceAccountName = cstorOperatorServiceAccount
-	return nil
+	d.Spec.Template.Spec.ServiceAccountName = obj.ServiceAccount
+	return skipped, nil
 }
 
 func getCVServicePatchData(obj *CStorVolumePatch) error {
@@ -249,28 +255,32 @@ func transformCVService(svc *corev1.Service, res *ResourcePatch) error {
 }
 
 // CStorVolumeUpgrade ...
-func (obj *CStorVolumePatch) CStorVolumeUpgrade() (string, error) {
-	err := obj.Deploy.Patch(obj.From, obj.To)
+func (obj *CStorVolumePatch) CStorVolumeUpgrade(ctx context.Context) (string, error) {
+	err := obj.Deploy.Patch(ctx, obj.From, obj.To)
 	if err != nil {
 		return "failed to patch target deploy", err
 	}
-	err = obj.Service.Patch(obj.From, obj.To)
+	obj.recordPatch("Deployment", obj.Deploy.Object, obj.Deploy.Data)
+	err = obj.Service.Patch(ctx, obj.From, obj.To)
 	if err != nil {
 		return "failed to patch target svc", err
 	}
-	err = obj.CV.Patch(obj.From, obj.To)
+	obj.recordPatch("Service", obj.Service.Object, obj.Service.Data)
+	err = obj.CV.Patch(ctx, obj.From, obj.To)
 	if err != nil {
 		return "failed to patch CV", err
 	}
-	err = obj.verifyCVVersionReconcile()
+	obj.recordPatch("CStorVolume", obj.CV.Object, obj.CV.Data)
+	err = obj.verifyCVVersionReconcile(ctx)
 	if err != nil {
 		return "failed to verify version reconcile on CV", err
 	}
-	err = obj.CVC.Patch(obj.From, obj.To)
+	err = obj.CVC.Patch(ctx, obj.From, obj.To)
 	if err != nil {
 		return "failed to patch CVC", err
 	}
-	err = obj.verifyCVCVersionReconcile()
+	obj.recordPatch("CStorVolumeConfig", obj.CVC.Object, obj.CVC.Data)
+	err = obj.verifyCVCVersionReconcile(ctx)
 	if err != nil {
 		return "failed to verify version reconcile on CVC", err
 	}
@@ -280,18 +290,18 @@ func (obj *CStorVolumePatch) CStorVolumeUpgrade() (string, error) {
 // Upgrade execute the steps to upgrade CStorVolume
 func (obj *CStorVolumePatch) Upgrade() error {
 	var err, uerr error
-	obj.Utask, err = getOrCreateUpgradeTask(
+	obj.Utask, uerr = getOrCreateUpgradeTask(
 		"cstorVolume",
 		obj.ResourcePatch,
 		obj.Client,
 	)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
 	statusObj := v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.PreUpgrade}
 	statusObj.Phase = v1Alpha1API.StepWaiting
 	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
 	statusObj.Phase = v1Alpha1API.StepErrored
@@ -300,7 +310,7 @@ func (obj *CStorVolumePatch) Upgrade() error {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
 		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-		if uerr != nil && isUpgradeTaskJob {
+		if uerr != nil && obj.IsUpgradeTaskJob {
 			return uerr
 		}
 		return errors.Wrap(err, msg)
@@ -310,7 +320,7 @@ func (obj *CStorVolumePatch) Upgrade() error {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
 		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-		if uerr != nil && isUpgradeTaskJob {
+		if uerr != nil && obj.IsUpgradeTaskJob {
 			return uerr
 		}
 		return errors.Wrap(err, msg)
@@ -320,7 +330,27 @@ func (obj *CStorVolumePatch) Upgrade() error {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
 		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-		if uerr != nil && isUpgradeTaskJob {
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
+	}
+	msg, err = obj.checkImages(obj.KubeClientset, obj.imageChecks...)
+	if err != nil {
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
+	}
+	msg, err = obj.backup()
+	if err != nil {
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
 			return uerr
 		}
 		return errors.Wrap(err, msg)
@@ -329,35 +359,49 @@ func (obj *CStorVolumePatch) Upgrade() error {
 	statusObj.Message = "Pre-upgrade steps were successful"
 	statusObj.Reason = ""
 	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
 
 	statusObj = v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.ReplicaUpgrade}
 	statusObj.Phase = v1Alpha1API.StepWaiting
 	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
 	statusObj.Phase = v1Alpha1API.StepErrored
+	ctx, cancel := obj.stepContext()
+	defer cancel()
 	res := *obj.ResourcePatch
+	res.Context = ctx
 	cvrList, err := obj.Client.OpenebsClientset.CstorV1().
 		CStorVolumeReplicas(obj.Namespace).List(context.TODO(),
 		metav1.ListOptions{
 			LabelSelector: "openebs.io/persistent-volume=" + obj.Name,
 		},
 	)
-	if err != nil && isUpgradeTaskJob {
+	if err != nil && obj.IsUpgradeTaskJob {
 		msg = "failed to list cvrs for volume"
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
 		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-		if uerr != nil && isUpgradeTaskJob {
+		if uerr != nil && obj.IsUpgradeTaskJob {
 			return uerr
 		}
 		return errors.Wrap(err, msg)
 	}
 	for _, cvrObj := range cvrList.Items {
+		key := "cvr/" + cvrObj.Name
+		// revalidate the cvrs upgraded by a previous attempt
+		if isCompleted(obj.Utask, key) && cvrObj.VersionDetails.Status.Current == obj.To {
+			klog.Infof("cvr %s is already upgraded to %s, skipping", cvrObj.Name, obj.To)
+			continue
+		}
+		// the upgrade can be paused or cancelled between two replicas
+		obj.Utask, uerr = obj.checkControl(obj.Utask, obj.Client)
+		if uerr != nil && (obj.IsUpgradeTaskJob || IsCancelled(uerr)) {
+			return uerr
+		}
 		res.Name = cvrObj.Name
 		dependant := NewCVRPatch(
 			WithCVRResorcePatch(&res),
@@ -369,32 +413,42 @@ func (obj *CStorVolumePatch) Upgrade() error {
 			statusObj.Message = msg
 			statusObj.Reason = err.Error()
 			obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-			if uerr != nil && isUpgradeTaskJob {
+			if uerr != nil && obj.IsUpgradeTaskJob {
 				return uerr
 			}
 			return errors.Wrap(err, msg)
 		}
+		obj.Utask, uerr = markCompleted(obj.Utask, key, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
 	}
 	statusObj.Phase = v1Alpha1API.StepCompleted
 	statusObj.Message = "Replica upgrade was successful"
 	statusObj.Reason = ""
 	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+	obj.Utask, uerr = obj.checkControl(obj.Utask, obj.Client)
+	if uerr != nil && (obj.IsUpgradeTaskJob || IsCancelled(uerr)) {
 		return uerr
 	}
 	statusObj = v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.TargetUpgrade}
 	statusObj.Phase = v1Alpha1API.StepWaiting
 	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
 	statusObj.Phase = v1Alpha1API.StepErrored
-	msg, err = obj.CStorVolumeUpgrade()
+	ctx, cancel = obj.stepContext()
+	defer cancel()
+	msg, err = obj.CStorVolumeUpgrade(ctx)
 	if err != nil {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
 		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-		if uerr != nil && isUpgradeTaskJob {
+		if uerr != nil && obj.IsUpgradeTaskJob {
 			return uerr
 		}
 		return errors.Wrap(err, msg)
@@ -403,52 +457,199 @@ func (obj *CStorVolumePatch) Upgrade() error {
 	statusObj.Message = "Target upgrade was successful"
 	statusObj.Reason = ""
 	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
 	return nil
 }
 
-func (obj *CStorVolumePatch) verifyCVVersionReconcile() error {
-	// get the latest cvc object
-	err := obj.CV.Get(obj.Name, obj.Namespace)
+// DryRun returns the changes required to upgrade the CStorVolume
+func (obj *CStorVolumePatch) DryRun() ([]Change, error) {
+	msg, err := obj.Init()
 	if err != nil {
-		return err
+		return nil, errors.Wrap(err, msg)
+	}
+	msg, err = obj.PreUpgrade()
+	if err != nil {
+		return nil, errors.Wrap(err, msg)
+	}
+	msg, err = obj.GetVolumePatches()
+	if err != nil {
+		return nil, errors.Wrap(err, msg)
+	}
+	changes := []Change{}
+	res := *obj.ResourcePatch
+	cvrList, err := obj.Client.OpenebsClientset.CstorV1().
+		CStorVolumeReplicas(obj.Namespace).List(context.TODO(),
+		metav1.ListOptions{
+			LabelSelector: "openebs.io/persistent-volume=" + obj.Name,
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cvrs for volume")
 	}
-	// waiting for the current version to be equal to desired version
-	for obj.CV.Object.VersionDetails.Status.Current != obj.To {
-		klog.Infof("Verifying the reconciliation of version for %s", obj.CV.Object.Name)
-		// Sleep equal to the default sync time
-		time.Sleep(10 * time.Second)
-		err = obj.CV.Get(obj.Name, obj.Namespace)
+	for _, cvrObj := range cvrList.Items {
+		res.Name = cvrObj.Name
+		dependant := NewCVRPatch(
+			WithCVRResorcePatch(&res),
+			WithCVRClient(obj.Client),
+		)
+		cvrChanges, err := dependant.DryRun()
 		if err != nil {
-			return err
+			return nil, errors.Wrap(err, "failed to verify cvr "+cvrObj.Name)
 		}
-		if obj.CV.Object.VersionDetails.Status.Message != "" {
-			klog.Errorf("failed to reconcile: %s", obj.CV.Object.VersionDetails.Status.Reason)
+		changes = append(changes, cvrChanges...)
+	}
+	changes = appendChange(changes, "Deployment", obj.Deploy.Object, obj.Deploy.Data,
+		obj.skippedContainers...)
+	changes = appendChange(changes, "Service", obj.Service.Object, obj.Service.Data)
+	changes = appendChange(changes, "CStorVolume", obj.CV.Object, obj.CV.Data)
+	changes = appendChange(changes, "CStorVolumeConfig", obj.CVC.Object, obj.CVC.Data)
+	return changes, nil
+}
+
+func (obj *CStorVolumePatch) backup() (string, error) {
+	cvrList, err := obj.Client.OpenebsClientset.CstorV1().
+		CStorVolumeReplicas(obj.Namespace).List(context.TODO(),
+		metav1.ListOptions{
+			LabelSelector: "openebs.io/persistent-volume=" + obj.Name,
+		},
+	)
+	if err != nil {
+		return "failed to list cvrs for volume", err
+	}
+	objs := []backupObject{
+		{kind: "CStorVolumeConfig", obj: obj.CVC.Object.DeepCopy()},
+		{kind: "CStorVolume", obj: obj.CV.Object.DeepCopy()},
+		{kind: "Deployment", obj: obj.Deploy.Object.DeepCopy()},
+		{kind: "Service", obj: obj.Service.Object.DeepCopy()},
+	}
+	for i := range cvrList.Items {
+		objs = append(objs, backupObject{kind: "CStorVolumeReplica", obj: &cvrList.Items[i]})
+	}
+	err = saveBackup("cstorVolume", obj.ResourcePatch, obj.Client, objs...)
+	if err != nil {
+		return "failed to backup volume " + obj.Name, err
+	}
+	return "", nil
+}
+
+// Rollback restores the CStorVolume to the objects
+// recorded before the upgrade
+func (obj *CStorVolumePatch) Rollback() error {
+	cm, err := getBackup("cstorVolume", obj.ResourcePatch, obj.Client)
+	if err != nil {
+		return err
+	}
+	setRollbackVersions(obj.ResourcePatch, cm)
+	var uerr error
+	obj.Utask, uerr = getUpgradeTaskForRollback(
+		"cstorVolume",
+		obj.ResourcePatch,
+		obj.Client,
+	)
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+	statusObj := v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.Rollback}
+	statusObj.Phase = v1Alpha1API.StepWaiting
+	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+	statusObj.Phase = v1Alpha1API.StepErrored
+	ctx, cancel := obj.stepContext()
+	defer cancel()
+	msg, err := obj.rollback(ctx, cm)
+	if err != nil {
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
 		}
+		return errors.Wrap(err, msg)
+	}
+	statusObj.Phase = v1Alpha1API.StepCompleted
+	statusObj.Message = "Rollback was successful"
+	statusObj.Reason = ""
+	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
 	}
 	return nil
 }
 
-func (obj *CStorVolumePatch) verifyCVCVersionReconcile() error {
-	// get the latest cvc object
-	err := obj.CVC.Get(obj.Name, obj.Namespace)
+func (obj *CStorVolumePatch) rollback(ctx context.Context, cm *corev1.ConfigMap) (string, error) {
+	msg, err := obj.Init()
 	if err != nil {
-		return err
+		return msg, err
 	}
-	// waiting for the current version to be equal to desired version
-	for obj.CVC.Object.VersionDetails.Status.Current != obj.To {
-		klog.Infof("Verifying the reconciliation of version for %s", obj.CVC.Object.Name)
-		// Sleep equal to the default sync time
-		time.Sleep(10 * time.Second)
-		err = obj.CVC.Get(obj.Name, obj.Namespace)
+	err = rollbackCVC(ctx, obj.CVC, cm)
+	if err != nil {
+		return "failed to rollback CVC", err
+	}
+	err = rollbackCV(ctx, obj.CV, cm)
+	if err != nil {
+		return "failed to rollback CV", err
+	}
+	err = rollbackService(ctx, obj.Service, cm)
+	if err != nil {
+		return "failed to rollback target svc", err
+	}
+	err = rollbackDeployment(ctx, obj.Deploy, cm)
+	if err != nil {
+		return "failed to rollback target deploy", err
+	}
+	cvrList, err := obj.Client.OpenebsClientset.CstorV1().
+		CStorVolumeReplicas(obj.Namespace).List(context.TODO(),
+		metav1.ListOptions{
+			LabelSelector: "openebs.io/persistent-volume=" + obj.Name,
+		},
+	)
+	if err != nil {
+		return "failed to list cvrs for volume", err
+	}
+	for _, cvrObj := range cvrList.Items {
+		cvr := patch.NewCVR(
+			patch.WithCVRClient(obj.OpenebsClientset),
+		)
+		err = cvr.Get(cvrObj.Name, obj.Namespace)
 		if err != nil {
-			return err
+			return "failed to get cvr " + cvrObj.Name, err
 		}
-		if obj.CVC.Object.VersionDetails.Status.Message != "" {
-			klog.Errorf("failed to reconcile: %s", obj.CVC.Object.VersionDetails.Status.Reason)
+		err = rollbackCVR(ctx, cvr, cm)
+		if err != nil {
+			return "failed to rollback cvr " + cvrObj.Name, err
 		}
 	}
-	return nil
+	return "", nil
+}
+
+func (obj *CStorVolumePatch) verifyCVVersionReconcile(ctx context.Context) error {
+	return waitForVersionReconcile(ctx, obj.Name, obj.To,
+		func() (versionStatus, error) {
+			// get the latest object
+			err := obj.CV.Get(obj.Name, obj.Namespace)
+			if err != nil {
+				return versionStatus{}, err
+			}
+			s := obj.CV.Object.VersionDetails.Status
+			return versionStatus{s.Current, s.Message, s.Reason}, nil
+		},
+	)
+}
+
+func (obj *CStorVolumePatch) verifyCVCVersionReconcile(ctx context.Context) error {
+	return waitForVersionReconcile(ctx, obj.Name, obj.To,
+		func() (versionStatus, error) {
+			// get the latest object
+			err := obj.CVC.Get(obj.Name, obj.Namespace)
+			if err != nil {
+				return versionStatus{}, err
+			}
+			s := obj.CVC.Object.VersionDetails.Status
+			return versionStatus{s.Current, s.Message, s.Reason}, nil
+		},
+	)
 }
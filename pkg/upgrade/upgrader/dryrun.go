@@ -0,0 +1,60 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Change is the patch that would be applied to an object
+// during the upgrade
+type Change struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Patch     []byte
+	// SkippedContainers are the containers left
+	// untouched as they are not managed by openebs
+	SkippedContainers []string
+}
+
+// recordPatch passes the patch applied to the given object to the
+// patch recorder, objects that did not require any change are skipped
+func (r *ResourcePatch) recordPatch(kind string, obj metav1.Object, data []byte) {
+	if r.PatchRecorder == nil {
+		return
+	}
+	for _, c := range appendChange(nil, kind, obj, data) {
+		r.PatchRecorder(c)
+	}
+}
+
+// appendChange adds the patch for the given object to the list
+// of changes, objects that do not require any change are skipped
+func appendChange(changes []Change, kind string, obj metav1.Object, data []byte,
+	skippedContainers ...string) []Change {
+	if len(data) == 0 || string(data) == "{}" {
+		return changes
+	}
+	return append(changes, Change{
+		Kind:              kind,
+		Namespace:         obj.GetNamespace(),
+		Name:              obj.GetName(),
+		Patch:             data,
+		SkippedContainers: skippedContainers,
+	})
+}
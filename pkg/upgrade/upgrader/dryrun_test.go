@@ -0,0 +1,266 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"reflect"
+	"testing"
+
+	cstor "github.com/openebs/api/v3/pkg/apis/cstor/v1"
+	"github.com/openebs/api/v3/pkg/apis/types"
+	openebsFakeClientset "github.com/openebs/api/v3/pkg/client/clientset/versioned/fake"
+	jv "github.com/openebs/jiva-operator/pkg/apis/openebs/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openebs/upgrade/pkg/kubeclient"
+)
+
+func Test_appendChange(t *testing.T) {
+	obj := &metav1.ObjectMeta{Name: "pv-1-target", Namespace: "openebs"}
+	tests := map[string]struct {
+		data    []byte
+		skipped []string
+		want    []Change
+	}{
+		"no patch": {
+			data: nil,
+			want: nil,
+		},
+		"empty patch": {
+			data: []byte("{}"),
+			want: nil,
+		},
+		"patch": {
+			data:    []byte(`{"metadata":{"labels":{"openebs.io/version":"3.5.0"}}}`),
+			skipped: []string{"istio-proxy"},
+			want: []Change{
+				{
+					Kind:              "Deployment",
+					Namespace:         "openebs",
+					Name:              "pv-1-target",
+					Patch:             []byte(`{"metadata":{"labels":{"openebs.io/version":"3.5.0"}}}`),
+					SkippedContainers: []string{"istio-proxy"},
+				},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := appendChange(nil, "Deployment", obj, test.data, test.skipped...)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("appendChange() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// changeKeys returns the kind and name of the changes
+func changeKeys(changes []Change) []string {
+	keys := []string{}
+	for _, c := range changes {
+		keys = append(keys, c.Kind+"/"+c.Name)
+	}
+	return keys
+}
+
+func testOperatorPod(component, version string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      component + "-0",
+			Namespace: "openebs",
+			Labels: map[string]string{
+				"openebs.io/component-name": component,
+				"openebs.io/version":        version,
+			},
+		},
+	}
+}
+
+func testDryRunCVR(cspi, version string) *cstor.CStorVolumeReplica {
+	cvr := testCVR("pv-1", cspi, cstor.CVRStatusOnline)
+	cvr.Labels["openebs.io/version"] = version
+	cvr.VersionDetails.Desired = version
+	cvr.VersionDetails.Status.Current = version
+	return cvr
+}
+
+func TestCStorVolumePatch_DryRun(t *testing.T) {
+	labels := func() map[string]string {
+		return map[string]string{
+			types.PersistentVolumeLabelKey: "pv-1",
+			"openebs.io/version":           "3.4.0",
+		}
+	}
+	cvc := &cstor.CStorVolumeConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pv-1",
+			Namespace:   "openebs",
+			Annotations: map[string]string{"openebs.io/persistent-volume-claim": "pvc-1"},
+		},
+	}
+	cvc.VersionDetails.Desired = "3.4.0"
+	cvc.VersionDetails.Status.Current = "3.4.0"
+	cv := testCV("pv-1", cstor.CVStatusHealthy)
+	cv.Labels = map[string]string{"openebs.io/persistent-volume-claim": "pvc-1"}
+	cv.VersionDetails.Desired = "3.4.0"
+	cv.VersionDetails.Status.Current = "3.4.0"
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec: corev1.PersistentVolumeSpec{
+			ClaimRef: &corev1.ObjectReference{Name: "pvc-1"},
+		},
+	}
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1-target", Namespace: "openebs", Labels: labels()},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels()},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: "openebs-cstor-operator",
+					Containers: []corev1.Container{
+						{Name: "cstor-istgt", Image: "openebs/cstor-istgt:3.4.0"},
+					},
+				},
+			},
+		},
+	}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1", Namespace: "openebs", Labels: labels()},
+	}
+	obj := NewCStorVolumePatch(
+		WithCStorVolumeResorcePatch(&ResourcePatch{
+			Name:             "pv-1",
+			OpenebsNamespace: "openebs",
+			From:             "3.4.0",
+			To:               "3.5.0",
+		}),
+		WithCStorVolumeClient(&Client{
+			KubeClientset: fake.NewSimpleClientset(
+				testOperatorPod("cvc-operator", "3.5.0"), pv, deploy, svc,
+			),
+			OpenebsClientset: openebsFakeClientset.NewSimpleClientset(
+				cvc, cv,
+				testDryRunCVR("cspi-a", "3.4.0"),
+				testDryRunCVR("cspi-b", "3.5.0"),
+				testDryRunCVR("cspi-c", "3.4.0"),
+			),
+		}),
+	)
+	changes, err := obj.DryRun()
+	if err != nil {
+		t.Fatalf("DryRun() error = %v", err)
+	}
+	// the cvr already upgraded has an empty patch
+	want := []string{
+		"CStorVolumeReplica/pv-1-cspi-a",
+		"CStorVolumeReplica/pv-1-cspi-c",
+		"Deployment/pv-1-target",
+		"Service/pv-1",
+		"CStorVolume/pv-1",
+		"CStorVolumeConfig/pv-1",
+	}
+	if got := changeKeys(changes); !reflect.DeepEqual(got, want) {
+		t.Errorf("DryRun() changes = %v, want %v", got, want)
+	}
+}
+
+func TestJivaVolumePatch_DryRun(t *testing.T) {
+	labels := func(component string) map[string]string {
+		return map[string]string{
+			"openebs.io/component":         component,
+			types.PersistentVolumeLabelKey: "pv-1",
+			"openebs.io/version":           "3.4.0",
+		}
+	}
+	controller := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1-jiva-ctrl", Namespace: "openebs",
+			Labels: labels("jiva-controller")},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels("jiva-controller")},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "jiva-controller", Image: "openebs/jiva:3.4.0"},
+						{Name: "istio-proxy", Image: "docker.io/istio/proxyv2:1.20.0"},
+					},
+				},
+			},
+		},
+	}
+	replicas := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1-jiva-rep", Namespace: "openebs",
+			Labels: labels("jiva-replica")},
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels("jiva-replica")},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "jiva-replica", Image: "openebs/jiva:3.4.0"},
+					},
+				},
+			},
+		},
+	}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1-jiva-ctrl-svc", Namespace: "openebs",
+			Labels: labels("jiva-controller-service")},
+	}
+	volume := &jv.JivaVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1", Namespace: "openebs"},
+	}
+	volume.VersionDetails.Desired = "3.4.0"
+	volume.VersionDetails.Status.Current = "3.4.0"
+	obj := NewJivaVolumePatch(
+		WithJivaVolumeResorcePatch(&ResourcePatch{
+			Name:             "pv-1",
+			OpenebsNamespace: "openebs",
+			From:             "3.4.0",
+			To:               "3.5.0",
+		}),
+		WithJivaVolumeClient(&Client{
+			KubeClientset: fake.NewSimpleClientset(
+				testOperatorPod("jiva-operator", "3.5.0"), controller, replicas, svc,
+			),
+			RuntimeClient: ctrlfake.NewClientBuilder().WithScheme(kubeclient.Scheme()).
+				WithObjects(volume).Build(),
+		}),
+	)
+	changes, err := obj.DryRun()
+	if err != nil {
+		t.Fatalf("DryRun() error = %v", err)
+	}
+	want := []string{
+		"StatefulSet/pv-1-jiva-rep",
+		"Deployment/pv-1-jiva-ctrl",
+		"Service/pv-1-jiva-ctrl-svc",
+		"JivaVolume/pv-1",
+	}
+	if got := changeKeys(changes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("DryRun() changes = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(changes[1].SkippedContainers, []string{"istio-proxy"}) {
+		t.Errorf("DryRun() skipped containers = %v, want [istio-proxy]", changes[1].SkippedContainers)
+	}
+	wantJV := `{"versionDetails":{"desired":"3.5.0"}}`
+	if string(changes[3].Patch) != wantJV {
+		t.Errorf("DryRun() jivavolume patch = %s, want %s", changes[3].Patch, wantJV)
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"context"
+	"fmt"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	jv "github.com/openebs/jiva-operator/pkg/apis/openebs/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openebs/upgrade/pkg/events"
+)
+
+// upgradeStepReasons are the reasons of the events emitted when a
+// step is started and completed, a failed step emits UpgradeFailed
+var upgradeStepReasons = map[v1Alpha1API.UpgradeStep][2]string{
+	v1Alpha1API.PreUpgrade:          {events.UpgradeStarted, events.PreUpgradeCompleted},
+	v1Alpha1API.ReplicaUpgrade:      {events.ReplicaUpgradeStarted, events.ReplicaUpgraded},
+	v1Alpha1API.TargetUpgrade:       {events.TargetUpgradeStarted, events.TargetPatched},
+	v1Alpha1API.PoolInstanceUpgrade: {events.PoolInstanceUpgradeStarted, events.PoolInstanceUpgraded},
+	v1Alpha1API.Verify:              {events.VerifyStarted, events.UpgradeVerified},
+	v1Alpha1API.Rollback:            {events.RollbackStarted, events.RolledBack},
+	Paused:                          {events.UpgradePaused, events.UpgradeResumed},
+	MaintenanceWindow:               {events.MaintenanceWindowWaiting, events.MaintenanceWindowOpened},
+}
+
+// upgradeEvent returns the type, reason and message of
+// the event for the given status of an upgrade step
+func upgradeEvent(utaskObj *v1Alpha1API.UpgradeTask,
+	status v1Alpha1API.UpgradeDetailedStatuses) (string, string, string) {
+	reasons := upgradeStepReasons[status.Step]
+	switch status.Phase {
+	case v1Alpha1API.StepWaiting:
+		if status.Step == v1Alpha1API.PreUpgrade {
+			return corev1.EventTypeNormal, reasons[0], fmt.Sprintf("Upgrading from %s to %s",
+				utaskObj.Spec.FromVersion, utaskObj.Spec.ToVersion)
+		}
+		if status.Step == Paused || status.Step == MaintenanceWindow {
+			return corev1.EventTypeNormal, reasons[0], status.Message
+		}
+		return corev1.EventTypeNormal, reasons[0], fmt.Sprintf("Started step %s of the upgrade to %s",
+			status.Step, utaskObj.Spec.ToVersion)
+	case v1Alpha1API.StepErrored:
+		reason := events.UpgradeFailed
+		if status.Step == v1Alpha1API.Rollback {
+			reason = events.RollbackFailed
+		}
+		return corev1.EventTypeWarning, reason, fmt.Sprintf("Step %s failed: %s: %s",
+			status.Step, status.Message, status.Reason)
+	}
+	return corev1.EventTypeNormal, reasons[1], status.Message
+}
+
+// recordUpgradeEvent emits the event for the status of an upgrade step on
+// the UpgradeTask and on the resource being upgraded, so that describing
+// the PV, CSPI or CSPC shows when it was touched
+func recordUpgradeEvent(utaskObj *v1Alpha1API.UpgradeTask,
+	status v1Alpha1API.UpgradeDetailedStatuses, client *Client) {
+	if client.Recorder == nil || utaskObj == nil {
+		return
+	}
+	eventtype, reason, message := upgradeEvent(utaskObj, status)
+	client.Recorder.Event(utaskObj, eventtype, reason, message)
+	for _, obj := range upgradedObjects(utaskObj, client) {
+		client.Recorder.Event(obj, eventtype, reason, message)
+	}
+}
+
+// upgradedObjects returns the objects upgraded by the UpgradeTask,
+// the objects that cannot be fetched are skipped
+func upgradedObjects(utaskObj *v1Alpha1API.UpgradeTask, c *Client) []runtime.Object {
+	getters := []func() (runtime.Object, error){}
+	spec := utaskObj.Spec.ResourceSpec
+	switch {
+	case spec.CStorVolume != nil:
+		getters = append(getters, func() (runtime.Object, error) {
+			return c.KubeClientset.CoreV1().PersistentVolumes().
+				Get(context.TODO(), spec.CStorVolume.PVName, metav1.GetOptions{})
+		})
+	case spec.JivaVolume != nil:
+		getters = append(getters, func() (runtime.Object, error) {
+			return c.KubeClientset.CoreV1().PersistentVolumes().
+				Get(context.TODO(), spec.JivaVolume.PVName, metav1.GetOptions{})
+		}, func() (runtime.Object, error) {
+			obj := &jv.JivaVolume{}
+			err := c.RuntimeClient.Get(context.TODO(), client.ObjectKey{
+				Namespace: utaskObj.Namespace,
+				Name:      spec.JivaVolume.PVName,
+			}, obj)
+			return obj, err
+		})
+	case spec.CStorPoolInstance != nil:
+		getters = append(getters, func() (runtime.Object, error) {
+			return c.OpenebsClientset.CstorV1().CStorPoolInstances(utaskObj.Namespace).
+				Get(context.TODO(), spec.CStorPoolInstance.CSPIName, metav1.GetOptions{})
+		})
+	case spec.CStorPoolCluster != nil:
+		getters = append(getters, func() (runtime.Object, error) {
+			return c.OpenebsClientset.CstorV1().CStorPoolClusters(utaskObj.Namespace).
+				Get(context.TODO(), spec.CStorPoolCluster.CSPCName, metav1.GetOptions{})
+		})
+	}
+	objs := []runtime.Object{}
+	for _, get := range getters {
+		obj, err := get()
+		if err != nil {
+			klog.Errorf("failed to get the object upgraded by %s for events: %v", utaskObj.Name, err)
+			continue
+		}
+		objs = append(objs, obj)
+	}
+	return objs
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"reflect"
+	"testing"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	openebsFakeClientset "github.com/openebs/api/v3/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func stepStatus(step v1Alpha1API.UpgradeStep, phase v1Alpha1API.StepPhase,
+	message, reason string) v1Alpha1API.UpgradeDetailedStatuses {
+	status := v1Alpha1API.UpgradeDetailedStatuses{Step: step}
+	status.Phase = phase
+	status.Message = message
+	status.Reason = reason
+	return status
+}
+
+func Test_recordUpgradeEvent(t *testing.T) {
+	r := &ResourcePatch{
+		Name:             "pvc-1",
+		OpenebsNamespace: "openebs",
+		From:             "3.4.0",
+		To:               "3.5.0",
+	}
+	utask := buildUpgradeTask("cstorVolume", r)
+	pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"}}
+	tests := []struct {
+		name   string
+		status v1Alpha1API.UpgradeDetailedStatuses
+		want   string
+	}{
+		{
+			name: "upgrade started",
+			status: stepStatus(v1Alpha1API.PreUpgrade, v1Alpha1API.StepWaiting,
+				"", ""),
+			want: "Normal UpgradeStarted Upgrading from 3.4.0 to 3.5.0",
+		},
+		{
+			name: "replicas upgraded",
+			status: stepStatus(v1Alpha1API.ReplicaUpgrade, v1Alpha1API.StepCompleted,
+				"Replica upgrade was successful", ""),
+			want: "Normal ReplicaUpgraded Replica upgrade was successful",
+		},
+		{
+			name: "target failed",
+			status: stepStatus(v1Alpha1API.TargetUpgrade, v1Alpha1API.StepErrored,
+				"failed to patch target deploy", "timed out"),
+			want: "Warning UpgradeFailed Step TARGET_UPGRADE failed: failed to patch target deploy: timed out",
+		},
+		{
+			name: "rollback failed",
+			status: stepStatus(v1Alpha1API.Rollback, v1Alpha1API.StepErrored,
+				"failed to rollback CVC", "not found"),
+			want: "Warning RollbackFailed Step ROLLBACK failed: failed to rollback CVC: not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(4)
+			client := &Client{
+				KubeClientset:    fake.NewSimpleClientset(pv),
+				OpenebsClientset: openebsFakeClientset.NewSimpleClientset(),
+				Recorder:         recorder,
+			}
+			recordUpgradeEvent(utask, tt.status, client)
+			close(recorder.Events)
+			got := []string{}
+			for e := range recorder.Events {
+				got = append(got, e)
+			}
+			// the event is emitted on the UpgradeTask and the PV
+			want := []string{tt.want, tt.want}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("recordUpgradeEvent() = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -21,36 +21,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/client-go/kubernetes"
-)
+	"k8s.io/klog/v2"
 
-var (
-	cstorOperatorServiceAccount = "openebs-cstor-operator"
+	"github.com/openebs/upgrade/pkg/upgrade/wait"
 )
 
-func getImageURL(url, prefix string) (string, error) {
-	lastIndex := strings.LastIndex(url, ":")
-	if lastIndex == -1 {
-		return "", errors.Errorf("no version tag found on image %s", url)
-	}
-	baseImage := url[:lastIndex]
-	if prefix != "" {
-		// urlPrefix is the url to the directory where the images are present
-		// the below logic takes the image name from current baseImage and
-		// appends it to the given urlPrefix
-		// For example baseImage is abc/quay.io/openebs/jiva
-		// and urlPrefix is xyz/aws-56546546/openebsdirectory/
-		// it will take jiva from current url and append it to urlPrefix
-		// and return xyz/aws-56546546/openebsdirectory/jiva
-		urlSubstr := strings.Split(baseImage, "/")
-		baseImage = prefix + urlSubstr[len(urlSubstr)-1]
-	}
-	return baseImage, nil
-}
+const (
+	// defaultCStorOperatorServiceAccount is used for the cstor pool
+	// and target deployments if the operator pods cannot be found
+	defaultCStorOperatorServiceAccount = "openebs-cstor-operator"
+)
 
 // GetPatchData returns patch data by
 // marshalling and taking diff of two objects
@@ -89,23 +75,68 @@ func isOperatorUpgraded(componentName string, namespace string,
 				componentName, pod.Labels["openebs.io/version"], toVersion)
 		}
 	}
-	if componentName == "cspc-operator" || componentName == "cvc-operator" {
-		cstorOperatorServiceAccount = operatorPods.Items[0].Spec.ServiceAccountName
-	}
 	return nil
 }
 
-// Remove the suffix only if it is present
-// at the end of the string
-func removeSuffixFromEnd(str, suffix string) string {
-	slice := strings.Split(str, "/")
-	// if the image is from openebs registry and has a suffix -amd64
-	// then only perform the operation
-	if slice[len(slice)-2] == "openebs" && strings.HasSuffix(slice[len(slice)-1], "-amd64") {
-		i := strings.LastIndex(str, suffix)
-		if i > -1 && i == len(str)-len(suffix) {
-			str = str[:i]
-		}
+// getOperatorServiceAccount returns the service account used by the
+// given operator so that the deployments it manages run with the same one
+func getOperatorServiceAccount(componentName string, namespace string,
+	kubeClient kubernetes.Interface) (string, error) {
+	operatorPods, err := kubeClient.CoreV1().
+		Pods(namespace).
+		List(context.TODO(), metav1.ListOptions{
+			LabelSelector: "openebs.io/component-name=" + componentName,
+		})
+	if err != nil {
+		return "", err
+	}
+	if len(operatorPods.Items) == 0 ||
+		operatorPods.Items[0].Spec.ServiceAccountName == "" {
+		return defaultCStorOperatorServiceAccount, nil
+	}
+	return operatorPods.Items[0].Spec.ServiceAccountName, nil
+}
+
+// versionStatus is the version reported by a resource
+// along with the details of a failed reconciliation
+type versionStatus struct {
+	current, message, reason string
+}
+
+// waitForVersionReconcile waits for the current version of the resource to be
+// equal to the desired version. It fails as soon as the resource reports an
+// error while reconciling the version.
+func waitForVersionReconcile(ctx context.Context, name, to string,
+	get func() (versionStatus, error)) error {
+	status, err := get()
+	if err != nil {
+		return err
+	}
+	if status.current == to {
+		return nil
 	}
-	return str
+	// Poll at the default sync time
+	return wait.For(ctx, "version reconciliation of "+name, 10*time.Second, 0,
+		func(ctx context.Context) (bool, error) {
+			klog.Infof("Verifying the reconciliation of version for %s", name)
+			status, err := get()
+			if err != nil {
+				return false, err
+			}
+			if status.current == to {
+				return true, nil
+			}
+			if status.message != "" || status.reason != "" {
+				details := []string{}
+				for _, s := range []string{status.message, status.reason} {
+					if s != "" {
+						details = append(details, s)
+					}
+				}
+				return false, errors.Errorf("failed to reconcile version for %s: %s",
+					name, strings.Join(details, ": "))
+			}
+			return false, nil
+		},
+	)
 }
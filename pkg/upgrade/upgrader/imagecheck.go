@@ -0,0 +1,283 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/openebs/upgrade/pkg/upgrade/wait"
+)
+
+const (
+	// imageCheckLabel is set on the probe pods to the uid
+	// of the workload whose images are being checked
+	imageCheckLabel = "openebs.io/upgrade-image-check"
+)
+
+// imagePullFailures are the waiting reasons of a
+// container whose image cannot be pulled
+var imagePullFailures = map[string]bool{
+	"ErrImagePull":      true,
+	"ImagePullBackOff":  true,
+	"InvalidImageName":  true,
+	"ErrImageNeverPull": true,
+}
+
+// imageCheck is a workload whose new images
+// need to be pulled on the nodes of its pods
+type imageCheck struct {
+	kind string
+	obj  metav1.Object
+	// selector selects the current pods of the workload
+	selector *metav1.LabelSelector
+	// old and new are the pod specs before and after the upgrade
+	old, new *corev1.PodSpec
+}
+
+func deploymentImageCheck(old, new *appsv1.Deployment) imageCheck {
+	return imageCheck{
+		kind:     "Deployment",
+		obj:      old,
+		selector: old.Spec.Selector,
+		old:      &old.Spec.Template.Spec,
+		new:      &new.Spec.Template.Spec,
+	}
+}
+
+func statefulSetImageCheck(old, new *appsv1.StatefulSet) imageCheck {
+	return imageCheck{
+		kind:     "StatefulSet",
+		obj:      old,
+		selector: old.Spec.Selector,
+		old:      &old.Spec.Template.Spec,
+		new:      &new.Spec.Template.Spec,
+	}
+}
+
+// changedImages returns the images of the new spec that differ
+// from the images of the same containers in the old spec
+func (c imageCheck) changedImages() []string {
+	current := map[string]string{}
+	for _, container := range c.old.Containers {
+		current[container.Name] = container.Image
+	}
+	images := []string{}
+	seen := map[string]bool{}
+	for _, container := range c.new.Containers {
+		if current[container.Name] == container.Image || seen[container.Image] {
+			continue
+		}
+		seen[container.Image] = true
+		images = append(images, container.Image)
+	}
+	return images
+}
+
+// verifyImagesPullable pulls the new images of the workloads on the nodes
+// running their pods with a short-lived probe pod per node, so that a
+// missing image fails the upgrade before any workload is patched. The
+// probe pods are deleted once the check is over.
+func verifyImagesPullable(ctx context.Context, client kubernetes.Interface,
+	timeout time.Duration, checks ...imageCheck) error {
+	for _, c := range checks {
+		images := c.changedImages()
+		if len(images) == 0 {
+			continue
+		}
+		nodes, err := workloadNodes(ctx, client, c)
+		if err != nil {
+			return err
+		}
+		if len(nodes) == 0 {
+			klog.Infof("No pods found for %s %s, skipping the image pull check", c.kind, c.obj.GetName())
+			continue
+		}
+		err = runImageProbes(ctx, client, timeout, c, images, nodes)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// workloadNodes returns the nodes running the pods of the workload
+func workloadNodes(ctx context.Context, client kubernetes.Interface, c imageCheck) ([]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(c.selector)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid selector for %s %s", c.kind, c.obj.GetName())
+	}
+	pods, err := client.CoreV1().Pods(c.obj.GetNamespace()).List(ctx,
+		metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list pods of %s %s", c.kind, c.obj.GetName())
+	}
+	nodes := []string{}
+	seen := map[string]bool{}
+	for _, p := range pods.Items {
+		if p.Spec.NodeName == "" || seen[p.Spec.NodeName] {
+			continue
+		}
+		seen[p.Spec.NodeName] = true
+		nodes = append(nodes, p.Spec.NodeName)
+	}
+	sort.Strings(nodes)
+	return nodes, nil
+}
+
+func runImageProbes(ctx context.Context, client kubernetes.Interface, timeout time.Duration,
+	c imageCheck, images, nodes []string) error {
+	pods := client.CoreV1().Pods(c.obj.GetNamespace())
+	label := imageCheckLabel + "=" + string(c.obj.GetUID())
+	// the probe pods are removed even if the upgrade was cancelled
+	defer deleteImageProbes(client, c.obj.GetNamespace(), label)
+	// remove the probe pods left behind by an interrupted check
+	deleteImageProbes(client, c.obj.GetNamespace(), label)
+	names := []string{}
+	for _, node := range nodes {
+		probe, err := pods.Create(ctx, newImageProbe(c, images, node), metav1.CreateOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to create image pull probe for %s %s on node %s",
+				c.kind, c.obj.GetName(), node)
+		}
+		names = append(names, probe.Name)
+	}
+	klog.Infof("Verifying that the images %s of %s %s can be pulled on the nodes %s",
+		strings.Join(images, ", "), c.kind, c.obj.GetName(), strings.Join(nodes, ", "))
+	return wait.For(ctx, "image pull on the nodes of "+c.obj.GetName(), 2*time.Second, timeout,
+		func(ctx context.Context) (bool, error) {
+			for _, name := range names {
+				p, err := pods.Get(ctx, name, metav1.GetOptions{})
+				if err != nil {
+					return false, err
+				}
+				pulled, err := imageProbeStatus(p)
+				if err != nil || !pulled {
+					return false, err
+				}
+			}
+			return true, nil
+		},
+	)
+}
+
+// newImageProbe returns a pod running a no-op command with the new
+// images on the given node. The pull secrets, service account and
+// tolerations of the workload are kept so that the images are pulled
+// the same way as after the upgrade.
+func newImageProbe(c imageCheck, images []string, node string) *corev1.Pod {
+	automount := false
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%.40s-image-check-", c.obj.GetName()),
+			Namespace:    c.obj.GetNamespace(),
+			Labels: map[string]string{
+				imageCheckLabel: string(c.obj.GetUID()),
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:                     node,
+			RestartPolicy:                corev1.RestartPolicyNever,
+			ServiceAccountName:           c.new.ServiceAccountName,
+			ImagePullSecrets:             c.new.ImagePullSecrets,
+			Tolerations:                  c.new.Tolerations,
+			AutomountServiceAccountToken: &automount,
+		},
+	}
+	for i, image := range images {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{
+			Name:            fmt.Sprintf("image-%d", i),
+			Image:           image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{"sh", "-c", "exit 0"},
+		})
+	}
+	return pod
+}
+
+// imageProbeStatus returns true once the images of all the containers of
+// the probe are pulled. The containers may fail to run the command, for
+// example if the image has no shell, which does not affect the check.
+func imageProbeStatus(p *corev1.Pod) (bool, error) {
+	for _, s := range p.Status.ContainerStatuses {
+		if s.State.Waiting != nil && imagePullFailures[s.State.Waiting.Reason] {
+			return false, errors.Errorf("failed to pull image %s on node %s: %s",
+				s.Image, p.Spec.NodeName, s.State.Waiting.Message)
+		}
+	}
+	if p.Status.Phase == corev1.PodFailed && len(p.Status.ContainerStatuses) == 0 {
+		return false, errors.Errorf("image pull probe %s failed on node %s: %s %s",
+			p.Name, p.Spec.NodeName, p.Status.Reason, p.Status.Message)
+	}
+	if len(p.Status.ContainerStatuses) != len(p.Spec.Containers) {
+		return false, nil
+	}
+	for _, s := range p.Status.ContainerStatuses {
+		// the other waiting reasons are reported after the pull
+		if s.State.Waiting != nil && (s.State.Waiting.Reason == "" ||
+			s.State.Waiting.Reason == "ContainerCreating") {
+			return false, nil
+		}
+		if s.State.Waiting == nil && s.State.Running == nil && s.State.Terminated == nil {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func deleteImageProbes(client kubernetes.Interface, namespace, label string) {
+	var grace int64
+	pods, err := client.CoreV1().Pods(namespace).List(context.TODO(),
+		metav1.ListOptions{LabelSelector: label})
+	if err != nil {
+		klog.Errorf("failed to list the image pull probes %s: %v", label, err)
+		return
+	}
+	for _, p := range pods.Items {
+		err = client.CoreV1().Pods(namespace).Delete(context.TODO(), p.Name,
+			metav1.DeleteOptions{GracePeriodSeconds: &grace})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			klog.Errorf("failed to delete the image pull probe %s: %v", p.Name, err)
+		}
+	}
+}
+
+// checkImages verifies that the new images of the workloads
+// can be pulled, if the image pull check is enabled
+func (r *ResourcePatch) checkImages(client kubernetes.Interface, checks ...imageCheck) (string, error) {
+	if !r.ImagePullCheck {
+		return "", nil
+	}
+	ctx, cancel := r.stepContext()
+	defer cancel()
+	err := verifyImagesPullable(ctx, client, r.ImagePullTimeout, checks...)
+	if err != nil {
+		return "failed to verify the new images can be pulled", err
+	}
+	return "", nil
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	cstor "github.com/openebs/api/v3/pkg/apis/cstor/v1"
+	openebsFakeClientset "github.com/openebs/api/v3/pkg/client/clientset/versioned/fake"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func testPoolDeployment(image string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cspc-stripe-k7cc",
+			Namespace: "openebs",
+			UID:       types.UID("deploy-uid"),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "cstor-pool"},
+			},
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "cstor-pool", Image: image},
+						{Name: "cstor-pool-mgmt", Image: "openebs/cstor-pool-manager:3.4.0"},
+						{Name: "istio-proxy", Image: "istio/proxyv2:1.18.0"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_imageCheck_changedImages(t *testing.T) {
+	old := testPoolDeployment("openebs/cstor-pool:3.4.0")
+	new := old.DeepCopy()
+	new.Spec.Template.Spec.Containers[0].Image = "openebs/cstor-pool:3.5.0"
+	new.Spec.Template.Spec.Containers[1].Image = "openebs/cstor-pool-manager:3.5.0"
+	got := deploymentImageCheck(old, new).changedImages()
+	want := []string{"openebs/cstor-pool:3.5.0", "openebs/cstor-pool-manager:3.5.0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changedImages() = %v, want %v", got, want)
+	}
+	if got := deploymentImageCheck(old, old).changedImages(); len(got) != 0 {
+		t.Errorf("changedImages() = %v, want no images", got)
+	}
+}
+
+func Test_imageProbeStatus(t *testing.T) {
+	probe := func(phase corev1.PodPhase, states ...corev1.ContainerState) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "probe"},
+			Spec: corev1.PodSpec{
+				NodeName:   "node-1",
+				Containers: []corev1.Container{{Name: "image-0"}, {Name: "image-1"}},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+		for _, s := range states {
+			p.Status.ContainerStatuses = append(p.Status.ContainerStatuses,
+				corev1.ContainerStatus{State: s})
+		}
+		return p
+	}
+	waiting := func(reason string) corev1.ContainerState {
+		return corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}}
+	}
+	terminated := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 127}}
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		wantPulled bool
+		wantErr    bool
+	}{
+		{
+			name: "not started",
+			pod:  probe(corev1.PodPending),
+		},
+		{
+			name: "pulling",
+			pod:  probe(corev1.PodPending, terminated, waiting("ContainerCreating")),
+		},
+		{
+			name:       "pulled",
+			pod:        probe(corev1.PodRunning, terminated, running),
+			wantPulled: true,
+		},
+		{
+			name:       "pulled without a shell",
+			pod:        probe(corev1.PodPending, waiting("RunContainerError"), terminated),
+			wantPulled: true,
+		},
+		{
+			name:    "pull backoff",
+			pod:     probe(corev1.PodPending, terminated, waiting("ImagePullBackOff")),
+			wantErr: true,
+		},
+		{
+			name:    "invalid image",
+			pod:     probe(corev1.PodPending, waiting("InvalidImageName"), waiting("ContainerCreating")),
+			wantErr: true,
+		},
+		{
+			name:    "rejected by the node",
+			pod:     probe(corev1.PodFailed),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pulled, err := imageProbeStatus(tt.pod)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("imageProbeStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if pulled != tt.wantPulled {
+				t.Errorf("imageProbeStatus() = %v, want %v", pulled, tt.wantPulled)
+			}
+		})
+	}
+}
+
+func Test_verifyImagesPullable(t *testing.T) {
+	tests := []struct {
+		name    string
+		image   string
+		reason  string
+		wantErr bool
+	}{
+		{name: "pulled", image: "mirror/cstor-pool:3.5.0", reason: "RunContainerError"},
+		{name: "missing tag", image: "mirror/cstor-pool:3.5.1", reason: "ErrImagePull", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := testPoolDeployment("openebs/cstor-pool:3.4.0")
+			new := old.DeepCopy()
+			new.Spec.Template.Spec.Containers[0].Image = tt.image
+			pods := []runtime.Object{}
+			for _, node := range []string{"node-1", "node-2", "node-1"} {
+				pods = append(pods, &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cstor-pool-" + node + "-" + string(rune('a'+len(pods))),
+						Namespace: "openebs",
+						Labels:    map[string]string{"app": "cstor-pool"},
+					},
+					Spec: corev1.PodSpec{NodeName: node},
+				})
+			}
+			client := fake.NewSimpleClientset(pods...)
+			created := []string{}
+			// the fake clientset neither generates names nor runs the pods,
+			// so the probes are named after their node and report the
+			// status of the pull on creation
+			client.PrependReactor("create", "pods",
+				func(action k8stesting.Action) (bool, runtime.Object, error) {
+					p := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+					p.Name = p.GenerateName + p.Spec.NodeName
+					p.Status.ContainerStatuses = []corev1.ContainerStatus{{
+						Image: p.Spec.Containers[0].Image,
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{Reason: tt.reason},
+						},
+					}}
+					created = append(created, p.Name)
+					return false, nil, nil
+				},
+			)
+			err := verifyImagesPullable(context.Background(), client, time.Minute,
+				deploymentImageCheck(old, new))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verifyImagesPullable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			want := []string{
+				"cspc-stripe-k7cc-image-check-node-1",
+				"cspc-stripe-k7cc-image-check-node-2",
+			}
+			if !reflect.DeepEqual(created, want) {
+				t.Errorf("verifyImagesPullable() created probes %v, want %v", created, want)
+			}
+			left, err := client.CoreV1().Pods("openebs").List(context.TODO(),
+				metav1.ListOptions{LabelSelector: imageCheckLabel})
+			if err != nil {
+				t.Fatalf("failed to list probes: %v", err)
+			}
+			if len(left.Items) != 0 {
+				t.Errorf("verifyImagesPullable() left %d probes behind", len(left.Items))
+			}
+		})
+	}
+}
+
+func TestCSPCPatch_checkCSPIImages(t *testing.T) {
+	kubeObjects := []runtime.Object{}
+	openebsObjects := []runtime.Object{}
+	// cspi-c was upgraded by a previous attempt
+	for name, version := range map[string]string{"cspi-a": "3.4.0", "cspi-b": "3.4.0", "cspi-c": "3.5.0"} {
+		cspi := &cstor.CStorPoolInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "openebs",
+				Labels:    map[string]string{"openebs.io/version": version},
+			},
+		}
+		cspi.VersionDetails.Status.Current = version
+		deploy := testPoolDeployment("openebs/cstor-pool:" + version)
+		deploy.Name = name
+		deploy.UID = types.UID(name)
+		deploy.Labels = map[string]string{
+			"openebs.io/cstor-pool-instance": name,
+			"openebs.io/version":             version,
+		}
+		deploy.Spec.Selector.MatchLabels = map[string]string{"app": "cstor-pool-" + name}
+		deploy.Spec.Template.Labels = map[string]string{}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name + "-pod",
+				Namespace: "openebs",
+				Labels:    map[string]string{"app": "cstor-pool-" + name},
+			},
+			Spec: corev1.PodSpec{NodeName: "node-" + name},
+		}
+		openebsObjects = append(openebsObjects, cspi)
+		kubeObjects = append(kubeObjects, deploy, pod)
+	}
+	client := fake.NewSimpleClientset(kubeObjects...)
+	created := []string{}
+	client.PrependReactor("create", "pods",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			p := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+			p.Name = p.GenerateName + p.Spec.NodeName
+			for _, c := range p.Spec.Containers {
+				p.Status.ContainerStatuses = append(p.Status.ContainerStatuses, corev1.ContainerStatus{
+					Image: c.Image,
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "RunContainerError"},
+					},
+				})
+			}
+			created = append(created, p.Name)
+			return false, nil, nil
+		},
+	)
+	obj := &CSPCPatch{
+		ResourcePatch: &ResourcePatch{
+			OpenebsNamespace: "openebs",
+			From:             "3.4.0",
+			To:               "3.5.0",
+			ImagePullCheck:   true,
+			ImagePullTimeout: time.Minute,
+		},
+		Namespace: "openebs",
+		CSPIs:     []string{"cspi-a", "cspi-b", "cspi-c"},
+		Client: &Client{
+			KubeClientset:    client,
+			OpenebsClientset: openebsFakeClientset.NewSimpleClientset(openebsObjects...),
+		},
+	}
+	msg, err := obj.checkCSPIImages()
+	if err != nil {
+		t.Fatalf("checkCSPIImages() error = %v, %s", err, msg)
+	}
+	want := []string{
+		"cspi-a-image-check-node-cspi-a",
+		"cspi-b-image-check-node-cspi-b",
+	}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("checkCSPIImages() created probes %v, want %v", created, want)
+	}
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	tagRegexp    = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	digestRegexp = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+)
+
+// ImageOverride replaces the image of the containers matching either
+// the container name or the repository of the current image. Either
+// the tag and digest, or the full image reference can be overridden.
+type ImageOverride struct {
+	// Container is the name of the container, for example cstor-istgt
+	Container string `json:"container,omitempty"`
+	// Repository is the repository of the current image with or
+	// without the registry, for example openebs/cstor-pool
+	Repository string `json:"repository,omitempty"`
+	// Tag replaces the tag of the upgraded image
+	Tag string `json:"tag,omitempty"`
+	// Digest pins the upgraded image, for example sha256:<hex>
+	Digest string `json:"digest,omitempty"`
+	// Image replaces the whole image reference
+	Image string `json:"image,omitempty"`
+}
+
+// ImageMapping is the list of image overrides, an override by
+// container name takes precedence over one by repository
+type ImageMapping []ImageOverride
+
+// ParseImageMapping parses the image mapping from yaml or json
+func ParseImageMapping(data []byte) (ImageMapping, error) {
+	m := ImageMapping{}
+	err := yaml.UnmarshalStrict(data, &m)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse image mapping")
+	}
+	return m, m.Validate()
+}
+
+// Validate checks that every override has a single key, a valid
+// replacement and that no two overrides have the same key
+func (m ImageMapping) Validate() error {
+	seen := map[string]bool{}
+	for i, o := range m {
+		var key string
+		switch {
+		case o.Container != "" && o.Repository != "":
+			return errors.Errorf("image override %d: only one of container or repository can be set", i)
+		case o.Container != "":
+			key = "container " + o.Container
+		case o.Repository != "":
+			key = "repository " + o.Repository
+		default:
+			return errors.Errorf("image override %d: container or repository is required", i)
+		}
+		if seen[key] {
+			return errors.Errorf("image override for %s: duplicate override", key)
+		}
+		seen[key] = true
+		if o.Image != "" {
+			if o.Tag != "" || o.Digest != "" {
+				return errors.Errorf("image override for %s: image cannot be set along with tag or digest", key)
+			}
+			repo, tag, digest := splitImage(o.Image)
+			if repo == "" || (tag == "" && digest == "") {
+				return errors.Errorf("image override for %s: image %q should have a tag or digest", key, o.Image)
+			}
+			if (tag != "" && !tagRegexp.MatchString(tag)) ||
+				(digest != "" && !digestRegexp.MatchString(digest)) {
+				return errors.Errorf("image override for %s: invalid image %q", key, o.Image)
+			}
+			continue
+		}
+		if o.Tag == "" && o.Digest == "" {
+			return errors.Errorf("image override for %s: one of tag, digest or image is required", key)
+		}
+		if o.Tag != "" && !tagRegexp.MatchString(o.Tag) {
+			return errors.Errorf("image override for %s: invalid tag %q", key, o.Tag)
+		}
+		if o.Digest != "" && !digestRegexp.MatchString(o.Digest) {
+			return errors.Errorf("image override for %s: invalid digest %q, should be sha256:<hex>", key, o.Digest)
+		}
+	}
+	return nil
+}
+
+// lookup returns the override for the given container and its current image
+func (m ImageMapping) lookup(container, current string) *ImageOverride {
+	for i := range m {
+		if m[i].Container != "" && m[i].Container == container {
+			return &m[i]
+		}
+	}
+	repo, _, _ := splitImage(current)
+	for i := range m {
+		r := m[i].Repository
+		if r != "" && (repo == r || strings.HasSuffix(repo, "/"+r)) {
+			return &m[i]
+		}
+	}
+	return nil
+}
+
+// Image returns the image the given container is upgraded to, which is
+// the default image unless an override matches the container
+func (m ImageMapping) Image(container, current, defaultImage string) string {
+	o := m.lookup(container, current)
+	if o == nil {
+		return defaultImage
+	}
+	if o.Image != "" {
+		return o.Image
+	}
+	repo, tag, _ := splitImage(defaultImage)
+	if o.Tag != "" {
+		tag = o.Tag
+	}
+	image := repo
+	if tag != "" {
+		image += ":" + tag
+	}
+	if o.Digest != "" {
+		image += "@" + o.Digest
+	}
+	return image
+}
+
+// splitImage splits the image reference into its repository, tag and digest
+func splitImage(image string) (string, string, string) {
+	var tag, digest string
+	if i := strings.Index(image, "@"); i != -1 {
+		image, digest = image[:i], image[i+1:]
+	}
+	// a colon before the last slash is the port of the registry
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image, tag = image[:i], image[i+1:]
+	}
+	return image, tag, digest
+}
+
+// The containers managed by openebs in each kind of workload, with the
+// repositories of their images. Other containers, like injected sidecars,
+// are not upgraded.
+var (
+	cstorTargetContainers = map[string][]string{
+		"cstor-istgt":          {"cstor-istgt"},
+		"cstor-volume-mgmt":    {"cstor-volume-manager", "cstor-volume-mgmt"},
+		"maya-volume-exporter": {"m-exporter"},
+	}
+	cstorPoolContainers = map[string][]string{
+		"cstor-pool":      {"cstor-pool"},
+		"cstor-pool-mgmt": {"cstor-pool-manager", "cstor-pool-mgmt"},
+		"maya-exporter":   {"m-exporter"},
+	}
+	jivaControllerContainers = map[string][]string{
+		"jiva-controller":      {"jiva"},
+		"maya-volume-exporter": {"m-exporter"},
+	}
+	jivaReplicaContainers = map[string][]string{
+		"jiva-replica": {"jiva"},
+	}
+)
+
+// isKnownContainer returns whether the container has the name of an
+// openebs container, and if so whether its image repository is one of
+// the repositories of that container
+func isKnownContainer(c corev1.Container, known map[string][]string) (bool, bool) {
+	repos, ok := known[c.Name]
+	if !ok {
+		return false, false
+	}
+	repo, _, _ := splitImage(c.Image)
+	name := strings.TrimSuffix(repo[strings.LastIndex(repo, "/")+1:], "-amd64")
+	for _, r := range repos {
+		if name == r {
+			return true, true
+		}
+	}
+	return true, false
+}
+
+// rewriteImages upgrades the images of the openebs containers of the pod
+// spec and returns the names of the containers left untouched. Containers
+// with an image override are always upgraded. Only the images are changed,
+// so the init containers, env and resources added to the workload are kept.
+// An openebs container with an unknown image, like one renamed in a private
+// registry, fails the upgrade unless it has an image override, as leaving it
+// at the old version would break the upgraded resource.
+func (r *ResourcePatch) rewriteImages(spec *corev1.PodSpec, known map[string][]string) ([]string, error) {
+	tag := r.To
+	if r.ImageTag != "" {
+		tag = r.ImageTag
+	}
+	var skipped []string
+	for i := range spec.Containers {
+		c := &spec.Containers[i]
+		if r.ImageMapping.lookup(c.Name, c.Image) == nil {
+			knownName, knownRepo := isKnownContainer(*c, known)
+			if !knownName {
+				skipped = append(skipped, c.Name)
+				continue
+			}
+			if !knownRepo {
+				return nil, errors.Errorf("container %s has the unknown image %s, "+
+					"set an image override for the container to upgrade it", c.Name, c.Image)
+			}
+		}
+		repo, err := r.imageRepository(c.Image)
+		if err != nil {
+			return nil, err
+		}
+		c.Image = r.ImageMapping.Image(c.Name, c.Image, repo+":"+tag)
+	}
+	return skipped, nil
+}
+
+// logSkippedContainers reports the containers which were not upgraded
+func logSkippedContainers(kind, name string, skipped []string) {
+	if len(skipped) != 0 {
+		klog.Infof("Skipping non openebs containers %v of %s %s", skipped, kind, name)
+	}
+}
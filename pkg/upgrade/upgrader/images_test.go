@@ -0,0 +1,267 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var testDigest = "sha256:" + strings.Repeat("ab", 32)
+
+func TestImageMapping_Image(t *testing.T) {
+	m := ImageMapping{
+		{Container: "cstor-istgt", Digest: testDigest},
+		{Container: "maya-volume-exporter", Tag: "3.5.0-patched"},
+		{Repository: "openebs/cstor-pool", Image: "mirror.example.com:5000/cstor-pool:3.5.0-fips"},
+		{Repository: "openebs/cstor-istgt", Tag: "ignored"},
+	}
+	tests := []struct {
+		name      string
+		container string
+		current   string
+		defImage  string
+		want      string
+	}{
+		{
+			name:      "digest by container",
+			container: "cstor-istgt",
+			current:   "openebs/cstor-istgt:3.4.0",
+			defImage:  "openebs/cstor-istgt:3.5.0",
+			want:      "openebs/cstor-istgt:3.5.0@" + testDigest,
+		},
+		{
+			name:      "tag by container",
+			container: "maya-volume-exporter",
+			current:   "openebs/m-exporter:3.4.0",
+			defImage:  "openebs/m-exporter:3.5.0",
+			want:      "openebs/m-exporter:3.5.0-patched",
+		},
+		{
+			name:      "image by repository with registry",
+			container: "cstor-pool",
+			current:   "docker.io/openebs/cstor-pool:3.4.0",
+			defImage:  "docker.io/openebs/cstor-pool:3.5.0",
+			want:      "mirror.example.com:5000/cstor-pool:3.5.0-fips",
+		},
+		{
+			name:      "no override",
+			container: "cstor-pool-mgmt",
+			current:   "openebs/cstor-pool-manager:3.4.0",
+			defImage:  "openebs/cstor-pool-manager:3.5.0",
+			want:      "openebs/cstor-pool-manager:3.5.0",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := m.Image(test.container, test.current, test.defImage)
+			if got != test.want {
+				t.Errorf("Image() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestImageMapping_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			mapping: `
+- container: cstor-istgt
+  digest: ` + testDigest + `
+- repository: openebs/cstor-pool
+  tag: 3.5.0-fips
+- repository: openebs/m-exporter
+  image: registry.example.com/m-exporter@` + testDigest,
+		},
+		{
+			name:    "unknown field",
+			mapping: `[{container: cstor-istgt, tags: 3.5.0}]`,
+			wantErr: true,
+		},
+		{
+			name:    "no key",
+			mapping: `[{tag: 3.5.0}]`,
+			wantErr: true,
+		},
+		{
+			name:    "both keys",
+			mapping: `[{container: cstor-istgt, repository: openebs/cstor-istgt, tag: 3.5.0}]`,
+			wantErr: true,
+		},
+		{
+			name:    "duplicate key",
+			mapping: `[{container: cstor-istgt, tag: 3.5.0}, {container: cstor-istgt, tag: 3.5.1}]`,
+			wantErr: true,
+		},
+		{
+			name:    "no replacement",
+			mapping: `[{container: cstor-istgt}]`,
+			wantErr: true,
+		},
+		{
+			name:    "image with tag",
+			mapping: `[{container: cstor-istgt, image: "openebs/cstor-istgt:3.5.0", tag: 3.5.0}]`,
+			wantErr: true,
+		},
+		{
+			name:    "image without tag",
+			mapping: `[{container: cstor-istgt, image: "registry.example.com:5000/cstor-istgt"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid digest",
+			mapping: `[{container: cstor-istgt, digest: "sha256:abc"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid tag",
+			mapping: `[{container: cstor-istgt, tag: "-3.5.0"}]`,
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseImageMapping([]byte(test.mapping))
+			if (err != nil) != test.wantErr {
+				t.Errorf("ParseImageMapping() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_transformCSPIDeploy(t *testing.T) {
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cspi-1",
+			Labels: map[string]string{},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{},
+				},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{Name: "init", Image: "busybox:1.36"},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:  "cstor-pool",
+							Image: "openebs/cstor-pool-amd64:3.4.0",
+							Env:   []corev1.EnvVar{{Name: "EXTRA", Value: "1"}},
+						},
+						{Name: "cstor-pool-mgmt", Image: "quay.io/openebs/cstor-pool-manager:3.4.0"},
+						{Name: "maya-exporter", Image: "openebs/m-exporter:3.4.0@" + testDigest},
+						{Name: "istio-proxy", Image: "docker.io/istio/proxyv2:1.20.0"},
+						{Name: "fluent-bit", Image: "fluent/fluent-bit:2.2"},
+						{Name: "cstor-pool-mgmt-debug", Image: "openebs/cstor-pool-manager:3.4.0"},
+					},
+				},
+			},
+		},
+	}
+	res := &ResourcePatch{
+		To: "3.5.0",
+		ImageMapping: ImageMapping{
+			{Container: "fluent-bit", Tag: "2.2.1"},
+		},
+	}
+	skipped, err := transformCSPIDeploy(d, res, "openebs-cstor-operator")
+	if err != nil {
+		t.Fatalf("transformCSPIDeploy() error = %v", err)
+	}
+	wantImages := []string{
+		"openebs/cstor-pool:3.5.0",
+		"quay.io/openebs/cstor-pool-manager:3.5.0",
+		"openebs/m-exporter:3.5.0",
+		"docker.io/istio/proxyv2:1.20.0",
+		"fluent/fluent-bit:2.2.1",
+		"openebs/cstor-pool-manager:3.4.0",
+	}
+	for i, c := range d.Spec.Template.Spec.Containers {
+		if c.Image != wantImages[i] {
+			t.Errorf("container %s image = %q, want %q", c.Name, c.Image, wantImages[i])
+		}
+	}
+	if !reflect.DeepEqual(skipped, []string{"istio-proxy", "cstor-pool-mgmt-debug"}) {
+		t.Errorf("transformCSPIDeploy() skipped = %v", skipped)
+	}
+	if d.Spec.Template.Spec.InitContainers[0].Image != "busybox:1.36" ||
+		len(d.Spec.Template.Spec.Containers[0].Env) != 1 {
+		t.Errorf("transformCSPIDeploy() changed the init containers or env")
+	}
+}
+
+func TestResourcePatch_rewriteImages(t *testing.T) {
+	tests := map[string]struct {
+		container   corev1.Container
+		mapping     ImageMapping
+		want        string
+		wantSkipped []string
+		wantErr     bool
+	}{
+		"openebs container": {
+			container: corev1.Container{Name: "cstor-istgt", Image: "openebs/cstor-istgt:3.4.0"},
+			want:      "openebs/cstor-istgt:3.5.0",
+		},
+		"sidecar is skipped": {
+			container:   corev1.Container{Name: "istio-proxy", Image: "docker.io/istio/proxyv2:1.20.0"},
+			want:        "docker.io/istio/proxyv2:1.20.0",
+			wantSkipped: []string{"istio-proxy"},
+		},
+		"openebs container with an unknown image": {
+			container: corev1.Container{Name: "cstor-istgt", Image: "registry.example.com/storage/istgt:3.4.0"},
+			wantErr:   true,
+		},
+		"openebs container with an unknown image and an override": {
+			container: corev1.Container{Name: "cstor-istgt", Image: "registry.example.com/storage/istgt:3.4.0"},
+			mapping: ImageMapping{
+				{Container: "cstor-istgt", Image: "registry.example.com/storage/istgt:3.5.0"},
+			},
+			want: "registry.example.com/storage/istgt:3.5.0",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			spec := &corev1.PodSpec{Containers: []corev1.Container{test.container}}
+			r := &ResourcePatch{To: "3.5.0", ImageMapping: test.mapping}
+			skipped, err := r.rewriteImages(spec, cstorTargetContainers)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("rewriteImages() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if got := spec.Containers[0].Image; got != test.want {
+				t.Errorf("rewriteImages() image = %q, want %q", got, test.want)
+			}
+			if !reflect.DeepEqual(skipped, test.wantSkipped) {
+				t.Errorf("rewriteImages() skipped = %v, want %v", skipped, test.wantSkipped)
+			}
+		})
+	}
+}
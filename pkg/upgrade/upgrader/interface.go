@@ -20,3 +20,15 @@ package upgrader
 type Upgrader interface {
 	Upgrade() error
 }
+
+// DryRunner abstracts computing the changes required to upgrade
+// a resource without applying them
+type DryRunner interface {
+	DryRun() ([]Change, error)
+}
+
+// Rollbacker abstracts restoring a resource to the
+// objects recorded before the upgrade
+type Rollbacker interface {
+	Rollback() error
+}
@@ -17,22 +17,16 @@ limitations under the License.
 package upgrader
 
 import (
-	"time"
+	"context"
 
 	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
 	jv "github.com/openebs/jiva-operator/pkg/apis/openebs/v1"
-	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
-	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
-	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/client/config"
 
 	"github.com/openebs/upgrade/pkg/upgrade/patch"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/klog/v2"
 )
 
 // JivaVolumePatch is the patch required to upgrade JivaVolume
@@ -44,6 +38,12 @@ type JivaVolumePatch struct {
 	Service      *patch.Service
 	JivaVolumeCR *patch.JV
 	Utask        *v1Alpha1API.UpgradeTask
+	// controllerSkipped and replicaSkipped are the containers of
+	// the controller and replicas which are not managed by openebs
+	controllerSkipped, replicaSkipped []string
+	// controllerCheck and replicaCheck are the workloads
+	// whose new images are verified before they are patched
+	controllerCheck, replicaCheck imageCheck
 	*Client
 }
 
@@ -75,7 +75,11 @@ func NewJivaVolumePatch(opts ...JivaVolumePatchOptions) *JivaVolumePatch {
 
 // PreUpgrade ...
 func (obj *JivaVolumePatch) PreUpgrade() (string, error) {
-	err := isOperatorUpgraded("jiva-operator", obj.Namespace, obj.To, obj.KubeClientset)
+	err := obj.ImageMapping.Validate()
+	if err != nil {
+		return "failed to validate image mapping", err
+	}
+	err = isOperatorUpgraded("jiva-operator", obj.Namespace, obj.To, obj.KubeClientset)
 	if err != nil {
 		return "failed to verify jiva-operator", err
 	}
@@ -110,6 +114,7 @@ func (obj *JivaVolumePatch) Init() (string, error) {
 	}
 	obj.Replicas = patch.NewStatefulSet(
 		patch.WithStatefulSetClient(obj.KubeClientset),
+		patch.WithStatefulSetPodUpdated(obj.verifyReplicaResync),
 	)
 	err = obj.Replicas.Get(replicaLabel, obj.Namespace)
 	if err != nil {
@@ -118,18 +123,8 @@ func (obj *JivaVolumePatch) Init() (string, error) {
 	obj.Service = patch.NewService(
 		patch.WithKubeClient(obj.KubeClientset),
 	)
-	scheme := runtime.NewScheme()
-	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
-	utilruntime.Must(jv.AddToScheme(scheme))
-	clientgoscheme.AddToScheme(scheme)
-	cl, err := client.New(config.GetConfigOrDie(), client.Options{
-		Scheme: scheme,
-	})
-	if err != nil {
-		return "failed to create runtime client", err
-	}
 	obj.JivaVolumeCR = patch.NewJV(
-		patch.WithJVClient(cl),
+		patch.WithJVClient(obj.RuntimeClient),
 	)
 
 	err = obj.Service.Get(serviceLabel, obj.Namespace)
@@ -161,66 +156,50 @@ func (obj *JivaVolumePatch) Init() (string, error) {
 
 func (obj *JivaVolumePatch) getJivaControllerPatchData() error {
 	newDeploy := obj.Controller.Object.DeepCopy()
-	err := obj.transformJivaController(newDeploy, obj.ResourcePatch)
+	skipped, err := obj.transformJivaController(newDeploy, obj.ResourcePatch)
 	if err != nil {
 		return err
 	}
+	obj.controllerSkipped = skipped
+	obj.controllerCheck = deploymentImageCheck(obj.Controller.Object, newDeploy)
 	obj.Controller.Data, err = GetPatchData(obj.Controller.Object, newDeploy)
 	return err
 }
 
-func (obj *JivaVolumePatch) transformJivaController(d *appsv1.Deployment, res *ResourcePatch) error {
-	// update deployment images
-	tag := res.To
-	if res.ImageTag != "" {
-		tag = res.ImageTag
-	}
-	cons := len(d.Spec.Template.Spec.Containers)
-	for i := 0; i < cons; i++ {
-		url, err := getImageURL(
-			d.Spec.Template.Spec.Containers[i].Image,
-			res.BaseURL,
-		)
-		if err != nil {
-			return err
-		}
-		d.Spec.Template.Spec.Containers[i].Image = url + ":" + tag
+func (obj *JivaVolumePatch) transformJivaController(d *appsv1.Deployment, res *ResourcePatch) ([]string, error) {
+	// update the images of the openebs containers
+	skipped, err := res.rewriteImages(&d.Spec.Template.Spec, jivaControllerContainers)
+	if err != nil {
+		return nil, err
 	}
+	logSkippedContainers("Deployment", d.Name, skipped)
 	d.Labels["openebs.io/version"] = res.To
 	d.Spec.Template.Labels["openebs.io/version"] = res.To
-	return nil
+	return skipped, nil
 }
 
 func (obj *JivaVolumePatch) getJivaReplicaPatchData() error {
 	newSTS := obj.Replicas.Object.DeepCopy()
-	err := obj.transformJivaReplica(newSTS, obj.ResourcePatch)
+	skipped, err := obj.transformJivaReplica(newSTS, obj.ResourcePatch)
 	if err != nil {
 		return err
 	}
+	obj.replicaSkipped = skipped
+	obj.replicaCheck = statefulSetImageCheck(obj.Replicas.Object, newSTS)
 	obj.Replicas.Data, err = GetPatchData(obj.Replicas.Object, newSTS)
 	return err
 }
 
-func (obj *JivaVolumePatch) transformJivaReplica(s *appsv1.StatefulSet, res *ResourcePatch) error {
-	// update deployment images
-	tag := res.To
-	if res.ImageTag != "" {
-		tag = res.ImageTag
-	}
-	cons := len(s.Spec.Template.Spec.Containers)
-	for i := 0; i < cons; i++ {
-		url, err := getImageURL(
-			s.Spec.Template.Spec.Containers[i].Image,
-			res.BaseURL,
-		)
-		if err != nil {
-			return err
-		}
-		s.Spec.Template.Spec.Containers[i].Image = url + ":" + tag
+func (obj *JivaVolumePatch) transformJivaReplica(s *appsv1.StatefulSet, res *ResourcePatch) ([]string, error) {
+	// update the images of the openebs containers
+	skipped, err := res.rewriteImages(&s.Spec.Template.Spec, jivaReplicaContainers)
+	if err != nil {
+		return nil, err
 	}
+	logSkippedContainers("StatefulSet", s.Name, skipped)
 	s.Labels["openebs.io/version"] = res.To
 	s.Spec.Template.Labels["openebs.io/version"] = res.To
-	return nil
+	return skipped, nil
 }
 
 func (obj *JivaVolumePatch) getJVPatchData() error {
@@ -254,20 +233,25 @@ func transformJivaService(svc *corev1.Service, res *ResourcePatch) error {
 }
 
 // JivaVolumeUpgrade ...
-func (obj *JivaVolumePatch) JivaVolumeUpgrade() (string, error) {
-	err := obj.Controller.Patch(obj.From, obj.To)
+func (obj *JivaVolumePatch) JivaVolumeUpgrade(ctx context.Context) (string, error) {
+	err := obj.Controller.Patch(ctx, obj.From, obj.To)
 	if err != nil {
 		return "failed to patch target deploy", err
 	}
-	err = obj.Service.Patch(obj.From, obj.To)
+	obj.recordPatch("Deployment", obj.Controller.Object, obj.Controller.Data)
+	err = obj.Service.Patch(ctx, obj.From, obj.To)
 	if err != nil {
 		return "failed to patch target svc", err
 	}
-	err = obj.JivaVolumeCR.Patch(obj.From, obj.To)
+	obj.recordPatch("Service", obj.Service.Object, obj.Service.Data)
+	err = obj.JivaVolumeCR.Patch(ctx, obj.From, obj.To)
 	if err != nil {
 		return "failed to patch JivaCR", err
 	}
-	err = obj.verifyJivaVolumeCRversionReconcile()
+	if jvData, err := obj.JivaVolumeCR.Data(); err == nil {
+		obj.recordPatch("JivaVolume", obj.JivaVolumeCR.Object, jvData)
+	}
+	err = obj.verifyJivaVolumeCRversionReconcile(ctx)
 	if err != nil {
 		return "failed to verify version reconcile on JivaVolumeCR", err
 	}
@@ -277,18 +261,18 @@ func (obj *JivaVolumePatch) JivaVolumeUpgrade() (string, error) {
 // Upgrade execute the steps to upgrade JivaVolume
 func (obj *JivaVolumePatch) Upgrade() error {
 	var err, uerr error
-	obj.Utask, err = getOrCreateUpgradeTask(
+	obj.Utask, uerr = getOrCreateUpgradeTask(
 		"jivaVolume",
 		obj.ResourcePatch,
 		obj.Client,
 	)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
 	statusObj := v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.PreUpgrade}
 	statusObj.Phase = v1Alpha1API.StepWaiting
 	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
 	statusObj.Phase = v1Alpha1API.StepErrored
@@ -297,7 +281,7 @@ func (obj *JivaVolumePatch) Upgrade() error {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
 		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-		if uerr != nil && isUpgradeTaskJob {
+		if uerr != nil && obj.IsUpgradeTaskJob {
 			return uerr
 		}
 		return errors.Wrap(err, msg)
@@ -307,7 +291,27 @@ func (obj *JivaVolumePatch) Upgrade() error {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
 		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-		if uerr != nil && isUpgradeTaskJob {
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
+	}
+	msg, err = obj.checkImages(obj.KubeClientset, obj.controllerCheck, obj.replicaCheck)
+	if err != nil {
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
+	}
+	msg, err = obj.backup()
+	if err != nil {
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
 			return uerr
 		}
 		return errors.Wrap(err, msg)
@@ -316,24 +320,42 @@ func (obj *JivaVolumePatch) Upgrade() error {
 	statusObj.Message = "Pre-upgrade steps were successful"
 	statusObj.Reason = ""
 	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
 
+	obj.Utask, uerr = obj.checkControl(obj.Utask, obj.Client)
+	if uerr != nil && (obj.IsUpgradeTaskJob || IsCancelled(uerr)) {
+		return uerr
+	}
 	statusObj = v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.ReplicaUpgrade}
 	statusObj.Phase = v1Alpha1API.StepWaiting
 	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
 	statusObj.Phase = v1Alpha1API.StepErrored
 
-	err = obj.Replicas.Patch(obj.From, obj.To)
+	ctx, cancel := obj.stepContext()
+	defer cancel()
+	err = obj.Replicas.Patch(ctx, obj.From, obj.To)
+	if err != nil {
+		msg = "failed to patch replica sts"
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
+		}
+		return errors.Wrap(err, msg)
+	}
+	obj.recordPatch("StatefulSet", obj.Replicas.Object, obj.Replicas.Data)
+	msg, err = obj.verifyReplicaRebuild(ctx)
 	if err != nil {
-		statusObj.Message = "failed to patch replica sts"
+		statusObj.Message = msg
 		statusObj.Reason = err.Error()
 		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-		if uerr != nil && isUpgradeTaskJob {
+		if uerr != nil && obj.IsUpgradeTaskJob {
 			return uerr
 		}
 		return errors.Wrap(err, msg)
@@ -343,22 +365,28 @@ func (obj *JivaVolumePatch) Upgrade() error {
 	statusObj.Message = "Replica upgrade was successful"
 	statusObj.Reason = ""
 	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+	obj.Utask, uerr = obj.checkControl(obj.Utask, obj.Client)
+	if uerr != nil && (obj.IsUpgradeTaskJob || IsCancelled(uerr)) {
 		return uerr
 	}
 	statusObj = v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.TargetUpgrade}
 	statusObj.Phase = v1Alpha1API.StepWaiting
 	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
 	statusObj.Phase = v1Alpha1API.StepErrored
-	msg, err = obj.JivaVolumeUpgrade()
+	ctx, cancel = obj.stepContext()
+	defer cancel()
+	msg, err = obj.JivaVolumeUpgrade(ctx)
 	if err != nil {
 		statusObj.Message = msg
 		statusObj.Reason = err.Error()
 		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-		if uerr != nil && isUpgradeTaskJob {
+		if uerr != nil && obj.IsUpgradeTaskJob {
 			return uerr
 		}
 		return errors.Wrap(err, msg)
@@ -367,30 +395,128 @@ func (obj *JivaVolumePatch) Upgrade() error {
 	statusObj.Message = "Target upgrade was successful"
 	statusObj.Reason = ""
 	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
-	if uerr != nil && isUpgradeTaskJob {
+	if uerr != nil && obj.IsUpgradeTaskJob {
 		return uerr
 	}
 	return nil
 }
 
-func (obj *JivaVolumePatch) verifyJivaVolumeCRversionReconcile() error {
-	// get the latest cvc object
-	err := obj.JivaVolumeCR.Get(obj.Name, obj.Namespace)
+// DryRun returns the changes required to upgrade the JivaVolume
+func (obj *JivaVolumePatch) DryRun() ([]Change, error) {
+	msg, err := obj.Init()
+	if err != nil {
+		return nil, errors.Wrap(err, msg)
+	}
+	msg, err = obj.PreUpgrade()
+	if err != nil {
+		return nil, errors.Wrap(err, msg)
+	}
+	jvData, err := obj.JivaVolumeCR.Data()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create jivavolume patch for volume"+obj.Name)
+	}
+	changes := appendChange(nil, "StatefulSet", obj.Replicas.Object, obj.Replicas.Data,
+		obj.replicaSkipped...)
+	changes = appendChange(changes, "Deployment", obj.Controller.Object, obj.Controller.Data,
+		obj.controllerSkipped...)
+	changes = appendChange(changes, "Service", obj.Service.Object, obj.Service.Data)
+	changes = appendChange(changes, "JivaVolume", obj.JivaVolumeCR.Object, jvData)
+	return changes, nil
+}
+
+func (obj *JivaVolumePatch) backup() (string, error) {
+	err := saveBackup("jivaVolume", obj.ResourcePatch, obj.Client,
+		backupObject{kind: "StatefulSet", obj: obj.Replicas.Object.DeepCopy()},
+		backupObject{kind: "Deployment", obj: obj.Controller.Object.DeepCopy()},
+		backupObject{kind: "Service", obj: obj.Service.Object.DeepCopy()},
+		backupObject{kind: "JivaVolume", obj: obj.JivaVolumeCR.Object.DeepCopy()},
+	)
+	if err != nil {
+		return "failed to backup volume " + obj.Name, err
+	}
+	return "", nil
+}
+
+// Rollback restores the JivaVolume to the objects
+// recorded before the upgrade
+func (obj *JivaVolumePatch) Rollback() error {
+	cm, err := getBackup("jivaVolume", obj.ResourcePatch, obj.Client)
 	if err != nil {
 		return err
 	}
-	// waiting for the current version to be equal to desired version
-	for obj.JivaVolumeCR.Object.VersionDetails.Status.Current != obj.To {
-		klog.Infof("Verifying the reconciliation of version for %s", obj.JivaVolumeCR.Object.Name)
-		// Sleep equal to the default sync time
-		time.Sleep(10 * time.Second)
-		err = obj.JivaVolumeCR.Get(obj.Name, obj.Namespace)
-		if err != nil {
-			return err
-		}
-		if obj.JivaVolumeCR.Object.VersionDetails.Status.Message != "" {
-			klog.Errorf("failed to reconcile: %s", obj.JivaVolumeCR.Object.VersionDetails.Status.Reason)
+	setRollbackVersions(obj.ResourcePatch, cm)
+	var uerr error
+	obj.Utask, uerr = getUpgradeTaskForRollback(
+		"jivaVolume",
+		obj.ResourcePatch,
+		obj.Client,
+	)
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+	statusObj := v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.Rollback}
+	statusObj.Phase = v1Alpha1API.StepWaiting
+	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
+	}
+	statusObj.Phase = v1Alpha1API.StepErrored
+	ctx, cancel := obj.stepContext()
+	defer cancel()
+	msg, err := obj.rollback(ctx, cm)
+	if err != nil {
+		statusObj.Message = msg
+		statusObj.Reason = err.Error()
+		obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+		if uerr != nil && obj.IsUpgradeTaskJob {
+			return uerr
 		}
+		return errors.Wrap(err, msg)
+	}
+	statusObj.Phase = v1Alpha1API.StepCompleted
+	statusObj.Message = "Rollback was successful"
+	statusObj.Reason = ""
+	obj.Utask, uerr = updateUpgradeDetailedStatus(obj.Utask, statusObj, obj.OpenebsNamespace, obj.Client)
+	if uerr != nil && obj.IsUpgradeTaskJob {
+		return uerr
 	}
 	return nil
 }
+
+func (obj *JivaVolumePatch) rollback(ctx context.Context, cm *corev1.ConfigMap) (string, error) {
+	msg, err := obj.Init()
+	if err != nil {
+		return msg, err
+	}
+	err = rollbackJV(ctx, obj.JivaVolumeCR, cm)
+	if err != nil {
+		return "failed to rollback JivaCR", err
+	}
+	err = rollbackService(ctx, obj.Service, cm)
+	if err != nil {
+		return "failed to rollback target svc", err
+	}
+	err = rollbackDeployment(ctx, obj.Controller, cm)
+	if err != nil {
+		return "failed to rollback controller deploy", err
+	}
+	err = rollbackStatefulSet(ctx, obj.Replicas, cm)
+	if err != nil {
+		return "failed to rollback replica statefulset", err
+	}
+	return "", nil
+}
+
+func (obj *JivaVolumePatch) verifyJivaVolumeCRversionReconcile(ctx context.Context) error {
+	return waitForVersionReconcile(ctx, obj.Name, obj.To,
+		func() (versionStatus, error) {
+			// get the latest object
+			err := obj.JivaVolumeCR.Get(obj.Name, obj.Namespace)
+			if err != nil {
+				return versionStatus{}, err
+			}
+			s := obj.JivaVolumeCR.Object.VersionDetails.Status
+			return versionStatus{s.Current, s.Message, s.Reason}, nil
+		},
+	)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+
+	"github.com/openebs/upgrade/pkg/metrics"
+)
+
+// upgradeTaskResource returns the kind and the name
+// of the resource upgraded by the UpgradeTask
+func upgradeTaskResource(utaskObj *v1Alpha1API.UpgradeTask) (string, string) {
+	spec := utaskObj.Spec.ResourceSpec
+	switch {
+	case spec.CStorPoolCluster != nil:
+		return "cstorPoolCluster", spec.CStorPoolCluster.CSPCName
+	case spec.CStorPoolInstance != nil:
+		return "cstorPoolInstance", spec.CStorPoolInstance.CSPIName
+	case spec.CStorVolume != nil:
+		return "cstorVolume", spec.CStorVolume.PVName
+	case spec.JivaVolume != nil:
+		return "jivaVolume", spec.JivaVolume.PVName
+	}
+	return "", ""
+}
+
+// observeUpgradeStep records the metrics of the given status of an upgrade
+// step, it is called before the status is recorded on the UpgradeTask. A
+// step started again after a failed or interrupted attempt is a retry.
+func observeUpgradeStep(utaskObj *v1Alpha1API.UpgradeTask,
+	status v1Alpha1API.UpgradeDetailedStatuses) {
+	kind, name := upgradeTaskResource(utaskObj)
+	statuses := utaskObj.Status.UpgradeDetailedStatuses
+	if status.Phase == v1Alpha1API.StepWaiting {
+		retry := false
+		for i := len(statuses) - 1; i >= 0; i-- {
+			if statuses[i].Step == status.Step {
+				retry = statuses[i].Phase != v1Alpha1API.StepCompleted
+				break
+			}
+		}
+		metrics.StepStarted(metrics.Upgrade, kind, name, string(status.Step), retry)
+		return
+	}
+	if len(statuses) == 0 {
+		return
+	}
+	metrics.StepFinished(metrics.Upgrade, kind, name, string(status.Step),
+		statuses[len(statuses)-1].StartTime.Time,
+		status.Phase == v1Alpha1API.StepErrored)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"testing"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/openebs/upgrade/pkg/metrics"
+)
+
+func Test_observeUpgradeStep(t *testing.T) {
+	r := &ResourcePatch{
+		Name:             "pvc-retry",
+		OpenebsNamespace: "openebs",
+		From:             "3.4.0",
+		To:               "3.5.0",
+	}
+	retries := func() int {
+		n, err := testutil.GatherAndCount(metrics.Registry, "openebs_task_step_retries_total")
+		if err != nil {
+			t.Fatalf("failed to gather metrics: %v", err)
+		}
+		return n
+	}
+	utask := buildUpgradeTask("jivaVolume", r)
+	utask.Status.UpgradeDetailedStatuses = []v1Alpha1API.UpgradeDetailedStatuses{
+		stepStatus(v1Alpha1API.PreUpgrade, v1Alpha1API.StepCompleted, "Pre-upgrade steps were successful", ""),
+		stepStatus(v1Alpha1API.ReplicaUpgrade, v1Alpha1API.StepCompleted, "Replica upgrade was successful", ""),
+	}
+	before := retries()
+	observeUpgradeStep(utask, stepStatus(v1Alpha1API.TargetUpgrade, v1Alpha1API.StepWaiting, "", ""))
+	if got := retries(); got != before {
+		t.Errorf("first attempt of a step counted as retry")
+	}
+	utask.Status.UpgradeDetailedStatuses = append(utask.Status.UpgradeDetailedStatuses,
+		stepStatus(v1Alpha1API.TargetUpgrade, v1Alpha1API.StepErrored, "failed to patch target deploy", "timed out"))
+	observeUpgradeStep(utask, stepStatus(v1Alpha1API.TargetUpgrade, v1Alpha1API.StepWaiting, "", ""))
+	if got := retries(); got != before+1 {
+		t.Errorf("retries = %d, want %d", got, before+1)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// RewriteRule replaces the repository of the images matching either
+// the prefix or the regular expression with the replacement. The
+// replacement of a regular expression can refer to its groups as $1.
+type RewriteRule struct {
+	Prefix      string `json:"prefix,omitempty"`
+	Regex       string `json:"regex,omitempty"`
+	Replacement string `json:"replacement"`
+
+	re *regexp.Regexp
+}
+
+// RegistryRules is an ordered list of rewrite rules,
+// only the first rule matching an image is applied
+type RegistryRules []RewriteRule
+
+// archSuffixRule removes the -amd64 suffix of the
+// images from the openebs repositories
+var archSuffixRule = RewriteRule{
+	Regex:       `^(.*/)?openebs/([^/]+)-amd64$`,
+	Replacement: "${1}openebs/${2}",
+	re:          regexp.MustCompile(`^(.*/)?openebs/([^/]+)-amd64$`),
+}
+
+// ParseRegistryRules parses the rewrite rules from yaml or json
+func ParseRegistryRules(data []byte) (RegistryRules, error) {
+	rules := RegistryRules{}
+	err := yaml.UnmarshalStrict(data, &rules)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse registry rules")
+	}
+	for i := range rules {
+		err = rules[i].compile()
+		if err != nil {
+			return nil, errors.Wrapf(err, "registry rule %d", i)
+		}
+	}
+	return rules, nil
+}
+
+// prefixRule returns the rule for the image url prefix which keeps the
+// last path segment of the repository and prepends the prefix to it.
+// For example with the prefix xyz/aws-56546546/openebsdirectory/ the
+// repository abc/quay.io/openebs/jiva is rewritten to
+// xyz/aws-56546546/openebsdirectory/jiva
+func prefixRule(prefix string) RewriteRule {
+	return RewriteRule{
+		Regex:       `^(?:.*/)?([^/]+)$`,
+		Replacement: prefix + "${1}",
+		re:          regexp.MustCompile(`^(?:.*/)?([^/]+)$`),
+	}
+}
+
+func (r *RewriteRule) compile() error {
+	switch {
+	case r.Prefix != "" && r.Regex != "":
+		return errors.Errorf("only one of prefix or regex can be set")
+	case r.Prefix != "":
+		return nil
+	case r.Regex != "":
+		re, err := regexp.Compile(r.Regex)
+		if err != nil {
+			return errors.Wrapf(err, "invalid regex %q", r.Regex)
+		}
+		r.re = re
+		return nil
+	}
+	return errors.Errorf("prefix or regex is required")
+}
+
+// rewrite applies the rule to the repository
+func (r RewriteRule) rewrite(repo string) (string, bool) {
+	if r.re != nil {
+		if !r.re.MatchString(repo) {
+			return repo, false
+		}
+		return r.re.ReplaceAllString(repo, r.Replacement), true
+	}
+	if r.Prefix != "" && strings.HasPrefix(repo, r.Prefix) {
+		return r.Replacement + strings.TrimPrefix(repo, r.Prefix), true
+	}
+	return repo, false
+}
+
+// Rewrite applies the first rule matching the repository. A rule can
+// match the repository as it is written or with the implicit docker.io
+// registry, so that a docker.io/ prefix matches openebs/jiva too.
+func (rules RegistryRules) Rewrite(repo string) (string, bool) {
+	normalized := normalizeRepository(repo)
+	for _, r := range rules {
+		if rewritten, ok := r.rewrite(repo); ok {
+			return rewritten, true
+		}
+		if normalized == repo {
+			continue
+		}
+		if rewritten, ok := r.rewrite(normalized); ok {
+			return rewritten, true
+		}
+	}
+	return repo, false
+}
+
+// normalizeRepository adds the implicit docker.io registry
+// and library namespace to the repository
+func normalizeRepository(repo string) string {
+	parts := strings.SplitN(repo, "/", 2)
+	if len(parts) == 1 {
+		return "docker.io/library/" + repo
+	}
+	if strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost" {
+		return repo
+	}
+	return "docker.io/" + repo
+}
+
+// imageRepository returns the repository the given image is upgraded to,
+// rewritten by the first matching registry rule or else by the image
+// url prefix, the -amd64 suffix is then removed from openebs repositories
+func (r *ResourcePatch) imageRepository(image string) (string, error) {
+	repo, tag, digest := splitImage(image)
+	if tag == "" && digest == "" {
+		return "", errors.Errorf("no version tag found on image %s", image)
+	}
+	rules := r.RegistryRules
+	if r.BaseURL != "" {
+		rules = append(rules[:len(rules):len(rules)], prefixRule(r.BaseURL))
+	}
+	repo, _ = rules.Rewrite(repo)
+	// the suffix is removed only from the repositories still under
+	// openebs/, mirrors may have kept the -amd64 names
+	repo, _ = archSuffixRule.rewrite(repo)
+	return repo, nil
+}
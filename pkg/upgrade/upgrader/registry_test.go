@@ -0,0 +1,184 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import "testing"
+
+func Test_archSuffixRule(t *testing.T) {
+	tests := []struct {
+		name string
+		repo string
+		want string
+	}{
+		{
+			name: "with suffix",
+			repo: "openebs/cstor-operator-amd64",
+			want: "openebs/cstor-operator",
+		},
+		{
+			name: "without suffix",
+			repo: "openebs/cstor-operator",
+			want: "openebs/cstor-operator",
+		},
+		{
+			name: "airgap with suffix",
+			repo: "air-gap-having-amd64/openebs/cstor-operator-amd64",
+			want: "air-gap-having-amd64/openebs/cstor-operator",
+		},
+		{
+			name: "airgap without suffix",
+			repo: "air-gap-having-amd64/openebs/cstor-operator",
+			want: "air-gap-having-amd64/openebs/cstor-operator",
+		},
+		{
+			name: "custom repository with suffix",
+			repo: "air-gap-having-amd64/custom/cstor-operator-amd64",
+			want: "air-gap-having-amd64/custom/cstor-operator-amd64",
+		},
+		{
+			name: "custom repository without suffix",
+			repo: "air-gap-having-amd64/custom/cstor-operator",
+			want: "air-gap-having-amd64/custom/cstor-operator",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := archSuffixRule.rewrite(tt.repo); got != tt.want {
+				t.Errorf("archSuffixRule.rewrite() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourcePatch_imageRepository(t *testing.T) {
+	rules, err := ParseRegistryRules([]byte(`
+- prefix: docker.io/openebs/
+  replacement: mirror.example.com/dockerhub/openebs/
+- regex: ^quay\.io/(openebs|k8scsi)/(.+)$
+  replacement: mirror.example.com/quay/$1/$2
+- prefix: registry.k8s.io/
+  replacement: mirror.example.com/nested/k8s/registry/
+`))
+	if err != nil {
+		t.Fatalf("ParseRegistryRules() error = %v", err)
+	}
+	tests := []struct {
+		name    string
+		rules   RegistryRules
+		baseURL string
+		image   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "docker.io implicit registry",
+			rules: rules,
+			image: "openebs/cstor-pool:3.4.0",
+			want:  "mirror.example.com/dockerhub/openebs/cstor-pool",
+		},
+		{
+			name:  "docker.io explicit registry with arch suffix",
+			rules: rules,
+			image: "docker.io/openebs/cstor-istgt-amd64:3.4.0",
+			want:  "mirror.example.com/dockerhub/openebs/cstor-istgt",
+		},
+		{
+			name:  "quay.io",
+			rules: rules,
+			image: "quay.io/openebs/m-exporter:3.4.0",
+			want:  "mirror.example.com/quay/openebs/m-exporter",
+		},
+		{
+			name:  "nested mirror",
+			rules: rules,
+			image: "registry.k8s.io/sig-storage/csi-provisioner:v3.5.0",
+			want:  "mirror.example.com/nested/k8s/registry/sig-storage/csi-provisioner",
+		},
+		{
+			name:  "no matching rule",
+			rules: rules,
+			image: "ghcr.io/openebs/jiva@sha256:abcd",
+			want:  "ghcr.io/openebs/jiva",
+		},
+		{
+			name:    "first rule wins over the prefix",
+			rules:   rules,
+			baseURL: "xyz/openebsdirectory/",
+			image:   "openebs/jiva:3.4.0",
+			want:    "mirror.example.com/dockerhub/openebs/jiva",
+		},
+		{
+			name:    "prefix without matching rule",
+			rules:   rules,
+			baseURL: "xyz/aws-56546546/openebsdirectory/",
+			image:   "abc/quay.io/openebs/jiva:3.4.0",
+			want:    "xyz/aws-56546546/openebsdirectory/jiva",
+		},
+		{
+			name:    "prefix keeps the arch suffix of the mirror",
+			baseURL: "mymirror/",
+			image:   "openebs/cstor-istgt-amd64:3.4.0",
+			want:    "mymirror/cstor-istgt-amd64",
+		},
+		{
+			name:    "prefix under openebs drops the arch suffix",
+			baseURL: "mirror.example.com/openebs/",
+			image:   "openebs/cstor-istgt-amd64:3.4.0",
+			want:    "mirror.example.com/openebs/cstor-istgt",
+		},
+		{
+			name:    "no tag",
+			image:   "openebs/jiva",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ResourcePatch{RegistryRules: tt.rules, BaseURL: tt.baseURL}
+			got, err := r.imageRepository(tt.image)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("imageRepository() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("imageRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRegistryRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   string
+		wantErr bool
+	}{
+		{name: "prefix", rules: `[{prefix: quay.io/, replacement: mirror/quay/}]`},
+		{name: "regex", rules: `[{regex: "^(.*)$", replacement: "mirror/$1"}]`},
+		{name: "no match", rules: `[{replacement: mirror/}]`, wantErr: true},
+		{name: "prefix and regex", rules: `[{prefix: a/, regex: b, replacement: c/}]`, wantErr: true},
+		{name: "invalid regex", rules: `[{regex: "(", replacement: c/}]`, wantErr: true},
+		{name: "unknown field", rules: `[{prefix: a/, replace: c/}]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRegistryRules([]byte(tt.rules))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRegistryRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,415 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	cstor "github.com/openebs/api/v3/pkg/apis/cstor/v1"
+	"github.com/openebs/api/v3/pkg/apis/types"
+	jv "github.com/openebs/jiva-operator/pkg/apis/openebs/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"github.com/openebs/upgrade/pkg/upgrade/wait"
+)
+
+const (
+	// DefaultReplicaHealthTimeout is the default maximum time the volume
+	// replicas of a pool instance or of a jiva volume can take to be healthy
+	DefaultReplicaHealthTimeout = 30 * time.Minute
+	// JivaReplicaModeRW is the mode of a jiva replica in sync
+	// with the controller, and the status of a writable volume
+	JivaReplicaModeRW = "RW"
+)
+
+var (
+	// replicaHealthInterval is the interval between
+	// two checks of the health of the volume replicas
+	replicaHealthInterval = 10 * time.Second
+)
+
+// quorumBlockers returns the reasons restarting the given pool instance
+// would take a volume below quorum: a volume having a replica on the
+// pool which is not healthy, or having a replica on another pool which
+// is not healthy. The replicas are grouped by volume.
+func quorumBlockers(cspi string, cvs map[string]cstor.CStorVolume,
+	cvrs map[string][]cstor.CStorVolumeReplica) []string {
+	blockers := []string{}
+	for _, volume := range sortedVolumes(cvrs) {
+		replicas := cvrs[volume]
+		if !hasReplicaOn(cspi, replicas) {
+			continue
+		}
+		cv, ok := cvs[volume]
+		if !ok {
+			blockers = append(blockers, fmt.Sprintf("cstorvolume %s not found", volume))
+			continue
+		}
+		if cv.Status.Phase != cstor.CVStatusHealthy {
+			blockers = append(blockers,
+				fmt.Sprintf("cstorvolume %s is %s", volume, PhaseOf(string(cv.Status.Phase))))
+		}
+		for _, cvr := range replicas {
+			if cvr.Labels[types.CStorPoolInstanceNameLabelKey] == cspi {
+				continue
+			}
+			if cvr.Status.Phase != cstor.CVRStatusOnline {
+				blockers = append(blockers,
+					fmt.Sprintf("cvr %s is %s", cvr.Name, PhaseOf(string(cvr.Status.Phase))))
+			}
+		}
+	}
+	return blockers
+}
+
+// unhealthyReplicas returns the replicas on the given pool instance
+// which are not healthy or have not reported their status since the
+// given time, the status from before the pool restart is stale
+func unhealthyReplicas(cspi string, since time.Time,
+	cvrs map[string][]cstor.CStorVolumeReplica) []string {
+	unhealthy := []string{}
+	for _, volume := range sortedVolumes(cvrs) {
+		for _, cvr := range cvrs[volume] {
+			if cvr.Labels[types.CStorPoolInstanceNameLabelKey] != cspi {
+				continue
+			}
+			switch {
+			case cvr.Status.LastUpdateTime.Time.Before(since):
+				unhealthy = append(unhealthy,
+					fmt.Sprintf("cvr %s has not reported its status since the pool restart", cvr.Name))
+			case cvr.Status.Phase != cstor.CVRStatusOnline:
+				unhealthy = append(unhealthy,
+					fmt.Sprintf("cvr %s is %s", cvr.Name, PhaseOf(string(cvr.Status.Phase))))
+			}
+		}
+	}
+	return unhealthy
+}
+
+func hasReplicaOn(cspi string, cvrs []cstor.CStorVolumeReplica) bool {
+	for _, cvr := range cvrs {
+		if cvr.Labels[types.CStorPoolInstanceNameLabelKey] == cspi {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedVolumes(cvrs map[string][]cstor.CStorVolumeReplica) []string {
+	volumes := make([]string, 0, len(cvrs))
+	for volume := range cvrs {
+		volumes = append(volumes, volume)
+	}
+	sort.Strings(volumes)
+	return volumes
+}
+
+// PhaseOf returns the phase reported by a resource, or
+// a description of the missing phase if it is empty
+func PhaseOf(phase string) string {
+	if phase == "" {
+		return "not reporting its status"
+	}
+	return phase
+}
+
+// volumeReplicas returns the cvs and the cvrs of the openebs
+// namespace, the cvrs are grouped by the volume they belong to
+func (obj *CSPIPatch) volumeReplicas(ctx context.Context) (map[string]cstor.CStorVolume,
+	map[string][]cstor.CStorVolumeReplica, error) {
+	cvList, err := obj.OpenebsClientset.CstorV1().CStorVolumes(obj.Namespace).
+		List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to list cstorvolumes")
+	}
+	cvrList, err := obj.OpenebsClientset.CstorV1().CStorVolumeReplicas(obj.Namespace).
+		List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to list cstorvolumereplicas")
+	}
+	cvs := map[string]cstor.CStorVolume{}
+	for _, cv := range cvList.Items {
+		cvs[cv.Name] = cv
+	}
+	cvrs := map[string][]cstor.CStorVolumeReplica{}
+	for _, cvr := range cvrList.Items {
+		volume := cvr.Labels[types.PersistentVolumeLabelKey]
+		cvrs[volume] = append(cvrs[volume], cvr)
+	}
+	return cvs, cvrs, nil
+}
+
+// waitForReplicaHealth waits until the given check returns no reasons,
+// the last reasons are returned along with the error if it times out
+func (r *ResourcePatch) waitForReplicaHealth(ctx context.Context, desc string,
+	check func(ctx context.Context) ([]string, error)) error {
+	var reasons []string
+	condition := func(ctx context.Context) (bool, error) {
+		var err error
+		reasons, err = check(ctx)
+		if err != nil {
+			return false, err
+		}
+		if len(reasons) != 0 {
+			klog.Infof("Waiting for %s: %s", desc, strings.Join(reasons, ", "))
+			return false, nil
+		}
+		return true, nil
+	}
+	// check once before waiting as the
+	// replicas are healthy most of the time
+	done, err := condition(ctx)
+	if err != nil || done {
+		return err
+	}
+	err = wait.For(ctx, desc, replicaHealthInterval, r.ReplicaHealthTimeout, condition)
+	if err != nil && len(reasons) != 0 {
+		return errors.Wrap(err, strings.Join(reasons, ", "))
+	}
+	return err
+}
+
+// verifyReplicaQuorum waits until restarting the pool instance
+// does not take any volume having a replica on it below quorum
+func (obj *CSPIPatch) verifyReplicaQuorum() (string, error) {
+	if obj.SkipReplicaHealthCheck {
+		klog.Warningf("Skipping the replica quorum check of cspi %s", obj.Name)
+		return "", nil
+	}
+	err := obj.waitForReplicaHealth(obj.baseContext(), "replica quorum of "+obj.Name,
+		func(ctx context.Context) ([]string, error) {
+			cvs, cvrs, err := obj.volumeReplicas(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return quorumBlockers(obj.Name, cvs, cvrs), nil
+		},
+	)
+	if err != nil {
+		return "failed to verify replica quorum of the volumes on pool instance " + obj.Name, err
+	}
+	return "", nil
+}
+
+// poolPodStartTime returns the start time of the running pod of the
+// pool deployment, it is zero if the pod has not started or is not ready
+func (obj *CSPIPatch) poolPodStartTime(ctx context.Context) (time.Time, error) {
+	deploy := obj.Deploy.Object
+	podList, err := obj.KubeClientset.CoreV1().Pods(deploy.Namespace).
+		List(ctx, metav1.ListOptions{
+			LabelSelector: metav1.FormatLabelSelector(deploy.Spec.Selector),
+		})
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to list pods of deployment %s", deploy.Name)
+	}
+	var started time.Time
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil || pod.Status.StartTime == nil || !podReady(pod) {
+			continue
+		}
+		if pod.Status.StartTime.Time.After(started) {
+			started = pod.Status.StartTime.Time
+		}
+	}
+	return started, nil
+}
+
+// verifyReplicaRebuild waits until the volume replicas on the pool
+// instance are reported healthy by the new pool pod after its restart
+func (obj *CSPIPatch) verifyReplicaRebuild(ctx context.Context) (string, error) {
+	if obj.SkipReplicaHealthCheck {
+		return "", nil
+	}
+	err := obj.waitForReplicaHealth(ctx, "replica rebuild of "+obj.Name,
+		func(ctx context.Context) ([]string, error) {
+			started, err := obj.poolPodStartTime(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if started.IsZero() {
+				return []string{"pool pod of " + obj.Name + " is not ready"}, nil
+			}
+			_, cvrs, err := obj.volumeReplicas(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return unhealthyReplicas(obj.Name, started, cvrs), nil
+		},
+	)
+	if err != nil {
+		return "failed to verify replica rebuild on pool instance " + obj.Name, err
+	}
+	return "", nil
+}
+
+// jivaReplicaBlockers returns the reasons the jiva volume is not
+// healthy: the volume is not read-write, some replicas have not
+// registered with the controller or are not in RW mode
+func jivaReplicaBlockers(v *jv.JivaVolume) []string {
+	blockers := []string{}
+	if v.Status.Phase != jv.JivaVolumePhaseReady || v.Status.Status != JivaReplicaModeRW {
+		blockers = append(blockers, fmt.Sprintf("jivavolume %s is %s with status %s",
+			v.Name, PhaseOf(string(v.Status.Phase)), PhaseOf(v.Status.Status)))
+	}
+	replicas := v.Spec.Policy.Target.ReplicationFactor
+	if len(v.Status.ReplicaStatuses) < replicas {
+		blockers = append(blockers, fmt.Sprintf("%d of %d replicas registered",
+			len(v.Status.ReplicaStatuses), replicas))
+	}
+	for _, rep := range v.Status.ReplicaStatuses {
+		if rep.Mode != JivaReplicaModeRW {
+			blockers = append(blockers, fmt.Sprintf("replica %s is %s",
+				rep.Address, PhaseOf(rep.Mode)))
+		}
+	}
+	return blockers
+}
+
+// jivaPodBlockers returns the reasons the replicas of the given
+// pods are not in sync: the pod is not ready, or its replica, matched
+// by the pod IP, has not registered with the controller or is not in
+// RW mode. The stale status of a restarted replica has the IP of its
+// previous pod, so it does not match the new pod.
+func jivaPodBlockers(v *jv.JivaVolume, pods []corev1.Pod) []string {
+	blockers := []string{}
+	for i := range pods {
+		pod := &pods[i]
+		if !podReady(pod) {
+			blockers = append(blockers, fmt.Sprintf("pod %s is not ready", pod.Name))
+			continue
+		}
+		rep, ok := jivaReplicaOf(v, pod.Status.PodIP)
+		if !ok {
+			blockers = append(blockers, fmt.Sprintf("replica of pod %s at %s has not registered",
+				pod.Name, pod.Status.PodIP))
+			continue
+		}
+		if rep.Mode != JivaReplicaModeRW {
+			blockers = append(blockers, fmt.Sprintf("replica of pod %s is %s",
+				pod.Name, PhaseOf(rep.Mode)))
+		}
+	}
+	return blockers
+}
+
+// jivaReplicaOf returns the status of the replica with the given IP,
+// the replicas register with an address like tcp://10.0.0.1:9502
+func jivaReplicaOf(v *jv.JivaVolume, ip string) (jv.ReplicaStatus, bool) {
+	for _, rep := range v.Status.ReplicaStatuses {
+		address := rep.Address
+		if i := strings.Index(address, "://"); i != -1 {
+			address = address[i+len("://"):]
+		}
+		if host, _, err := net.SplitHostPort(address); err == nil {
+			address = host
+		}
+		if ip != "" && address == ip {
+			return rep, true
+		}
+	}
+	return jv.ReplicaStatus{}, false
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// verifyReplicaRebuild waits until the replicas of the volume have
+// rejoined the controller in RW mode after their restart, the replica
+// of every current pod has to be registered so that the status from
+// before the rollout is not taken as healthy
+func (obj *JivaVolumePatch) verifyReplicaRebuild(ctx context.Context) (string, error) {
+	if obj.SkipReplicaHealthCheck {
+		klog.Warningf("Skipping the replica rebuild check of jiva volume %s", obj.Name)
+		return "", nil
+	}
+	sts := obj.Replicas.Object
+	err := obj.waitForJivaReplicas(ctx, "replica rebuild of "+obj.Name,
+		func(ctx context.Context) ([]corev1.Pod, error) {
+			podList, err := obj.KubeClientset.CoreV1().Pods(sts.Namespace).
+				List(ctx, metav1.ListOptions{
+					LabelSelector: metav1.FormatLabelSelector(sts.Spec.Selector),
+				})
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to list pods of statefulset %s", sts.Name)
+			}
+			return podList.Items, nil
+		},
+	)
+	if err != nil {
+		return "failed to verify replica rebuild of jiva volume " + obj.Name, err
+	}
+	return "", nil
+}
+
+// verifyReplicaResync waits until the replica pod of the given
+// ordinal has resynced with the controller after its update,
+// before the next replica pod is updated
+func (obj *JivaVolumePatch) verifyReplicaResync(ctx context.Context, ordinal int32) error {
+	if obj.SkipReplicaHealthCheck {
+		return nil
+	}
+	name := fmt.Sprintf("%s-%d", obj.Replicas.Object.Name, ordinal)
+	return obj.waitForJivaReplicas(ctx, "replica resync of "+name,
+		func(ctx context.Context) ([]corev1.Pod, error) {
+			pod, err := obj.KubeClientset.CoreV1().Pods(obj.Replicas.Object.Namespace).
+				Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get pod %s", name)
+			}
+			return []corev1.Pod{*pod}, nil
+		},
+	)
+}
+
+// waitForJivaReplicas waits until the jiva volume is healthy and the
+// replicas of the pods returned by the given function are in sync
+func (obj *JivaVolumePatch) waitForJivaReplicas(ctx context.Context, desc string,
+	replicaPods func(ctx context.Context) ([]corev1.Pod, error)) error {
+	return obj.waitForReplicaHealth(ctx, desc,
+		func(ctx context.Context) ([]string, error) {
+			// the JivaVolumeCR object is the base of its
+			// patch, so the status is read into a new object
+			v := &jv.JivaVolume{}
+			err := obj.RuntimeClient.Get(ctx,
+				k8stypes.NamespacedName{Name: obj.Name, Namespace: obj.Namespace}, v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get jivavolume %s", obj.Name)
+			}
+			pods, err := replicaPods(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return append(jivaReplicaBlockers(v), jivaPodBlockers(v, pods)...), nil
+		},
+	)
+}
@@ -0,0 +1,452 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	cstor "github.com/openebs/api/v3/pkg/apis/cstor/v1"
+	"github.com/openebs/api/v3/pkg/apis/types"
+	openebsFakeClientset "github.com/openebs/api/v3/pkg/client/clientset/versioned/fake"
+	jv "github.com/openebs/jiva-operator/pkg/apis/openebs/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openebs/upgrade/pkg/kubeclient"
+	"github.com/openebs/upgrade/pkg/upgrade/patch"
+)
+
+func testCV(name string, phase cstor.CStorVolumePhase) *cstor.CStorVolume {
+	return &cstor.CStorVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "openebs"},
+		Status:     cstor.CStorVolumeStatus{Phase: phase},
+	}
+}
+
+func testCVR(volume, cspi string, phase cstor.CStorVolumeReplicaPhase) *cstor.CStorVolumeReplica {
+	return &cstor.CStorVolumeReplica{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      volume + "-" + cspi,
+			Namespace: "openebs",
+			Labels: map[string]string{
+				types.PersistentVolumeLabelKey:      volume,
+				types.CStorPoolInstanceNameLabelKey: cspi,
+			},
+		},
+		Status: cstor.CStorVolumeReplicaStatus{Phase: phase},
+	}
+}
+
+func Test_quorumBlockers(t *testing.T) {
+	tests := map[string]struct {
+		objs []runtime.Object
+		want []string
+	}{
+		"all healthy": {
+			objs: []runtime.Object{
+				testCV("pv-1", cstor.CVStatusHealthy),
+				testCVR("pv-1", "cspi-a", cstor.CVRStatusOnline),
+				testCVR("pv-1", "cspi-b", cstor.CVRStatusOnline),
+				testCVR("pv-1", "cspi-c", cstor.CVRStatusOnline),
+			},
+			want: []string{},
+		},
+		"replica rebuilding on another pool": {
+			objs: []runtime.Object{
+				testCV("pv-1", cstor.CVStatusDegraded),
+				testCVR("pv-1", "cspi-a", cstor.CVRStatusOnline),
+				testCVR("pv-1", "cspi-b", cstor.CVRStatusRebuilding),
+				testCVR("pv-1", "cspi-c", cstor.CVRStatusOnline),
+			},
+			want: []string{"cstorvolume pv-1 is Degraded", "cvr pv-1-cspi-b is Rebuilding"},
+		},
+		"replica on the pool itself is not checked": {
+			objs: []runtime.Object{
+				testCV("pv-1", cstor.CVStatusHealthy),
+				testCVR("pv-1", "cspi-a", cstor.CVRStatusOffline),
+				testCVR("pv-1", "cspi-b", cstor.CVRStatusOnline),
+			},
+			want: []string{},
+		},
+		"volume without a replica on the pool": {
+			objs: []runtime.Object{
+				testCV("pv-2", cstor.CVStatusOffline),
+				testCVR("pv-2", "cspi-b", cstor.CVRStatusOffline),
+			},
+			want: []string{},
+		},
+		"missing volume": {
+			objs: []runtime.Object{
+				testCVR("pv-3", "cspi-a", cstor.CVRStatusOnline),
+			},
+			want: []string{"cstorvolume pv-3 not found"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := &CSPIPatch{
+				ResourcePatch: &ResourcePatch{Name: "cspi-a"},
+				Namespace:     "openebs",
+				Client: &Client{
+					OpenebsClientset: openebsFakeClientset.NewSimpleClientset(test.objs...),
+				},
+			}
+			cvs, cvrs, err := obj.volumeReplicas(obj.baseContext())
+			if err != nil {
+				t.Fatalf("volumeReplicas() error = %v", err)
+			}
+			got := quorumBlockers("cspi-a", cvs, cvrs)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("quorumBlockers() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_unhealthyReplicas(t *testing.T) {
+	started := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	reported := func(cvr *cstor.CStorVolumeReplica, at time.Time) cstor.CStorVolumeReplica {
+		cvr.Status.LastUpdateTime = metav1.NewTime(at)
+		return *cvr
+	}
+	cvrs := map[string][]cstor.CStorVolumeReplica{
+		"pv-1": {
+			reported(testCVR("pv-1", "cspi-a", cstor.CVRStatusOnline), started.Add(time.Minute)),
+			reported(testCVR("pv-1", "cspi-b", cstor.CVRStatusRebuilding), started.Add(time.Minute)),
+		},
+		"pv-2": {
+			reported(testCVR("pv-2", "cspi-a", ""), started.Add(time.Minute)),
+		},
+		"pv-3": {
+			reported(testCVR("pv-3", "cspi-a", cstor.CVRStatusOnline), started.Add(-time.Minute)),
+		},
+	}
+	want := []string{
+		"cvr pv-2-cspi-a is not reporting its status",
+		"cvr pv-3-cspi-a has not reported its status since the pool restart",
+	}
+	got := unhealthyReplicas("cspi-a", started, cvrs)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unhealthyReplicas() = %v, want %v", got, want)
+	}
+}
+
+func Test_verifyReplicaQuorum(t *testing.T) {
+	defer func(interval time.Duration) {
+		replicaHealthInterval = interval
+	}(replicaHealthInterval)
+	replicaHealthInterval = 10 * time.Millisecond
+	tests := map[string]struct {
+		skip    bool
+		wantErr bool
+	}{
+		"times out": {
+			wantErr: true,
+		},
+		"skipped": {
+			skip: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := &CSPIPatch{
+				ResourcePatch: &ResourcePatch{
+					Name:                   "cspi-a",
+					SkipReplicaHealthCheck: test.skip,
+					ReplicaHealthTimeout:   50 * time.Millisecond,
+				},
+				Namespace: "openebs",
+				Client: &Client{
+					OpenebsClientset: openebsFakeClientset.NewSimpleClientset(
+						testCV("pv-1", cstor.CVStatusDegraded),
+						testCVR("pv-1", "cspi-a", cstor.CVRStatusOnline),
+						testCVR("pv-1", "cspi-b", cstor.CVRStatusRebuilding),
+					),
+				},
+			}
+			_, err := obj.verifyReplicaQuorum()
+			if (err != nil) != test.wantErr {
+				t.Fatalf("verifyReplicaQuorum() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func testJivaVolume(phase jv.JivaVolumePhase, status string, modes ...string) *jv.JivaVolume {
+	v := &jv.JivaVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1", Namespace: "openebs"},
+		Status: jv.JivaVolumeStatus{
+			Phase:        phase,
+			Status:       status,
+			ReplicaCount: len(modes),
+		},
+	}
+	v.Spec.Policy.Target.ReplicationFactor = 3
+	for i, mode := range modes {
+		v.Status.ReplicaStatuses = append(v.Status.ReplicaStatuses, jv.ReplicaStatus{
+			Address: fmt.Sprintf("tcp://10.0.0.%d:9502", i+1),
+			Mode:    mode,
+		})
+	}
+	return v
+}
+
+func Test_jivaReplicaBlockers(t *testing.T) {
+	tests := map[string]struct {
+		volume *jv.JivaVolume
+		want   []string
+	}{
+		"healthy": {
+			volume: testJivaVolume(jv.JivaVolumePhaseReady, "RW", "RW", "RW", "RW"),
+			want:   []string{},
+		},
+		"replica rebuilding": {
+			volume: testJivaVolume(jv.JivaVolumePhaseReady, "RW", "RW", "WO", "RW"),
+			want:   []string{"replica tcp://10.0.0.2:9502 is WO"},
+		},
+		"replica not registered": {
+			volume: testJivaVolume(jv.JivaVolumePhaseReady, "RW", "RW", "RW"),
+			want:   []string{"2 of 3 replicas registered"},
+		},
+		"volume read-only": {
+			volume: testJivaVolume(jv.JivaVolumePhaseSyncing, "RO", "ERR", "WO"),
+			want: []string{
+				"jivavolume pv-1 is Syncing with status RO",
+				"2 of 3 replicas registered",
+				"replica tcp://10.0.0.1:9502 is ERR",
+				"replica tcp://10.0.0.2:9502 is WO",
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := jivaReplicaBlockers(test.volume)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("jivaReplicaBlockers() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func testReplicaPod(name, ip string, ready bool) corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "openebs"},
+		Status: corev1.PodStatus{
+			PodIP:      ip,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func Test_jivaPodBlockers(t *testing.T) {
+	volume := testJivaVolume(jv.JivaVolumePhaseReady, "RW", "RW", "WO")
+	tests := map[string]struct {
+		pods []corev1.Pod
+		want []string
+	}{
+		"replica in sync": {
+			pods: []corev1.Pod{testReplicaPod("pv-1-rep-0", "10.0.0.1", true)},
+			want: []string{},
+		},
+		"replica rebuilding": {
+			pods: []corev1.Pod{testReplicaPod("pv-1-rep-1", "10.0.0.2", true)},
+			want: []string{"replica of pod pv-1-rep-1 is WO"},
+		},
+		"stale status of the previous pod": {
+			pods: []corev1.Pod{testReplicaPod("pv-1-rep-2", "10.0.0.9", true)},
+			want: []string{"replica of pod pv-1-rep-2 at 10.0.0.9 has not registered"},
+		},
+		"pod not ready": {
+			pods: []corev1.Pod{testReplicaPod("pv-1-rep-0", "10.0.0.1", false)},
+			want: []string{"pod pv-1-rep-0 is not ready"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := jivaPodBlockers(volume, test.pods)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("jivaPodBlockers() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_verifyReplicaResync(t *testing.T) {
+	defer func(interval time.Duration) {
+		replicaHealthInterval = interval
+	}(replicaHealthInterval)
+	replicaHealthInterval = 10 * time.Millisecond
+	tests := map[string]struct {
+		podIP   string
+		wantErr bool
+	}{
+		"replica registered": {
+			podIP: "10.0.0.3",
+		},
+		"replica of the previous pod": {
+			podIP:   "10.0.0.9",
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			pod := testReplicaPod("pv-1-rep-2", test.podIP, true)
+			obj := &JivaVolumePatch{
+				ResourcePatch: &ResourcePatch{
+					Name:                 "pv-1",
+					ReplicaHealthTimeout: 50 * time.Millisecond,
+				},
+				Namespace: "openebs",
+				Replicas: &patch.StatefulSet{
+					Object: &appsv1.StatefulSet{
+						ObjectMeta: metav1.ObjectMeta{Name: "pv-1-rep", Namespace: "openebs"},
+					},
+				},
+				Client: &Client{
+					KubeClientset: fake.NewSimpleClientset(&pod),
+					RuntimeClient: ctrlfake.NewClientBuilder().WithScheme(kubeclient.Scheme()).
+						WithObjects(testJivaVolume(jv.JivaVolumePhaseReady, "RW", "RW", "RW", "RW")).
+						Build(),
+				},
+			}
+			err := obj.verifyReplicaResync(context.TODO(), 2)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("verifyReplicaResync() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_jivaVerifyReplicaRebuild(t *testing.T) {
+	defer func(interval time.Duration) {
+		replicaHealthInterval = interval
+	}(replicaHealthInterval)
+	replicaHealthInterval = 10 * time.Millisecond
+	tests := map[string]struct {
+		podIPs  []string
+		wantErr bool
+	}{
+		"replicas of the current pods registered": {
+			podIPs: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"},
+		},
+		"status from before the rollout": {
+			podIPs:  []string{"10.0.0.1", "10.0.0.2", "10.0.0.9"},
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			selector := map[string]string{"openebs.io/component": "jiva-replica"}
+			objs := []runtime.Object{}
+			for i, ip := range test.podIPs {
+				pod := testReplicaPod(fmt.Sprintf("pv-1-rep-%d", i), ip, true)
+				pod.Labels = selector
+				objs = append(objs, &pod)
+			}
+			obj := &JivaVolumePatch{
+				ResourcePatch: &ResourcePatch{
+					Name:                 "pv-1",
+					ReplicaHealthTimeout: 50 * time.Millisecond,
+				},
+				Namespace: "openebs",
+				Replicas: &patch.StatefulSet{
+					Object: &appsv1.StatefulSet{
+						ObjectMeta: metav1.ObjectMeta{Name: "pv-1-rep", Namespace: "openebs"},
+						Spec: appsv1.StatefulSetSpec{
+							Selector: &metav1.LabelSelector{MatchLabels: selector},
+						},
+					},
+				},
+				Client: &Client{
+					KubeClientset: fake.NewSimpleClientset(objs...),
+					RuntimeClient: ctrlfake.NewClientBuilder().WithScheme(kubeclient.Scheme()).
+						WithObjects(testJivaVolume(jv.JivaVolumePhaseReady, "RW", "RW", "RW", "RW")).
+						Build(),
+				},
+			}
+			_, err := obj.verifyReplicaRebuild(context.TODO())
+			if (err != nil) != test.wantErr {
+				t.Fatalf("verifyReplicaRebuild() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_cspiVerifyReplicaRebuild(t *testing.T) {
+	defer func(interval time.Duration) {
+		replicaHealthInterval = interval
+	}(replicaHealthInterval)
+	replicaHealthInterval = 10 * time.Millisecond
+	started := time.Now().Add(-time.Minute).Truncate(time.Second)
+	tests := map[string]struct {
+		reported time.Time
+		wantErr  bool
+	}{
+		"reported by the new pool pod": {
+			reported: started.Add(30 * time.Second),
+		},
+		"reported before the restart": {
+			reported: started.Add(-30 * time.Second),
+			wantErr:  true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			selector := map[string]string{"openebs.io/cstor-pool-instance": "cspi-a"}
+			pod := testReplicaPod("cspi-a-7d9f", "10.0.0.1", true)
+			pod.Labels = selector
+			pod.Status.StartTime = &metav1.Time{Time: started}
+			cvr := testCVR("pv-1", "cspi-a", cstor.CVRStatusOnline)
+			cvr.Status.LastUpdateTime = metav1.NewTime(test.reported)
+			obj := &CSPIPatch{
+				ResourcePatch: &ResourcePatch{
+					Name:                 "cspi-a",
+					ReplicaHealthTimeout: 50 * time.Millisecond,
+				},
+				Namespace: "openebs",
+				Deploy: &patch.Deployment{
+					Object: &appsv1.Deployment{
+						ObjectMeta: metav1.ObjectMeta{Name: "cspi-a", Namespace: "openebs"},
+						Spec: appsv1.DeploymentSpec{
+							Selector: &metav1.LabelSelector{MatchLabels: selector},
+						},
+					},
+				},
+				Client: &Client{
+					KubeClientset:    fake.NewSimpleClientset(&pod),
+					OpenebsClientset: openebsFakeClientset.NewSimpleClientset(cvr),
+				},
+			}
+			_, err := obj.verifyReplicaRebuild(context.TODO())
+			if (err != nil) != test.wantErr {
+				t.Fatalf("verifyReplicaRebuild() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -16,12 +16,46 @@ limitations under the License.
 
 package upgrader
 
+import (
+	"context"
+	"time"
+)
+
 // ResourcePatch has all the patches required to upgrade a resource
 type ResourcePatch struct {
 	Name              string
 	OpenebsNamespace  string
 	From, To          string
 	ImageTag, BaseURL string
+	// ImageMapping overrides the images of specific containers
+	ImageMapping ImageMapping
+	// RegistryRules rewrite the repositories of the images,
+	// they take precedence over the BaseURL
+	RegistryRules RegistryRules
+	// Context bounds the whole upgrade of the resource
+	Context context.Context
+	// StepTimeout bounds each step of the upgrade
+	StepTimeout time.Duration
+	// ImagePullCheck pulls the new images on the nodes of the
+	// workloads before they are patched, bounded by the timeout
+	ImagePullCheck   bool
+	ImagePullTimeout time.Duration
+	// SkipReplicaHealthCheck restarts the pool instances and the
+	// jiva controllers without waiting for the volume replicas
+	// to be healthy, ReplicaHealthTimeout bounds each of these waits
+	SkipReplicaHealthCheck bool
+	ReplicaHealthTimeout   time.Duration
+	// IsUpgradeTaskJob fails the upgrade if the UpgradeTask
+	// of the resource cannot be updated
+	IsUpgradeTaskJob bool
+	// MaxRetries is the number of failed attempts after which the
+	// UpgradeTask of a dependant resource, like a cspi, is failed,
+	// it is set by the controller and the backoffLimit of the job
+	// running the upgrade is used otherwise
+	MaxRetries int
+	// PatchRecorder is called with every patch applied
+	// during the upgrade, like for the report of the run
+	PatchRecorder func(Change)
 	// UpgradeTask       *utask.UpgradeTask
 }
 
@@ -70,11 +104,96 @@ func WithBaseURL(url string) ResourcePatchOptions {
 	}
 }
 
+// WithImageMapping ...
+func WithImageMapping(m ImageMapping) ResourcePatchOptions {
+	return func(r *ResourcePatch) {
+		r.ImageMapping = m
+	}
+}
+
+// WithRegistryRules ...
+func WithRegistryRules(rules RegistryRules) ResourcePatchOptions {
+	return func(r *ResourcePatch) {
+		r.RegistryRules = rules
+	}
+}
+
+// WithContext ...
+func WithContext(ctx context.Context) ResourcePatchOptions {
+	return func(r *ResourcePatch) {
+		r.Context = ctx
+	}
+}
+
+// WithStepTimeout ...
+func WithStepTimeout(timeout time.Duration) ResourcePatchOptions {
+	return func(r *ResourcePatch) {
+		r.StepTimeout = timeout
+	}
+}
+
+// WithImagePullCheck ...
+func WithImagePullCheck(check bool, timeout time.Duration) ResourcePatchOptions {
+	return func(r *ResourcePatch) {
+		r.ImagePullCheck = check
+		r.ImagePullTimeout = timeout
+	}
+}
+
+// WithReplicaHealthCheck ...
+func WithReplicaHealthCheck(skip bool, timeout time.Duration) ResourcePatchOptions {
+	return func(r *ResourcePatch) {
+		r.SkipReplicaHealthCheck = skip
+		r.ReplicaHealthTimeout = timeout
+	}
+}
+
+// WithPatchRecorder sets the function called
+// with every patch applied during the upgrade
+func WithPatchRecorder(recorder func(Change)) ResourcePatchOptions {
+	return func(r *ResourcePatch) {
+		r.PatchRecorder = recorder
+	}
+}
+
+// WithUpgradeTaskJob ...
+func WithUpgradeTaskJob(isUpgradeTaskJob bool) ResourcePatchOptions {
+	return func(r *ResourcePatch) {
+		r.IsUpgradeTaskJob = isUpgradeTaskJob
+	}
+}
+
+// WithMaxRetries ...
+func WithMaxRetries(n int) ResourcePatchOptions {
+	return func(r *ResourcePatch) {
+		r.MaxRetries = n
+	}
+}
+
 // NewResourcePatch returns a new instance of ResourcePatch
 func NewResourcePatch(opts ...ResourcePatchOptions) *ResourcePatch {
-	r := &ResourcePatch{}
+	r := &ResourcePatch{
+		ReplicaHealthTimeout: DefaultReplicaHealthTimeout,
+	}
 	for _, o := range opts {
 		o(r)
 	}
 	return r
 }
+
+// baseContext returns the context bounding the whole upgrade
+func (r *ResourcePatch) baseContext() context.Context {
+	if r.Context == nil {
+		return context.Background()
+	}
+	return r.Context
+}
+
+// stepContext returns the context for a step of the upgrade,
+// bounded by the step timeout if one is set
+func (r *ResourcePatch) stepContext() (context.Context, context.CancelFunc) {
+	if r.StepTimeout > 0 {
+		return context.WithTimeout(r.baseContext(), r.StepTimeout)
+	}
+	return context.WithCancel(r.baseContext())
+}
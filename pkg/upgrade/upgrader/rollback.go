@@ -0,0 +1,299 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	cstor "github.com/openebs/api/v3/pkg/apis/cstor/v1"
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	jv "github.com/openebs/jiva-operator/pkg/apis/openebs/v1"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/openebs/upgrade/pkg/upgrade/patch"
+)
+
+const (
+	backupLabel           = "openebs.io/upgrade-backup"
+	fromVersionAnnotation = "openebs.io/from-version"
+	toVersionAnnotation   = "openebs.io/to-version"
+)
+
+// backupObject is an object recorded before the upgrade
+type backupObject struct {
+	kind string
+	obj  metav1.Object
+}
+
+func backupName(kind, name string) string {
+	switch kind {
+	case "cstorPoolInstance":
+		return "upgrade-backup-cstor-cspi-" + name
+	case "cstorPoolCluster":
+		return "upgrade-backup-cstor-cspc-" + name
+	case "cstorVolume":
+		return "upgrade-backup-cstor-csi-volume-" + name
+	case "jivaVolume":
+		return "upgrade-backup-jiva-csi-volume-" + name
+	}
+	return "upgrade-backup-" + strings.ToLower(kind) + "-" + name
+}
+
+func backupKey(kind, name string) string {
+	return strings.ToLower(kind) + "." + name
+}
+
+// saveBackup records the given objects in a configmap so that the
+// resource can be rolled back if the upgrade fails. A backup taken
+// for the same upgrade by a previous attempt is retained as the
+// objects may already be partially patched.
+func saveBackup(kind string, r *ResourcePatch, client *Client, objs ...backupObject) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      backupName(kind, r.Name),
+			Namespace: r.OpenebsNamespace,
+			Labels: map[string]string{
+				backupLabel: "true",
+			},
+			Annotations: map[string]string{
+				fromVersionAnnotation: r.From,
+				toVersionAnnotation:   r.To,
+			},
+		},
+		Data: map[string]string{},
+	}
+	for _, o := range objs {
+		// managed fields are not required to restore the object
+		o.obj.SetManagedFields(nil)
+		data, err := json.Marshal(o.obj)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal %s %s", o.kind, o.obj.GetName())
+		}
+		cm.Data[backupKey(o.kind, o.obj.GetName())] = string(data)
+	}
+	cmClient := client.KubeClientset.CoreV1().ConfigMaps(r.OpenebsNamespace)
+	existing, err := cmClient.Get(context.TODO(), cm.Name, metav1.GetOptions{})
+	if err == nil {
+		if existing.Annotations[fromVersionAnnotation] == r.From &&
+			existing.Annotations[toVersionAnnotation] == r.To {
+			klog.Infof("backup %s already present", cm.Name)
+			return nil
+		}
+		// the backup belongs to an earlier upgrade, replace it
+		cm.ResourceVersion = existing.ResourceVersion
+		_, err = cmClient.Update(context.TODO(), cm, metav1.UpdateOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to update backup %s", cm.Name)
+		}
+		return nil
+	}
+	if !k8serror.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get backup %s", cm.Name)
+	}
+	_, err = cmClient.Create(context.TODO(), cm, metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create backup %s", cm.Name)
+	}
+	return nil
+}
+
+func getBackup(kind string, r *ResourcePatch, client *Client) (*corev1.ConfigMap, error) {
+	name := backupName(kind, r.Name)
+	cm, err := client.KubeClientset.CoreV1().ConfigMaps(r.OpenebsNamespace).
+		Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get backup %s", name)
+	}
+	return cm, nil
+}
+
+func getBackupObject(cm *corev1.ConfigMap, kind, name string, into interface{}) error {
+	data, ok := cm.Data[backupKey(kind, name)]
+	if !ok {
+		return errors.Errorf("%s %s not found in backup %s", kind, name, cm.Name)
+	}
+	return json.Unmarshal([]byte(data), into)
+}
+
+// getUpgradeTaskForRollback fetches the upgradetask of the resource, unlike
+// getOrCreateUpgradeTask it retains the statuses of the failed upgrade
+func getUpgradeTaskForRollback(kind string, r *ResourcePatch, client *Client) (*v1Alpha1API.UpgradeTask, error) {
+	utaskObj := buildUpgradeTask(kind, r)
+	existing, err := client.OpenebsClientset.OpenebsV1alpha1().
+		UpgradeTasks(r.OpenebsNamespace).
+		Get(context.TODO(), utaskObj.Name, metav1.GetOptions{})
+	if err == nil {
+		return existing, nil
+	}
+	if !k8serror.IsNotFound(err) {
+		return nil, err
+	}
+	return client.OpenebsClientset.OpenebsV1alpha1().
+		UpgradeTasks(r.OpenebsNamespace).Create(context.TODO(),
+		utaskObj, metav1.CreateOptions{})
+}
+
+// setRollbackVersions sets the versions of the resource patch
+// to move from the upgraded version to the recorded version
+func setRollbackVersions(r *ResourcePatch, cm *corev1.ConfigMap) {
+	r.From = cm.Annotations[toVersionAnnotation]
+	r.To = cm.Annotations[fromVersionAnnotation]
+}
+
+func rollbackDeployment(ctx context.Context, d *patch.Deployment, cm *corev1.ConfigMap) error {
+	old := &appsv1.Deployment{}
+	err := getBackupObject(cm, "Deployment", d.Object.Name, old)
+	if err != nil {
+		return err
+	}
+	newDeploy := d.Object.DeepCopy()
+	newDeploy.Labels = old.Labels
+	newDeploy.Spec.Template = old.Spec.Template
+	d.Data, err = GetPatchData(d.Object, newDeploy)
+	if err != nil {
+		return err
+	}
+	return d.Patch(ctx, d.Object.Labels["openebs.io/version"], old.Labels["openebs.io/version"])
+}
+
+func rollbackStatefulSet(ctx context.Context, s *patch.StatefulSet, cm *corev1.ConfigMap) error {
+	old := &appsv1.StatefulSet{}
+	err := getBackupObject(cm, "StatefulSet", s.Object.Name, old)
+	if err != nil {
+		return err
+	}
+	newSTS := s.Object.DeepCopy()
+	newSTS.Labels = old.Labels
+	newSTS.Spec.Template = old.Spec.Template
+	s.Data, err = GetPatchData(s.Object, newSTS)
+	if err != nil {
+		return err
+	}
+	return s.Patch(ctx, s.Object.Labels["openebs.io/version"], old.Labels["openebs.io/version"])
+}
+
+func rollbackService(ctx context.Context, s *patch.Service, cm *corev1.ConfigMap) error {
+	old := &corev1.Service{}
+	err := getBackupObject(cm, "Service", s.Object.Name, old)
+	if err != nil {
+		return err
+	}
+	newSVC := s.Object.DeepCopy()
+	newSVC.Labels = old.Labels
+	s.Data, err = GetPatchData(s.Object, newSVC)
+	if err != nil {
+		return err
+	}
+	return s.Patch(ctx, s.Object.Labels["openebs.io/version"], old.Labels["openebs.io/version"])
+}
+
+func rollbackCV(ctx context.Context, c *patch.CV, cm *corev1.ConfigMap) error {
+	old := &cstor.CStorVolume{}
+	err := getBackupObject(cm, "CStorVolume", c.Object.Name, old)
+	if err != nil {
+		return err
+	}
+	newCV := c.Object.DeepCopy()
+	newCV.Labels = old.Labels
+	newCV.VersionDetails.Desired = old.VersionDetails.Desired
+	c.Data, err = GetPatchData(c.Object, newCV)
+	if err != nil {
+		return err
+	}
+	return c.Patch(ctx, c.Object.VersionDetails.Desired, old.VersionDetails.Desired)
+}
+
+func rollbackCVC(ctx context.Context, c *patch.CVC, cm *corev1.ConfigMap) error {
+	old := &cstor.CStorVolumeConfig{}
+	err := getBackupObject(cm, "CStorVolumeConfig", c.Object.Name, old)
+	if err != nil {
+		return err
+	}
+	newCVC := c.Object.DeepCopy()
+	newCVC.Labels = old.Labels
+	newCVC.VersionDetails.Desired = old.VersionDetails.Desired
+	c.Data, err = GetPatchData(c.Object, newCVC)
+	if err != nil {
+		return err
+	}
+	return c.Patch(ctx, c.Object.VersionDetails.Desired, old.VersionDetails.Desired)
+}
+
+func rollbackCVR(ctx context.Context, c *patch.CVR, cm *corev1.ConfigMap) error {
+	old := &cstor.CStorVolumeReplica{}
+	err := getBackupObject(cm, "CStorVolumeReplica", c.Object.Name, old)
+	if err != nil {
+		return err
+	}
+	newCVR := c.Object.DeepCopy()
+	newCVR.Labels = old.Labels
+	newCVR.VersionDetails.Desired = old.VersionDetails.Desired
+	c.Data, err = GetPatchData(c.Object, newCVR)
+	if err != nil {
+		return err
+	}
+	return c.Patch(ctx, c.Object.VersionDetails.Desired, old.VersionDetails.Desired)
+}
+
+func rollbackCSPI(ctx context.Context, c *patch.CSPI, cm *corev1.ConfigMap) error {
+	old := &cstor.CStorPoolInstance{}
+	err := getBackupObject(cm, "CStorPoolInstance", c.Object.Name, old)
+	if err != nil {
+		return err
+	}
+	newCSPI := c.Object.DeepCopy()
+	newCSPI.Labels = old.Labels
+	newCSPI.VersionDetails.Desired = old.VersionDetails.Desired
+	c.Data, err = GetPatchData(c.Object, newCSPI)
+	if err != nil {
+		return err
+	}
+	return c.Patch(ctx, c.Object.Labels["openebs.io/version"], old.Labels["openebs.io/version"])
+}
+
+func rollbackCSPC(ctx context.Context, c *patch.CSPC, cm *corev1.ConfigMap) error {
+	old := &cstor.CStorPoolCluster{}
+	err := getBackupObject(cm, "CStorPoolCluster", c.Object.Name, old)
+	if err != nil {
+		return err
+	}
+	newCSPC := c.Object.DeepCopy()
+	newCSPC.VersionDetails.Desired = old.VersionDetails.Desired
+	c.Data, err = GetPatchData(c.Object, newCSPC)
+	if err != nil {
+		return err
+	}
+	return c.Patch(ctx, c.Object.VersionDetails.Desired, old.VersionDetails.Desired)
+}
+
+func rollbackJV(ctx context.Context, j *patch.JV, cm *corev1.ConfigMap) error {
+	old := &jv.JivaVolume{}
+	err := getBackupObject(cm, "JivaVolume", j.Object.Name, old)
+	if err != nil {
+		return err
+	}
+	j.NewObject = j.Object.DeepCopy()
+	j.NewObject.VersionDetails.Desired = old.VersionDetails.Desired
+	return j.Patch(ctx, j.Object.VersionDetails.Desired, old.VersionDetails.Desired)
+}
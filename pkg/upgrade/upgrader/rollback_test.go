@@ -0,0 +1,340 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openebs/upgrade/pkg/upgrade/patch"
+)
+
+func newBackup(name, from, to string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "openebs",
+			Labels: map[string]string{
+				backupLabel: "true",
+			},
+			Annotations: map[string]string{
+				fromVersionAnnotation: from,
+				toVersionAnnotation:   to,
+			},
+		},
+		Data: data,
+	}
+}
+
+// backupOf returns a backup of the given objects taken
+// for the upgrade of the resource from 3.4.0 to 3.5.0
+func backupOf(t *testing.T, name string, objs ...backupObject) *corev1.ConfigMap {
+	data := map[string]string{}
+	for _, o := range objs {
+		raw, err := json.Marshal(o.obj)
+		if err != nil {
+			t.Fatalf("failed to marshal %s: %v", o.obj.GetName(), err)
+		}
+		data[backupKey(o.kind, o.obj.GetName())] = string(raw)
+	}
+	return newBackup(name, "3.4.0", "3.5.0", data)
+}
+
+func Test_saveBackup(t *testing.T) {
+	r := &ResourcePatch{
+		Name:             "pvc-1",
+		OpenebsNamespace: "openebs",
+		From:             "3.4.0",
+		To:               "3.5.0",
+	}
+	name := backupName("cstorVolume", r.Name)
+	tests := map[string]struct {
+		existing *corev1.ConfigMap
+		wantData bool
+	}{
+		"backup is created": {
+			wantData: true,
+		},
+		"backup of the same upgrade is retained": {
+			existing: newBackup(name, "3.4.0", "3.5.0", map[string]string{"deployment.pvc-1": "{}"}),
+			wantData: false,
+		},
+		"backup of an earlier upgrade is replaced": {
+			existing: newBackup(name, "3.3.0", "3.4.0", map[string]string{"deployment.pvc-1": "{}"}),
+			wantData: true,
+		},
+	}
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			if test.existing != nil {
+				kubeClient = fake.NewSimpleClientset(test.existing)
+			}
+			client := &Client{KubeClientset: kubeClient}
+			deploy := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pvc-1",
+					Namespace: "openebs",
+					Labels:    map[string]string{"openebs.io/version": "3.4.0"},
+					ManagedFields: []metav1.ManagedFieldsEntry{
+						{Manager: "kubectl"},
+					},
+				},
+			}
+			err := saveBackup("cstorVolume", r, client, backupObject{"Deployment", deploy})
+			if err != nil {
+				t.Fatalf("saveBackup() error = %v", err)
+			}
+			cm, err := kubeClient.CoreV1().ConfigMaps("openebs").
+				Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get backup: %v", err)
+			}
+			if cm.Labels[backupLabel] != "true" {
+				t.Errorf("saveBackup() label %s = %q, want true", backupLabel, cm.Labels[backupLabel])
+			}
+			if cm.Annotations[fromVersionAnnotation] != "3.4.0" ||
+				cm.Annotations[toVersionAnnotation] != "3.5.0" {
+				t.Errorf("saveBackup() annotations = %v, want the versions of the upgrade", cm.Annotations)
+			}
+			saved := cm.Data[backupKey("Deployment", "pvc-1")] != "{}"
+			if saved != test.wantData {
+				t.Fatalf("saveBackup() saved the deployment = %t, want %t", saved, test.wantData)
+			}
+			if !saved {
+				return
+			}
+			got := &appsv1.Deployment{}
+			err = getBackupObject(cm, "Deployment", "pvc-1", got)
+			if err != nil {
+				t.Fatalf("getBackupObject() error = %v", err)
+			}
+			if got.Labels["openebs.io/version"] != "3.4.0" {
+				t.Errorf("saveBackup() version = %q, want 3.4.0", got.Labels["openebs.io/version"])
+			}
+			if got.ManagedFields != nil {
+				t.Errorf("saveBackup() kept the managed fields %v", got.ManagedFields)
+			}
+		})
+	}
+}
+
+func Test_getBackup(t *testing.T) {
+	r := &ResourcePatch{
+		Name:             "pvc-1",
+		OpenebsNamespace: "openebs",
+	}
+	name := backupName("jivaVolume", r.Name)
+	tests := map[string]struct {
+		existing     *corev1.ConfigMap
+		wantNotFound bool
+	}{
+		"backup is found": {
+			existing: newBackup(name, "3.4.0", "3.5.0", nil),
+		},
+		"missing backup": {
+			existing:     newBackup(backupName("cstorVolume", r.Name), "3.4.0", "3.5.0", nil),
+			wantNotFound: true,
+		},
+	}
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			client := &Client{KubeClientset: fake.NewSimpleClientset(test.existing)}
+			cm, err := getBackup("jivaVolume", r, client)
+			if test.wantNotFound {
+				if !k8serror.IsNotFound(errors.Cause(err)) {
+					t.Errorf("getBackup() error = %v, want not found", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getBackup() error = %v", err)
+			}
+			if cm.Name != name {
+				t.Errorf("getBackup() = %s, want %s", cm.Name, name)
+			}
+		})
+	}
+}
+
+func Test_setRollbackVersions(t *testing.T) {
+	r := &ResourcePatch{From: "3.4.0", To: "3.5.0"}
+	setRollbackVersions(r, newBackup("backup", "3.3.0", "3.4.0", nil))
+	if r.From != "3.4.0" || r.To != "3.3.0" {
+		t.Errorf("setRollbackVersions() = %s to %s, want 3.4.0 to 3.3.0", r.From, r.To)
+	}
+}
+
+func newRollbackDeployment(version, image string) *appsv1.Deployment {
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "pvc-1-target",
+			Namespace:  "openebs",
+			Generation: 1,
+			Labels:     map[string]string{"openebs.io/version": version},
+			Annotations: map[string]string{
+				"deployment.kubernetes.io/revision": "1",
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"openebs.io/version": version},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "cstor-istgt", Image: image},
+					},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 1,
+			Replicas:           1,
+			UpdatedReplicas:    1,
+			AvailableReplicas:  1,
+		},
+	}
+}
+
+func Test_rollbackDeployment(t *testing.T) {
+	t.Parallel()
+	old := newRollbackDeployment("3.4.0", "openebs/cstor-istgt:3.4.0")
+	cm := backupOf(t, "backup", backupObject{"Deployment", old})
+	tests := map[string]struct {
+		current *appsv1.Deployment
+	}{
+		"upgraded deployment is rolled back": {
+			current: newRollbackDeployment("3.5.0", "openebs/cstor-istgt:3.5.0"),
+		},
+		"rolled back deployment is skipped": {
+			current: newRollbackDeployment("3.4.0", "openebs/cstor-istgt:3.4.0"),
+		},
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			client := fake.NewSimpleClientset(test.current)
+			d := patch.NewDeployment(patch.WithDeploymentClient(client))
+			d.Object = test.current
+			err := rollbackDeployment(context.TODO(), d, cm)
+			if err != nil {
+				t.Fatalf("rollbackDeployment() error = %v", err)
+			}
+			got, err := client.AppsV1().Deployments("openebs").
+				Get(context.TODO(), test.current.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get deployment: %v", err)
+			}
+			if got.Labels["openebs.io/version"] != "3.4.0" ||
+				got.Spec.Template.Labels["openebs.io/version"] != "3.4.0" {
+				t.Errorf("rollbackDeployment() labels = %v, template labels = %v, want 3.4.0",
+					got.Labels, got.Spec.Template.Labels)
+			}
+			if image := got.Spec.Template.Spec.Containers[0].Image; image != "openebs/cstor-istgt:3.4.0" {
+				t.Errorf("rollbackDeployment() image = %s, want openebs/cstor-istgt:3.4.0", image)
+			}
+		})
+	}
+}
+
+func newRollbackStatefulSet(version, image string) *appsv1.StatefulSet {
+	replicas := int32(1)
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "pvc-1-jiva-rep",
+			Namespace:  "openebs",
+			Generation: 1,
+			Labels:     map[string]string{"openebs.io/version": version},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type: appsv1.RollingUpdateStatefulSetStrategyType,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"openebs.io/version": version},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "jiva-replica", Image: image},
+					},
+				},
+			},
+		},
+		Status: appsv1.StatefulSetStatus{
+			ObservedGeneration: 1,
+			Replicas:           1,
+			ReadyReplicas:      1,
+			UpdatedReplicas:    1,
+		},
+	}
+}
+
+func Test_rollbackStatefulSet(t *testing.T) {
+	t.Parallel()
+	old := newRollbackStatefulSet("3.4.0", "openebs/jiva:3.4.0")
+	cm := backupOf(t, "backup", backupObject{"StatefulSet", old})
+	tests := map[string]struct {
+		current *appsv1.StatefulSet
+	}{
+		"upgraded statefulset is rolled back": {
+			current: newRollbackStatefulSet("3.5.0", "openebs/jiva:3.5.0"),
+		},
+		"rolled back statefulset is skipped": {
+			current: newRollbackStatefulSet("3.4.0", "openebs/jiva:3.4.0"),
+		},
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			client := fake.NewSimpleClientset(test.current)
+			s := patch.NewStatefulSet(patch.WithStatefulSetClient(client))
+			s.Object = test.current
+			err := rollbackStatefulSet(context.TODO(), s, cm)
+			if err != nil {
+				t.Fatalf("rollbackStatefulSet() error = %v", err)
+			}
+			got, err := client.AppsV1().StatefulSets("openebs").
+				Get(context.TODO(), test.current.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get statefulset: %v", err)
+			}
+			if got.Labels["openebs.io/version"] != "3.4.0" ||
+				got.Spec.Template.Labels["openebs.io/version"] != "3.4.0" {
+				t.Errorf("rollbackStatefulSet() labels = %v, template labels = %v, want 3.4.0",
+					got.Labels, got.Spec.Template.Labels)
+			}
+			if image := got.Spec.Template.Spec.Containers[0].Image; image != "openebs/jiva:3.4.0" {
+				t.Errorf("rollbackStatefulSet() image = %s, want openebs/jiva:3.4.0", image)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"context"
+	"time"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/openebs/upgrade/pkg/schedule"
+)
+
+const (
+	// MaintenanceWindow is the step recorded on the UpgradeTask while
+	// waiting for its maintenance window, it completes once the window
+	// is open
+	MaintenanceWindow v1Alpha1API.UpgradeStep = "MAINTENANCE_WINDOW"
+)
+
+// WaitToStart waits until the upgrade of the UpgradeTask can start:
+// the UpgradeTask is not paused and its maintenance window is open.
+// ErrUpgradeCancelled is returned if the UpgradeTask is cancelled.
+// The window is only checked before the upgrade, a started upgrade
+// is not interrupted when the window closes.
+func WaitToStart(ctx context.Context, utaskObj *v1Alpha1API.UpgradeTask, client *Client) error {
+	r := &ResourcePatch{
+		Name:             utaskObj.Name,
+		OpenebsNamespace: utaskObj.Namespace,
+		Context:          ctx,
+	}
+	latest, err := r.checkControl(utaskObj, client)
+	if err != nil {
+		return err
+	}
+	_, err = r.waitForWindow(latest, client)
+	return err
+}
+
+// waitForWindow waits until the maintenance window of the UpgradeTask
+// is open, recording the wait as a step. The windows are read from the
+// UpgradeTask while waiting, so that they can be changed or removed.
+func (r *ResourcePatch) waitForWindow(utaskObj *v1Alpha1API.UpgradeTask,
+	client *Client) (*v1Alpha1API.UpgradeTask, error) {
+	next, err := schedule.NextOpen(utaskObj.Annotations, schedule.Now())
+	if err != nil {
+		return utaskObj, errors.Wrapf(err, "invalid maintenance window of %s", utaskObj.Name)
+	}
+	if next.IsZero() {
+		return utaskObj, nil
+	}
+	klog.Infof("Upgrade of %s is waiting for the maintenance window opening at %s",
+		r.Name, next.Format(time.RFC3339))
+	interrupted := interruptedStep(utaskObj)
+	statusObj := v1Alpha1API.UpgradeDetailedStatuses{Step: MaintenanceWindow}
+	statusObj.Phase = v1Alpha1API.StepWaiting
+	statusObj.Message = "Waiting for the maintenance window opening at " + next.Format(time.RFC3339)
+	latest, err := updateUpgradeDetailedStatus(utaskObj, statusObj, r.OpenebsNamespace, client)
+	if err != nil {
+		return utaskObj, err
+	}
+	err = schedule.Wait(r.baseContext(), "maintenance window of "+latest.Name,
+		func(ctx context.Context) (map[string]string, error) {
+			obj, err := getUpgradeTask(ctx, latest, client)
+			if err != nil {
+				return nil, err
+			}
+			latest = obj
+			// a cancelled upgrade stops waiting as if it had no windows
+			if obj.Annotations[UpgradeControlAnnotation] == ControlCancel {
+				return nil, nil
+			}
+			return obj.Annotations, nil
+		},
+	)
+	if err != nil {
+		return latest, err
+	}
+	if latest.Annotations[UpgradeControlAnnotation] == ControlCancel {
+		return r.cancel(latest, client)
+	}
+	statusObj.Phase = v1Alpha1API.StepCompleted
+	statusObj.Message = "Maintenance window is open"
+	latest, err = updateUpgradeDetailedStatus(latest, statusObj, r.OpenebsNamespace, client)
+	if err != nil {
+		return utaskObj, err
+	}
+	klog.Infof("Maintenance window of %s is open", r.Name)
+	return r.resumeStep(latest, interrupted, client)
+}
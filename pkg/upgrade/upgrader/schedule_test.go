@@ -0,0 +1,147 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	openebsFakeClientset "github.com/openebs/api/v3/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openebs/upgrade/pkg/schedule"
+)
+
+func TestWaitForWindow(t *testing.T) {
+	defer func(interval time.Duration, clock func() time.Time) {
+		schedule.CheckInterval = interval
+		schedule.Now = clock
+	}(schedule.CheckInterval, schedule.Now)
+	schedule.CheckInterval = time.Millisecond
+	// 2026-10-17 is a Saturday
+	schedule.Now = func() time.Time {
+		return time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	}
+	r := &ResourcePatch{
+		Name:             "pvc-1",
+		OpenebsNamespace: "openebs",
+		From:             "3.4.0",
+		To:               "3.5.0",
+	}
+	tests := map[string]struct {
+		window string
+		// then is the annotation set once the wait is recorded
+		then         map[string]string
+		wantErr      bool
+		wantStatuses []stepPhase
+	}{
+		"no window": {
+			wantStatuses: []stepPhase{
+				{v1Alpha1API.ReplicaUpgrade, v1Alpha1API.StepWaiting},
+			},
+		},
+		"window open": {
+			window: "0 10 * * 6 4h",
+			wantStatuses: []stepPhase{
+				{v1Alpha1API.ReplicaUpgrade, v1Alpha1API.StepWaiting},
+			},
+		},
+		"invalid window": {
+			window:  "0 10 * * 4h",
+			wantErr: true,
+			wantStatuses: []stepPhase{
+				{v1Alpha1API.ReplicaUpgrade, v1Alpha1API.StepWaiting},
+			},
+		},
+		"window closed then removed": {
+			window: "0 22 * * 1-5 4h",
+			then:   map[string]string{},
+			wantStatuses: []stepPhase{
+				{v1Alpha1API.ReplicaUpgrade, v1Alpha1API.StepWaiting},
+				{MaintenanceWindow, v1Alpha1API.StepCompleted},
+				{v1Alpha1API.ReplicaUpgrade, v1Alpha1API.StepWaiting},
+			},
+		},
+		"window closed then cancelled": {
+			window: "0 22 * * 1-5 4h",
+			then: map[string]string{
+				schedule.WindowAnnotation: "0 22 * * 1-5 4h",
+				UpgradeControlAnnotation:  ControlCancel,
+			},
+			wantErr: true,
+			wantStatuses: []stepPhase{
+				{v1Alpha1API.ReplicaUpgrade, v1Alpha1API.StepWaiting},
+				{MaintenanceWindow, v1Alpha1API.StepErrored},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			utaskObj := buildUpgradeTask("cstorVolume", r)
+			if test.window != "" {
+				utaskObj.Annotations = map[string]string{schedule.WindowAnnotation: test.window}
+			}
+			status := v1Alpha1API.UpgradeDetailedStatuses{Step: v1Alpha1API.ReplicaUpgrade}
+			status.Phase = v1Alpha1API.StepWaiting
+			utaskObj.Status.UpgradeDetailedStatuses = []v1Alpha1API.UpgradeDetailedStatuses{status}
+			client := &Client{
+				OpenebsClientset: openebsFakeClientset.NewSimpleClientset(utaskObj),
+			}
+			tasks := client.OpenebsClientset.OpenebsV1alpha1().UpgradeTasks(utaskObj.Namespace)
+			if test.then != nil {
+				// change the annotations once the wait is recorded
+				go func() {
+					for {
+						obj, err := tasks.Get(context.TODO(), utaskObj.Name, metav1.GetOptions{})
+						if err != nil {
+							t.Errorf("failed to get upgradetask: %v", err)
+							return
+						}
+						l := len(obj.Status.UpgradeDetailedStatuses)
+						if obj.Status.UpgradeDetailedStatuses[l-1].Step == MaintenanceWindow {
+							obj.Annotations = test.then
+							_, err = tasks.Update(context.TODO(), obj, metav1.UpdateOptions{})
+							if err != nil {
+								t.Errorf("failed to update upgradetask: %v", err)
+							}
+							return
+						}
+						time.Sleep(time.Millisecond)
+					}
+				}()
+			}
+			_, err := r.waitForWindow(utaskObj, client)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("waitForWindow() error = %v, wantErr %v", err, test.wantErr)
+			}
+			got, err := tasks.Get(context.TODO(), utaskObj.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get upgradetask: %v", err)
+			}
+			statuses := []stepPhase{}
+			for _, s := range got.Status.UpgradeDetailedStatuses {
+				statuses = append(statuses, stepPhase{s.Step, s.Phase})
+			}
+			if !reflect.DeepEqual(statuses, test.wantStatuses) {
+				t.Errorf("waitForWindow() statuses = %v, want %v", statuses, test.wantStatuses)
+			}
+		})
+	}
+}
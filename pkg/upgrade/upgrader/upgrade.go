@@ -20,13 +20,9 @@ import (
 	"os"
 
 	openebsclientset "github.com/openebs/api/v3/pkg/client/clientset/versioned"
-	"github.com/pkg/errors"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
-)
-
-var (
-	isUpgradeTaskJob = false
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // UpgradeOptions ...
@@ -38,40 +34,31 @@ type Client struct {
 	KubeClientset kubernetes.Interface
 	// openebsclientset is a openebs custom resource package generated for custom API group.
 	OpenebsClientset openebsclientset.Interface
+	// runtimeclient is used for resources without a generated clientset
+	RuntimeClient client.Client
+	// Recorder emits the events for the steps of the upgrade,
+	// no events are emitted if it is not set
+	Recorder record.EventRecorder
 }
 
 // Upgrade ...
 type Upgrade struct {
 	UpgradeMap map[string]UpgradeOptions
+	// IsUpgradeTaskJob is set when the upgrade is
+	// executed by a job created for an UpgradeTask
+	IsUpgradeTaskJob bool
 	*Client
 }
 
-func (u *Upgrade) initClient() error {
-	cfg, err := rest.InClusterConfig()
-	if err != nil {
-		return errors.Wrap(err, "error building kubeconfig")
-	}
-	u.KubeClientset, err = kubernetes.NewForConfig(cfg)
-	if err != nil {
-		return errors.Wrap(err, "error building kubernetes clientset")
-	}
-	u.OpenebsClientset, err = openebsclientset.NewForConfig(cfg)
-	if err != nil {
-		return errors.Wrap(err, "error building openebs clientset")
-	}
-	return nil
-}
-
 // NewUpgrade ...
-func NewUpgrade() *Upgrade {
+func NewUpgrade(c *Client) *Upgrade {
 	u := &Upgrade{
 		UpgradeMap: map[string]UpgradeOptions{},
-		Client:     &Client{},
+		Client:     c,
 	}
-	u.initClient()
 	u.RegisterAll()
 	if os.Getenv("UPGRADE_TASK_LABEL") != "" {
-		isUpgradeTaskJob = true
+		u.IsUpgradeTaskJob = true
 	}
 	return u
 }
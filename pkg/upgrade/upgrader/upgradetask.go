@@ -19,6 +19,7 @@ package upgrader
 import (
 	"context"
 	"os"
+	"strings"
 
 	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
 	"github.com/pkg/errors"
@@ -26,6 +27,19 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// DefaultMaxRetries is the number of failed attempts after which
+	// an UpgradeTask is failed, same as the default backoffLimit of a Job
+	DefaultMaxRetries = 6
+	// ParentUpgradeTaskLabel is set on the UpgradeTask of a resource
+	// upgraded as part of another resource, like the cspis of a CSPC
+	ParentUpgradeTaskLabel = "openebs.io/parent-upgradetask"
+	// upgradeProgressAnnotation records the parts of the upgrade
+	// that are completed, like the cvrs of a volume or the cspis
+	// of a CSPC, so that a retry can resume from there
+	upgradeProgressAnnotation = "openebs.io/upgrade-progress"
+)
+
 func updateUpgradeDetailedStatus(utaskObj *v1Alpha1API.UpgradeTask,
 	uStatusObj v1Alpha1API.UpgradeDetailedStatuses,
 	openebsNamespace string, client *Client,
@@ -37,6 +51,8 @@ func updateUpgradeDetailedStatus(utaskObj *v1Alpha1API.UpgradeTask,
 			uStatusObj,
 		)
 	}
+	recordUpgradeEvent(utaskObj, uStatusObj, client)
+	observeUpgradeStep(utaskObj, uStatusObj)
 	uStatusObj.LastUpdatedTime = metav1.Now()
 	if uStatusObj.Phase == v1Alpha1API.StepWaiting {
 		uStatusObj.StartTime = uStatusObj.LastUpdatedTime
@@ -109,12 +125,24 @@ func getOrCreateUpgradeTask(kind string, r *ResourcePatch, client *Client) (*v1A
 		utaskObj = utaskObj1
 	}
 
+	// a task left behind by an upgrade to other versions is
+	// reset, otherwise the statuses of the previous attempts are
+	// retained and the upgrade resumes from the recorded progress
+	if utaskObj.Spec.FromVersion != r.From || utaskObj.Spec.ToVersion != r.To {
+		utaskObj.Spec.FromVersion = r.From
+		utaskObj.Spec.ToVersion = r.To
+		utaskObj.Status = v1Alpha1API.UpgradeTaskStatus{}
+		delete(utaskObj.Annotations, upgradeProgressAnnotation)
+	}
+
 	if utaskObj.Status.StartTime.IsZero() {
 		utaskObj.Status.Phase = v1Alpha1API.UpgradeStarted
 		utaskObj.Status.StartTime = metav1.Now()
 	}
 
-	utaskObj.Status.UpgradeDetailedStatuses = []v1Alpha1API.UpgradeDetailedStatuses{}
+	if utaskObj.Status.UpgradeDetailedStatuses == nil {
+		utaskObj.Status.UpgradeDetailedStatuses = []v1Alpha1API.UpgradeDetailedStatuses{}
+	}
 	utaskObj, err = client.OpenebsClientset.OpenebsV1alpha1().
 		UpgradeTasks(r.OpenebsNamespace).
 		Update(context.TODO(), utaskObj, metav1.UpdateOptions{})
@@ -124,10 +152,27 @@ func getOrCreateUpgradeTask(kind string, r *ResourcePatch, client *Client) (*v1A
 	return utaskObj, nil
 }
 
+// UpgradeTaskName returns the name of the UpgradeTask
+// recording the upgrade of the given resource
+func UpgradeTaskName(kind, name string) string {
+	switch kind {
+	case "cstorPoolCluster":
+		return "upgrade-cstor-cspc-" + name
+	case "cstorPoolInstance":
+		return "upgrade-cstor-cspi-" + name
+	case "cstorVolume":
+		return "upgrade-cstor-csi-volume-" + name
+	case "jivaVolume":
+		return "upgrade-jiva-csi-volume-" + name
+	}
+	return ""
+}
+
 func buildUpgradeTask(kind string, r *ResourcePatch) *v1Alpha1API.UpgradeTask {
 	// TODO builder
 	utaskObj := &v1Alpha1API.UpgradeTask{
 		ObjectMeta: metav1.ObjectMeta{
+			Name:      UpgradeTaskName(kind, r.Name),
 			Namespace: r.OpenebsNamespace,
 		},
 		Spec: v1Alpha1API.UpgradeTaskSpec{
@@ -142,22 +187,25 @@ func buildUpgradeTask(kind string, r *ResourcePatch) *v1Alpha1API.UpgradeTask {
 		},
 	}
 	switch kind {
+	case "cstorPoolCluster":
+		utaskObj.Spec.ResourceSpec = v1Alpha1API.ResourceSpec{
+			CStorPoolCluster: &v1Alpha1API.CStorPoolCluster{
+				CSPCName: r.Name,
+			},
+		}
 	case "cstorPoolInstance":
-		utaskObj.Name = "upgrade-cstor-cspi-" + r.Name
 		utaskObj.Spec.ResourceSpec = v1Alpha1API.ResourceSpec{
 			CStorPoolInstance: &v1Alpha1API.CStorPoolInstance{
 				CSPIName: r.Name,
 			},
 		}
 	case "cstorVolume":
-		utaskObj.Name = "upgrade-cstor-csi-volume-" + r.Name
 		utaskObj.Spec.ResourceSpec = v1Alpha1API.ResourceSpec{
 			CStorVolume: &v1Alpha1API.CStorVolume{
 				PVName: r.Name,
 			},
 		}
 	case "jivaVolume":
-		utaskObj.Name = "upgrade-jiva-csi-volume-" + r.Name
 		utaskObj.Spec.ResourceSpec = v1Alpha1API.ResourceSpec{
 			JivaVolume: &v1Alpha1API.JivaVolume{
 				PVName: r.Name,
@@ -167,6 +215,78 @@ func buildUpgradeTask(kind string, r *ResourcePatch) *v1Alpha1API.UpgradeTask {
 	return utaskObj
 }
 
+// setParentUpgradeTask links the UpgradeTask to the parent UpgradeTask
+// using a label and an owner reference, so that the child tasks can be
+// listed with the parent and are cleaned up along with it
+func setParentUpgradeTask(utaskObj, parent *v1Alpha1API.UpgradeTask,
+	openebsNamespace string, client *Client) (*v1Alpha1API.UpgradeTask, error) {
+	if utaskObj.Labels[ParentUpgradeTaskLabel] == parent.Name {
+		return utaskObj, nil
+	}
+	// the given task is returned as is if it cannot be updated
+	newUtask := utaskObj.DeepCopy()
+	if newUtask.Labels == nil {
+		newUtask.Labels = map[string]string{}
+	}
+	newUtask.Labels[ParentUpgradeTaskLabel] = parent.Name
+	newUtask.OwnerReferences = append(newUtask.OwnerReferences, metav1.OwnerReference{
+		APIVersion: v1Alpha1API.SchemeGroupVersion.String(),
+		Kind:       "UpgradeTask",
+		Name:       parent.Name,
+		UID:        parent.UID,
+	})
+	newUtask, err := client.OpenebsClientset.OpenebsV1alpha1().
+		UpgradeTasks(openebsNamespace).
+		Update(context.TODO(), newUtask, metav1.UpdateOptions{})
+	if err != nil {
+		return utaskObj, errors.Wrapf(err, "failed to link upgradetask to %s", parent.Name)
+	}
+	return newUtask, nil
+}
+
+// isCompleted returns true if the given part of the
+// upgrade is recorded as completed on the UpgradeTask
+func isCompleted(utaskObj *v1Alpha1API.UpgradeTask, key string) bool {
+	if utaskObj == nil {
+		return false
+	}
+	for _, k := range strings.Split(utaskObj.Annotations[upgradeProgressAnnotation], ",") {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// markCompleted records the given part of the upgrade
+// as completed on the UpgradeTask
+func markCompleted(utaskObj *v1Alpha1API.UpgradeTask, key string,
+	openebsNamespace string, client *Client) (*v1Alpha1API.UpgradeTask, error) {
+	if utaskObj == nil {
+		return nil, errors.Errorf("failed to record %s as completed: missing upgradetask", key)
+	}
+	if isCompleted(utaskObj, key) {
+		return utaskObj, nil
+	}
+	// the given task is returned as is if it cannot be updated
+	newUtask := utaskObj.DeepCopy()
+	if newUtask.Annotations == nil {
+		newUtask.Annotations = map[string]string{}
+	}
+	progress := newUtask.Annotations[upgradeProgressAnnotation]
+	if progress != "" {
+		progress += ","
+	}
+	newUtask.Annotations[upgradeProgressAnnotation] = progress + key
+	newUtask, err := client.OpenebsClientset.OpenebsV1alpha1().
+		UpgradeTasks(openebsNamespace).
+		Update(context.TODO(), newUtask, metav1.UpdateOptions{})
+	if err != nil {
+		return utaskObj, errors.Wrapf(err, "failed to record %s as completed", key)
+	}
+	return newUtask, nil
+}
+
 func getBackoffLimit(openebsNamespace string, client *Client) (int, error) {
 	podName := os.Getenv("POD_NAME")
 	podObj, err := client.KubeClientset.CoreV1().Pods(openebsNamespace).
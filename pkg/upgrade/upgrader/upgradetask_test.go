@@ -0,0 +1,170 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrader
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	v1Alpha1API "github.com/openebs/api/v3/pkg/apis/openebs.io/v1alpha1"
+	openebsFakeClientset "github.com/openebs/api/v3/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func Test_setParentUpgradeTask(t *testing.T) {
+	r := &ResourcePatch{
+		Name:             "cspc-1",
+		OpenebsNamespace: "openebs",
+		From:             "3.4.0",
+		To:               "3.5.0",
+	}
+	parent := buildUpgradeTask("cstorPoolCluster", r)
+	parent.UID = types.UID("parent-uid")
+	if parent.Name != "upgrade-cstor-cspc-cspc-1" ||
+		parent.Spec.ResourceSpec.CStorPoolCluster.CSPCName != "cspc-1" {
+		t.Fatalf("buildUpgradeTask() = %v, want the cspc upgradetask", parent)
+	}
+	r.Name = "cspi-1"
+	child := buildUpgradeTask("cstorPoolInstance", r)
+	client := &Client{
+		OpenebsClientset: openebsFakeClientset.NewSimpleClientset(parent, child),
+	}
+	// linking twice should not add a second owner reference
+	for i := 0; i < 2; i++ {
+		var err error
+		child, err = setParentUpgradeTask(child, parent, "openebs", client)
+		if err != nil {
+			t.Fatalf("setParentUpgradeTask() error = %v", err)
+		}
+	}
+	if child.Labels[ParentUpgradeTaskLabel] != parent.Name {
+		t.Errorf("setParentUpgradeTask() label = %q, want %q",
+			child.Labels[ParentUpgradeTaskLabel], parent.Name)
+	}
+	want := []metav1.OwnerReference{
+		{
+			APIVersion: v1Alpha1API.SchemeGroupVersion.String(),
+			Kind:       "UpgradeTask",
+			Name:       parent.Name,
+			UID:        parent.UID,
+		},
+	}
+	if !reflect.DeepEqual(child.OwnerReferences, want) {
+		t.Errorf("setParentUpgradeTask() ownerReferences = %v, want %v",
+			child.OwnerReferences, want)
+	}
+}
+
+func Test_getOrCreateUpgradeTask(t *testing.T) {
+	tests := []struct {
+		name         string
+		from, to     string
+		wantStatuses int
+		wantResumed  bool
+	}{
+		{
+			name:         "retry of the same upgrade",
+			from:         "3.4.0",
+			to:           "3.5.0",
+			wantStatuses: 1,
+			wantResumed:  true,
+		},
+		{
+			name:         "upgrade to another version",
+			from:         "3.5.0",
+			to:           "3.6.0",
+			wantStatuses: 0,
+			wantResumed:  false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &ResourcePatch{
+				Name:             "pv-1",
+				OpenebsNamespace: "openebs",
+				From:             "3.4.0",
+				To:               "3.5.0",
+			}
+			existing := buildUpgradeTask("cstorVolume", r)
+			existing.Status.UpgradeDetailedStatuses = []v1Alpha1API.UpgradeDetailedStatuses{
+				{
+					Step:   v1Alpha1API.ReplicaUpgrade,
+					Status: v1Alpha1API.Status{Phase: v1Alpha1API.StepErrored},
+				},
+			}
+			client := &Client{
+				OpenebsClientset: openebsFakeClientset.NewSimpleClientset(existing),
+			}
+			utaskObj, err := markCompleted(existing, "cvr/pv-1-cspi-a", "openebs", client)
+			if err != nil {
+				t.Fatalf("markCompleted() error = %v", err)
+			}
+			utaskObj, err = markCompleted(utaskObj, "cvr/pv-1-cspi-b", "openebs", client)
+			if err != nil {
+				t.Fatalf("markCompleted() error = %v", err)
+			}
+			r.From, r.To = test.from, test.to
+			utaskObj, err = getOrCreateUpgradeTask("cstorVolume", r, client)
+			if err != nil {
+				t.Fatalf("getOrCreateUpgradeTask() error = %v", err)
+			}
+			if len(utaskObj.Status.UpgradeDetailedStatuses) != test.wantStatuses {
+				t.Errorf("getOrCreateUpgradeTask() statuses = %v, want %d",
+					utaskObj.Status.UpgradeDetailedStatuses, test.wantStatuses)
+			}
+			for _, key := range []string{"cvr/pv-1-cspi-a", "cvr/pv-1-cspi-b"} {
+				if isCompleted(utaskObj, key) != test.wantResumed {
+					t.Errorf("isCompleted(%s) = %v, want %v", key, !test.wantResumed, test.wantResumed)
+				}
+			}
+			if isCompleted(utaskObj, "cvr/pv-1") {
+				t.Errorf("isCompleted(cvr/pv-1) = true, want false")
+			}
+		})
+	}
+}
+
+func Test_upgradeTaskUpdateErrors(t *testing.T) {
+	r := &ResourcePatch{
+		Name:             "cspi-1",
+		OpenebsNamespace: "openebs",
+		From:             "3.4.0",
+		To:               "3.5.0",
+	}
+	utaskObj := buildUpgradeTask("cstorPoolInstance", r)
+	parent := buildUpgradeTask("cstorPoolCluster", r)
+	clientset := openebsFakeClientset.NewSimpleClientset(utaskObj)
+	clientset.PrependReactor("update", "upgradetasks",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, fmt.Errorf("conflict")
+		})
+	client := &Client{OpenebsClientset: clientset}
+	// the task is returned unchanged along with the error so
+	// that the callers ignoring the error keep a valid task
+	got, err := markCompleted(utaskObj, "cvr/pv-1-cspi-1", "openebs", client)
+	if err == nil || got != utaskObj || isCompleted(got, "cvr/pv-1-cspi-1") {
+		t.Errorf("markCompleted() = %v, %v, want the given task and an error", got, err)
+	}
+	got, err = setParentUpgradeTask(utaskObj, parent, "openebs", client)
+	if err == nil || got != utaskObj || got.Labels[ParentUpgradeTaskLabel] != "" {
+		t.Errorf("setParentUpgradeTask() = %v, %v, want the given task and an error", got, err)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"context"
+
+	"github.com/openebs/api/v3/pkg/apis/types"
+	jv "github.com/openebs/jiva-operator/pkg/apis/openebs/v1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/openebs/upgrade/pkg/generate"
+	"github.com/openebs/upgrade/pkg/kubeclient"
+)
+
+// operators maps the resource kinds to the
+// component name of the operator managing them
+var operators = map[string]string{
+	"cstorPoolCluster":  "cspc-operator",
+	"cstorPoolInstance": "cspc-operator",
+	"cstorVolume":       "cvc-operator",
+	"jivaVolume":        "jiva-operator",
+}
+
+// CurrentVersion returns the version the given resource is running,
+// read from its version details or else from its version label
+func CurrentVersion(kind, name, openebsNamespace string,
+	clientset *kubeclient.Clientset) (string, error) {
+	var current string
+	var objLabels map[string]string
+	switch kind {
+	case "cstorPoolCluster":
+		cspc, err := clientset.OpenebsClientset.CstorV1().CStorPoolClusters(openebsNamespace).
+			Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get cspc %s", name)
+		}
+		current, objLabels = cspc.VersionDetails.Status.Current, cspc.Labels
+	case "cstorPoolInstance":
+		cspi, err := clientset.OpenebsClientset.CstorV1().CStorPoolInstances(openebsNamespace).
+			Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get cspi %s", name)
+		}
+		current, objLabels = cspi.VersionDetails.Status.Current, cspi.Labels
+	case "cstorVolume":
+		// the CVC is patched last, once the target and the CV are
+		// upgraded, so that a failed upgrade is resumed from its version
+		cvc, err := clientset.OpenebsClientset.CstorV1().CStorVolumeConfigs(openebsNamespace).
+			Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get cstorvolumeconfig %s", name)
+		}
+		current, objLabels = cvc.VersionDetails.Status.Current, cvc.Labels
+	case "jivaVolume":
+		jvObj := &jv.JivaVolume{}
+		err := clientset.RuntimeClient.Get(context.TODO(),
+			k8stypes.NamespacedName{Name: name, Namespace: openebsNamespace}, jvObj)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get jivavolume %s", name)
+		}
+		current, objLabels = jvObj.VersionDetails.Status.Current, jvObj.Labels
+	default:
+		return "", errors.Errorf("version detection is not supported for %s", kind)
+	}
+	if current == "" {
+		current = objLabels[types.OpenEBSVersionLabelKey]
+	}
+	if current == "" {
+		return "", errors.Errorf("failed to find the version of %s %s", kind, name)
+	}
+	return current, nil
+}
+
+// OperatorComponent returns the component name of the
+// operator managing the given kind of resources
+func OperatorComponent(kind string) (string, error) {
+	componentName, ok := operators[kind]
+	if !ok {
+		return "", errors.Errorf("version detection is not supported for %s", kind)
+	}
+	return componentName, nil
+}
+
+// OperatorVersion returns the version of the operator managing the
+// given kind of resources, all the operator pods should be at the same
+// version as the resources cannot be upgraded during an operator rollout
+func OperatorVersion(kind, openebsNamespace string,
+	clientset *kubeclient.Clientset) (string, error) {
+	componentName, err := OperatorComponent(kind)
+	if err != nil {
+		return "", err
+	}
+	operator, err := generate.GetOperator(clientset.KubeClientset, openebsNamespace, componentName)
+	if err != nil {
+		return "", err
+	}
+	return operator.Version, nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/openebs/api/v3/pkg/apis/types"
+	openebsFakeClientset "github.com/openebs/api/v3/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openebs/upgrade/pkg/kubeclient"
+)
+
+func newOperator(name, component, version string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "openebs",
+			Labels: map[string]string{
+				"openebs.io/component-name":  component,
+				types.OpenEBSVersionLabelKey: version,
+			},
+		},
+	}
+}
+
+func TestCurrentVersion(t *testing.T) {
+	labelled := newCVC("pv2", "")
+	labelled.Labels[types.OpenEBSVersionLabelKey] = "3.3.0"
+	clientset := &kubeclient.Clientset{
+		OpenebsClientset: openebsFakeClientset.NewSimpleClientset(
+			// the CV of pv1 is reconciled but the upgrade
+			// failed before its CVC was patched
+			newCV("pv1", "3.5.0", nil),
+			newCVC("pv1", "3.4.0"),
+			labelled,
+			newCVC("pv3", ""),
+		),
+	}
+	tests := []struct {
+		name    string
+		kind    string
+		volume  string
+		want    string
+		wantErr bool
+	}{
+		{name: "version details", kind: "cstorVolume", volume: "pv1", want: "3.4.0"},
+		{name: "version label", kind: "cstorVolume", volume: "pv2", want: "3.3.0"},
+		{name: "no version", kind: "cstorVolume", volume: "pv3", wantErr: true},
+		{name: "missing volume", kind: "cstorVolume", volume: "pv4", wantErr: true},
+		{name: "unknown kind", kind: "cstorVolumeReplica", volume: "pv1", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := CurrentVersion(test.kind, test.volume, "openebs", clientset)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("CurrentVersion() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("CurrentVersion() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestOperatorVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		pods    []runtime.Object
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "cvc operator",
+			kind: "cstorVolume",
+			pods: []runtime.Object{
+				newOperator("cvc-1", "cvc-operator", "3.5.0"),
+				newOperator("cspc-1", "cspc-operator", "3.4.0"),
+			},
+			want: "3.5.0",
+		},
+		{
+			name: "cspc operator for pool instances",
+			kind: "cstorPoolInstance",
+			pods: []runtime.Object{
+				newOperator("cspc-1", "cspc-operator", "3.4.0"),
+			},
+			want: "3.4.0",
+		},
+		{
+			name: "operator rollout in progress",
+			kind: "jivaVolume",
+			pods: []runtime.Object{
+				newOperator("jiva-1", "jiva-operator", "3.4.0"),
+				newOperator("jiva-2", "jiva-operator", "3.5.0"),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "operator missing",
+			kind:    "cstorPoolCluster",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientset := &kubeclient.Clientset{
+				KubeClientset: fake.NewSimpleClientset(test.pods...),
+			}
+			got, err := OperatorVersion(test.kind, "openebs", clientset)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("OperatorVersion() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("OperatorVersion() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
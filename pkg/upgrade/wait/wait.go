@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	k8swait "k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/openebs/upgrade/pkg/metrics"
+)
+
+// ConditionFunc returns true once the condition being waited
+// for is met, an error stops the wait immediately
+type ConditionFunc func(ctx context.Context) (bool, error)
+
+// For checks the condition after every interval until it is met, it
+// returns an error or the context is done. A non zero timeout further
+// bounds the wait. The first check is made after the first interval.
+// The description is of the form "<condition> of <object>", the time
+// spent waiting is recorded in the metrics under the condition.
+func For(ctx context.Context, desc string, interval, timeout time.Duration,
+	condition ConditionFunc) (err error) {
+	start := time.Now()
+	defer func() {
+		metrics.ObserveWait(metrics.WaitCondition(desc), time.Since(start), err)
+	}()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	var condErr error
+	err = k8swait.PollUntilWithContext(ctx, interval,
+		func(ctx context.Context) (bool, error) {
+			done, err := condition(ctx)
+			// keep the error returned by the condition to
+			// differentiate it from the context expiry
+			condErr = err
+			return done, err
+		},
+	)
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return contextError(ctx, desc)
+	}
+	if condErr != nil {
+		return condErr
+	}
+	return err
+}
+
+// contextError returns the error explaining why the wait was stopped
+func contextError(ctx context.Context, desc string) error {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return errors.Errorf("timed out waiting for %s", desc)
+	case context.Canceled:
+		return errors.Errorf("cancelled while waiting for %s", desc)
+	}
+	return errors.Errorf("stopped waiting for %s", desc)
+}
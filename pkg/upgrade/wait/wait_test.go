@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       func() (context.Context, context.CancelFunc)
+		timeout   time.Duration
+		condition ConditionFunc
+		wantErr   string
+	}{
+		{
+			name: "condition met",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			condition: func(ctx context.Context) (bool, error) {
+				return true, nil
+			},
+		},
+		{
+			name: "condition failed",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			condition: func(ctx context.Context) (bool, error) {
+				return false, errors.New("failed to reconcile")
+			},
+			wantErr: "failed to reconcile",
+		},
+		{
+			name: "timeout expired",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			timeout: 20 * time.Millisecond,
+			condition: func(ctx context.Context) (bool, error) {
+				return false, nil
+			},
+			wantErr: "timed out waiting for test",
+		},
+		{
+			name: "parent deadline expired",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			timeout: time.Hour,
+			condition: func(ctx context.Context) (bool, error) {
+				return false, nil
+			},
+			wantErr: "timed out waiting for test",
+		},
+		{
+			name: "parent cancelled",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			condition: func(ctx context.Context) (bool, error) {
+				return false, nil
+			},
+			wantErr: "cancelled while waiting for test",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := test.ctx()
+			defer cancel()
+			err := For(ctx, "test", 5*time.Millisecond, test.timeout, test.condition)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("expected error %q, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
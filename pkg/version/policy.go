@@ -0,0 +1,322 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// Constraint restricts the versions matched by a rule, for example
+// ">=2.0.0" or "<3.0.0". Comma separated constraints must all hold
+// and an empty constraint matches every version.
+type Constraint string
+
+// Hop matches an upgrade path by its from and to versions
+type Hop struct {
+	From Constraint `json:"from,omitempty"`
+	To   Constraint `json:"to,omitempty"`
+}
+
+// ForbiddenHop is an upgrade path that is not supported
+type ForbiddenHop struct {
+	Hop    `json:",inline"`
+	Reason string `json:"reason"`
+}
+
+// RequiredIntermediate is an upgrade path that has to go
+// through the given version
+type RequiredIntermediate struct {
+	Hop `json:",inline"`
+	Via string `json:"via"`
+}
+
+// Policy has the rules used to validate an upgrade path
+type Policy struct {
+	// MinFromVersion is the oldest version that can be upgraded
+	MinFromVersion string `json:"minFromVersion,omitempty"`
+	// ForbiddenHops are the upgrade paths that are not supported
+	ForbiddenHops []ForbiddenHop `json:"forbiddenHops,omitempty"`
+	// RequiredIntermediates are the upgrade paths that have
+	// to be done in more than one upgrade
+	RequiredIntermediates []RequiredIntermediate `json:"requiredIntermediates,omitempty"`
+	// MinMigrationVersion is the oldest version the pools and
+	// volumes can be migrated to
+	MinMigrationVersion string `json:"minMigrationVersion,omitempty"`
+}
+
+// DefaultPolicy is the policy for the supported upgrade paths
+var DefaultPolicy = Policy{
+	MinFromVersion:      "1.10.0",
+	MinMigrationVersion: "1.12.0",
+}
+
+// PathError explains why an upgrade path is rejected
+type PathError struct {
+	From, To string
+	Reason   string
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("upgrade from %s to %s is not supported: %s", e.From, e.To, e.Reason)
+}
+
+// Parse parses the given semantic version, including
+// the pre-release and build metadata if present
+func Parse(v string) (*utilversion.Version, error) {
+	parsed, err := utilversion.ParseSemantic(strings.TrimSpace(v))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid version %q", v)
+	}
+	return parsed, nil
+}
+
+// Validate checks that the upgrade path from the given
+// version to the given version is allowed by the policy
+func (p Policy) Validate(from, to string) error {
+	reject := func(format string, args ...interface{}) error {
+		return &PathError{From: from, To: to, Reason: fmt.Sprintf(format, args...)}
+	}
+	fromVersion, err := Parse(from)
+	if err != nil {
+		return reject("%v", err)
+	}
+	toVersion, err := Parse(to)
+	if err != nil {
+		return reject("%v", err)
+	}
+	if p.MinFromVersion != "" {
+		minVersion, err := Parse(p.MinFromVersion)
+		if err != nil {
+			return errors.Wrap(err, "invalid upgrade policy")
+		}
+		// release candidates of the oldest version are supported too
+		if fromVersion.WithPreRelease("").LessThan(minVersion) {
+			return reject("the oldest version that can be upgraded is %s", p.MinFromVersion)
+		}
+	}
+	if toVersion.LessThan(fromVersion) {
+		return reject("downgrade is not supported, use the rollback command to revert a failed upgrade")
+	}
+	for _, h := range p.ForbiddenHops {
+		ok, err := h.matches(fromVersion, toVersion)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return reject("%s", h.Reason)
+		}
+	}
+	for _, h := range p.RequiredIntermediates {
+		ok, err := h.matches(fromVersion, toVersion)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		via, err := Parse(h.Via)
+		if err != nil {
+			return errors.Wrap(err, "invalid upgrade policy")
+		}
+		if fromVersion.LessThan(via) && via.LessThan(toVersion) {
+			return reject("upgrade to %s first", h.Via)
+		}
+	}
+	return nil
+}
+
+func (h Hop) matches(from, to *utilversion.Version) (bool, error) {
+	ok, err := h.From.Check(from)
+	if err != nil || !ok {
+		return false, err
+	}
+	return h.To.Check(to)
+}
+
+// Check returns true if the version satisfies the constraint
+func (c Constraint) Check(v *utilversion.Version) (bool, error) {
+	for _, part := range strings.Split(string(c), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		op, value := splitConstraint(part)
+		other, err := Parse(value)
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid constraint %q", c)
+		}
+		var ok bool
+		switch op {
+		case "<":
+			ok = v.LessThan(other)
+		case "<=":
+			ok = !other.LessThan(v)
+		case ">":
+			ok = other.LessThan(v)
+		case ">=":
+			ok = v.AtLeast(other)
+		case "=":
+			ok = !v.LessThan(other) && !other.LessThan(v)
+		case "!=":
+			ok = v.LessThan(other) || other.LessThan(v)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// splitConstraint returns the operator and the version of
+// the constraint, a constraint without operator is an equality
+func splitConstraint(part string) (string, string) {
+	for _, op := range []string{"<=", ">=", "!=", "<", ">", "="} {
+		if strings.HasPrefix(part, op) {
+			return op, part[len(op):]
+		}
+	}
+	return "=", part
+}
+
+// validate checks that every version of the constraint is valid
+func (c Constraint) validate() error {
+	for _, part := range strings.Split(string(c), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		_, value := splitConstraint(part)
+		if _, err := Parse(value); err != nil {
+			return errors.Wrapf(err, "invalid constraint %q", c)
+		}
+	}
+	return nil
+}
+
+// currentVersion returns the version of this binary, it is nil
+// with a warning when the binary is a development build without
+// a semantic version, like a branch build or one without ldflags
+func currentVersion() *utilversion.Version {
+	current := GetVersion()
+	parsed, err := Parse(current)
+	if err != nil {
+		klog.Warningf("Skipping the version checks of the binary: %v", err)
+		return nil
+	}
+	return parsed
+}
+
+// ValidateTarget checks that the given version is the one this
+// binary upgrades to. The pre-release and build metadata are not
+// compared, so that custom and release candidate images can be used.
+func ValidateTarget(to string) error {
+	toVersion, err := Parse(to)
+	if err != nil {
+		return err
+	}
+	current := currentVersion()
+	if current == nil {
+		return nil
+	}
+	if !sameRelease(current, toVersion) {
+		return errors.Errorf("the upgrade image can only upgrade to %s, use the image with tag %s to upgrade to %s",
+			current, to, to)
+	}
+	return nil
+}
+
+// ValidateUpgradePath checks the upgrade path against
+// the policy and the version of the binary
+func (p Policy) ValidateUpgradePath(from, to string) error {
+	err := p.Validate(from, to)
+	if err != nil {
+		return err
+	}
+	return ValidateTarget(to)
+}
+
+// ValidateMigrationTarget checks that the version of this
+// binary is one the resources can be migrated to
+func (p Policy) ValidateMigrationTarget() error {
+	if p.MinMigrationVersion == "" {
+		return nil
+	}
+	minVersion, err := Parse(p.MinMigrationVersion)
+	if err != nil {
+		return errors.Wrap(err, "invalid upgrade policy")
+	}
+	current := currentVersion()
+	if current == nil {
+		return nil
+	}
+	if current.WithPreRelease("").LessThan(minVersion) {
+		return errors.Errorf("migration to %s is not supported: the oldest version that can be migrated to is %s",
+			current, p.MinMigrationVersion)
+	}
+	return nil
+}
+
+// ParsePolicy parses the yaml upgrade policy, unknown
+// fields and invalid versions or constraints are rejected
+func ParsePolicy(data []byte) (Policy, error) {
+	var p Policy
+	err := yaml.UnmarshalStrict(data, &p)
+	if err != nil {
+		return Policy{}, errors.Wrap(err, "invalid upgrade policy")
+	}
+	for _, v := range []string{p.MinFromVersion, p.MinMigrationVersion} {
+		if v == "" {
+			continue
+		}
+		if _, err := Parse(v); err != nil {
+			return Policy{}, errors.Wrap(err, "invalid upgrade policy")
+		}
+	}
+	hops := []Hop{}
+	for i, h := range p.ForbiddenHops {
+		if h.Reason == "" {
+			return Policy{}, errors.Errorf("invalid upgrade policy: forbidden hop %d has no reason", i)
+		}
+		hops = append(hops, h.Hop)
+	}
+	for i, h := range p.RequiredIntermediates {
+		if _, err := Parse(h.Via); err != nil {
+			return Policy{}, errors.Wrapf(err, "invalid upgrade policy: required intermediate %d", i)
+		}
+		hops = append(hops, h.Hop)
+	}
+	for _, h := range hops {
+		for _, c := range []Constraint{h.From, h.To} {
+			if err := c.validate(); err != nil {
+				return Policy{}, errors.Wrap(err, "invalid upgrade policy")
+			}
+		}
+	}
+	return p, nil
+}
+
+// sameRelease returns true if both the versions are of the same
+// release, ignoring the pre-release and build metadata
+func sameRelease(a, b *utilversion.Version) bool {
+	return a.Major() == b.Major() && a.Minor() == b.Minor() && a.Patch() == b.Patch()
+}
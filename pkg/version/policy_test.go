@@ -0,0 +1,267 @@
+/*
+Copyright 2026 The OpenEBS Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"reflect"
+	"testing"
+
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	policy := Policy{
+		MinFromVersion: "1.10.0",
+		ForbiddenHops: []ForbiddenHop{
+			{
+				Hop:    Hop{From: "2.5.0"},
+				Reason: "2.5.0 volumes cannot be upgraded",
+			},
+		},
+		RequiredIntermediates: []RequiredIntermediate{
+			{
+				Hop: Hop{From: "<2.0.0", To: ">=3.0.0"},
+				Via: "2.12.2",
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		from, to   string
+		wantReason string
+	}{
+		{
+			name: "supported path",
+			from: "2.12.2",
+			to:   "3.5.0",
+		},
+		{
+			name: "release candidate and build metadata",
+			from: "3.4.0-RC1",
+			to:   "3.5.0+build.1",
+		},
+		{
+			name: "release candidate of the oldest version",
+			from: "1.10.0-RC2",
+			to:   "2.12.2",
+		},
+		{
+			name: "same version",
+			from: "3.5.0",
+			to:   "3.5.0",
+		},
+		{
+			name:       "invalid version",
+			from:       "3.4",
+			to:         "3.5.0",
+			wantReason: `invalid version "3.4": illegal version string "3.4"`,
+		},
+		{
+			name:       "older than the minimum version",
+			from:       "1.9.0",
+			to:         "2.12.2",
+			wantReason: "the oldest version that can be upgraded is 1.10.0",
+		},
+		{
+			name:       "downgrade",
+			from:       "3.5.0",
+			to:         "3.5.0-RC1",
+			wantReason: "downgrade is not supported, use the rollback command to revert a failed upgrade",
+		},
+		{
+			name:       "forbidden hop",
+			from:       "2.5.0",
+			to:         "3.5.0",
+			wantReason: "2.5.0 volumes cannot be upgraded",
+		},
+		{
+			name:       "required intermediate",
+			from:       "1.12.0",
+			to:         "3.5.0",
+			wantReason: "upgrade to 2.12.2 first",
+		},
+		{
+			name: "up to the intermediate",
+			from: "1.12.0",
+			to:   "2.12.2",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := policy.Validate(test.from, test.to)
+			if test.wantReason == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			pathErr, ok := err.(*PathError)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want a PathError", err)
+			}
+			if pathErr.Reason != test.wantReason {
+				t.Errorf("Validate() reason = %q, want %q", pathErr.Reason, test.wantReason)
+			}
+		})
+	}
+}
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint Constraint
+		version    string
+		want       bool
+		wantErr    bool
+	}{
+		{name: "empty", constraint: "", version: "1.0.0", want: true},
+		{name: "equal", constraint: "3.5.0", version: "3.5.0", want: true},
+		{name: "not equal", constraint: "!=3.5.0", version: "3.5.0", want: false},
+		{name: "range", constraint: ">=2.0.0, <3.0.0", version: "2.12.2", want: true},
+		{name: "out of range", constraint: ">=2.0.0, <3.0.0", version: "3.0.0", want: false},
+		{name: "pre-release", constraint: "<3.0.0", version: "3.0.0-RC1", want: true},
+		{name: "invalid", constraint: ">=abc", version: "3.0.0", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.constraint.Check(utilversion.MustParseSemantic(test.version))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("Check() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateTarget(t *testing.T) {
+	defer func(v string) { Version = v }(Version)
+	tests := []struct {
+		name    string
+		version string
+		to      string
+		wantErr bool
+	}{
+		{name: "release", version: "3.5.0-dev", to: "3.5.0"},
+		{name: "custom tag", version: "3.5.0-dev", to: "3.5.0-ee"},
+		{name: "other release", version: "3.5.0-dev", to: "3.4.0", wantErr: true},
+		{name: "invalid", version: "3.5.0-dev", to: "latest", wantErr: true},
+		{name: "development build", version: "develop-dev", to: "3.4.0"},
+		{name: "build without version", version: "", to: "3.4.0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			Version = test.version
+			err := ValidateTarget(test.to)
+			if (err != nil) != test.wantErr {
+				t.Errorf("ValidateTarget() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMigrationTarget(t *testing.T) {
+	defer func(v string) { Version = v }(Version)
+	tests := []struct {
+		name    string
+		version string
+		wantErr bool
+	}{
+		{name: "supported", version: "3.5.0"},
+		{name: "release candidate", version: "1.12.0-RC1"},
+		{name: "too old", version: "1.11.0", wantErr: true},
+		{name: "development build", version: "develop-dev"},
+		{name: "build without version", version: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			Version = test.version
+			err := DefaultPolicy.ValidateMigrationTarget()
+			if (err != nil) != test.wantErr {
+				t.Errorf("ValidateMigrationTarget() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	tests := map[string]struct {
+		data    string
+		want    Policy
+		wantErr bool
+	}{
+		"full policy": {
+			data: `
+minFromVersion: 2.0.0
+minMigrationVersion: 2.1.0
+forbiddenHops:
+- from: 2.5.0
+  reason: 2.5.0 volumes cannot be upgraded
+requiredIntermediates:
+- from: <2.0.0
+  to: '>=3.0.0'
+  via: 2.12.2
+`,
+			want: Policy{
+				MinFromVersion:      "2.0.0",
+				MinMigrationVersion: "2.1.0",
+				ForbiddenHops: []ForbiddenHop{
+					{Hop: Hop{From: "2.5.0"}, Reason: "2.5.0 volumes cannot be upgraded"},
+				},
+				RequiredIntermediates: []RequiredIntermediate{
+					{Hop: Hop{From: "<2.0.0", To: ">=3.0.0"}, Via: "2.12.2"},
+				},
+			},
+		},
+		"empty policy": {
+			data: "{}",
+			want: Policy{},
+		},
+		"unknown field": {
+			data:    "minVersion: 2.0.0",
+			wantErr: true,
+		},
+		"invalid min version": {
+			data:    "minFromVersion: latest",
+			wantErr: true,
+		},
+		"invalid constraint": {
+			data:    "forbiddenHops:\n- to: '>=3.x'\n  reason: unsupported",
+			wantErr: true,
+		},
+		"forbidden hop without reason": {
+			data:    "forbiddenHops:\n- from: 2.5.0",
+			wantErr: true,
+		},
+		"invalid intermediate": {
+			data:    "requiredIntermediates:\n- from: <2.0.0\n  via: next",
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParsePolicy([]byte(test.data))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParsePolicy() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParsePolicy() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}